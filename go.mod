@@ -11,10 +11,11 @@ require (
 	github.com/openshift/client-go v0.0.0-20210112165513-ebc401615f47
 	github.com/operator-framework/api v0.5.3
 	github.com/operator-framework/operator-lifecycle-manager v0.17.0
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/segmentio/kafka-go v0.3.10
 	k8s.io/api v0.20.1
 	k8s.io/apimachinery v0.20.1
 	k8s.io/client-go v0.20.1
 	sigs.k8s.io/controller-runtime v0.8.0
-
+	sigs.k8s.io/yaml v1.2.0
 )
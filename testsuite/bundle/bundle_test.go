@@ -28,7 +28,7 @@ var _ = Describe("bundle installation", func() {
 
 func installOperator() {
 
-	kubernetesutils.CreateTestNamespace(suiteCtx.Clientset, operatorNamespace)
+	kubernetesutils.CreateTestNamespace(suiteCtx, operatorNamespace)
 
 	log.Info("Installing operator")
 	if strings.HasPrefix(utils.OperatorBundlePath, "https://") {
@@ -66,11 +66,11 @@ func installOperator() {
 	}
 
 	if utils.ImagePullSecretUser != "" {
-		kubernetesutils.SetPullSecret(suiteCtx.Clientset, "apicurio-registry-operator", operatorNamespace)
+		kubernetesutils.SetPullSecret(suiteCtx, "apicurio-registry-operator", operatorNamespace)
 		kubernetescli.Execute("delete", "pod", "-l", "name=apicurio-registry-operator", "-n", operatorNamespace)
 	}
 
-	kubernetesutils.WaitForOperatorDeploymentReady(suiteCtx.Clientset, operatorNamespace, utils.OperatorDeploymentName)
+	kubernetesutils.WaitForOperatorDeploymentReady(suiteCtx, operatorNamespace, utils.OperatorDeploymentName)
 
 }
 
@@ -96,8 +96,8 @@ func uninstallOperator() {
 		kubernetescli.Execute("delete", "-n", operatorNamespace, "-f", filepath.Join(bundlePath, "service_account.yaml"))
 	}
 
-	kubernetesutils.WaitForOperatorDeploymentRemoved(suiteCtx.Clientset, operatorNamespace, utils.OperatorDeploymentName)
+	kubernetesutils.WaitForOperatorDeploymentRemoved(suiteCtx, operatorNamespace, utils.OperatorDeploymentName)
 
-	kubernetesutils.DeleteTestNamespace(suiteCtx.Clientset, operatorNamespace)
+	kubernetesutils.DeleteTestNamespace(suiteCtx, operatorNamespace)
 
 }
@@ -3,30 +3,31 @@ package olm
 import (
 	"context"
 	"errors"
-	"net/http"
-	"strconv"
-	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/operator-framework/api/pkg/operators/v1alpha1"
-	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils"
 	apicurioutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio"
-	apicurioclient "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/client"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/deploy"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/seeder"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/snapshot"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/functional"
 	kubernetesutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetes"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetescli"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/olm"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/testcase"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/timeouts"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/waiter"
 )
 
-var artifactData string = "{\"type\":\"record\",\"name\":\"price\",\"namespace\":\"com.example\",\"fields\":[{\"name\":\"symbol\",\"type\":\"string\"},{\"name\":\"price\",\"type\":\"string\"}]}"
+//seedValue seed of the dataset created before the upgrade, fixed so failures can be reproduced
+const seedValue int64 = 20210601
 
 var _ = DescribeTable("olm-upgrade",
 	func(ctx *types.TestContext) {
@@ -58,9 +59,9 @@ func executeUpgradeTest(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
 	ctx.RegistryNamespace = operatorNamespace
 
 	ctx.RegisterCleanup(func() {
-		kubernetesutils.DeleteTestNamespace(suiteCtx.Clientset, operatorNamespace)
+		kubernetesutils.DeleteTestNamespace(suiteCtx, operatorNamespace)
 	})
-	kubernetesutils.CreateTestNamespace(suiteCtx.Clientset, operatorNamespace)
+	kubernetesutils.CreateTestNamespace(suiteCtx, operatorNamespace)
 
 	//create operator group
 	const operatorGroupName string = "apicurio-registry-operator-group"
@@ -86,7 +87,7 @@ func executeUpgradeTest(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
 		ChannelName: channel,
 		ChannelCSV:  startingCSV,
 	})
-	kubernetesutils.WaitForOperatorDeploymentReady(suiteCtx.Clientset, sub.Namespace, utils.OperatorDeploymentNameOlm)
+	kubernetesutils.WaitForOperatorDeploymentReady(suiteCtx, sub.Namespace, utils.OperatorDeploymentNameOlm)
 
 	ctx.RegisterCleanup(func() {
 		olm.DeleteSubscription(suiteCtx, sub, true)
@@ -103,18 +104,18 @@ func executeUpgradeTest(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
 
 	//create artifacts on the registry
 	log.Info("Creating test artifacts")
-	registryClient := apicurioclient.NewApicurioRegistryApiClient(ctx.RegistryHost, ctx.RegistryPort, http.DefaultClient)
+	registryClient := functional.RegistryClient(ctx, nil)
 
-	for i := 1; i <= 50; i++ {
-		err := registryClient.CreateArtifact("upgrd-"+strconv.Itoa(i), apicurioclient.Avro, artifactData)
-		Expect(err).ToNot(HaveOccurred())
-		time.Sleep(1 * time.Second)
-	}
+	manifest, err := seeder.Seed(registryClient, seeder.Options{Seed: seedValue, Prefix: "upgrd", Groups: 5, Artifacts: 50})
+	Expect(err).ToNot(HaveOccurred())
+
+	log.Info("Test artifacts created", "artifacts", len(manifest.Artifacts), "versions", manifest.VersionsCount())
+
+	references := functional.CreateReferencingArtifacts(registryClient)
+	functional.VerifyReferences(registryClient, references)
 
-	artifacts, err := registryClient.ListArtifacts()
+	beforeUpgrade, err := snapshot.Take(registryClient)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(len(artifacts)).To(BeIdenticalTo(50))
-	log.Info(strconv.Itoa(len(artifacts)) + "test artifacts created")
 
 	//deploy new catalog source
 	const catalogSourceName string = "registry-upgrade-catalog"
@@ -137,18 +138,14 @@ func executeUpgradeTest(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
 	Expect(err).ToNot(HaveOccurred())
 
 	//wait for subscription to point to new CSV
-	timeout := 120 * time.Second
+	timeout := timeouts.Get(timeouts.SubscriptionUpdate)
 	log.Info("Waiting for subscription to be updated", "timeout", timeout)
-	err = wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		updatedsub, err = suiteCtx.OLMClient.OperatorsV1alpha1().Subscriptions(sub.Namespace).Get(context.TODO(), sub.Name, v1.GetOptions{})
-		if err != nil {
-			return false, err
-		}
-		if updatedsub.Status.CurrentCSV != "" && updatedsub.Status.CurrentCSV == upgradeCSV {
-			return true, nil
-		}
-		return false, nil
-	})
+	updatedsub = &v1alpha1.Subscription{}
+	record, err := suiteCtx.Waiter.Timeout(timeout).Describe("subscription "+sub.Name+" pointing to "+upgradeCSV).
+		ForObject(client.ObjectKeyFromObject(sub), updatedsub, func(obj client.Object, exists bool) (bool, error) {
+			return exists && obj.(*v1alpha1.Subscription).Status.CurrentCSV == upgradeCSV, nil
+		})
+	waiter.LogOnError(record, err)
 	kubernetescli.GetPods(sub.Namespace)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -158,30 +155,26 @@ func executeUpgradeTest(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
 	})
 
 	//wait for new csv to be created
-	timeout = 160 * time.Second
+	timeout = timeouts.Get(timeouts.CSVReady)
 	log.Info("Waiting for new csv to be created and ready", "timeout", timeout)
-	lastPhase := ""
-	err = wait.Poll(utils.MediumPollInterval, timeout, func() (bool, error) {
-		newcsv, err := suiteCtx.OLMClient.OperatorsV1alpha1().ClusterServiceVersions(sub.Namespace).Get(context.TODO(), upgradeCSV, v1.GetOptions{})
-		if err != nil {
-			if kubeerrors.IsNotFound(err) {
+	record, err = suiteCtx.Waiter.Timeout(timeout).Describe("csv "+upgradeCSV+" succeeded").
+		Summarize(func(obj runtime.Object) string {
+			return "CSV Phase " + string(obj.(*v1alpha1.ClusterServiceVersion).Status.Phase)
+		}).
+		ForObject(client.ObjectKey{Namespace: sub.Namespace, Name: upgradeCSV}, &v1alpha1.ClusterServiceVersion{}, func(obj client.Object, exists bool) (bool, error) {
+			if !exists {
 				return false, nil
 			}
-			return false, err
-		}
-		if newcsv.Status.Phase == v1alpha1.CSVPhaseFailed {
-			return false, errors.New("CSV failed")
-		}
-		if newcsv.Status.Phase == v1alpha1.CSVPhaseSucceeded {
-			log.Info("CSV Succeeded")
-			return true, nil
-		}
-		if lastPhase == "" || lastPhase != string(newcsv.Status.Phase) {
-			lastPhase = string(newcsv.Status.Phase)
-			log.Info("CSV Phase " + string(newcsv.Status.Phase))
-		}
-		return false, nil
-	})
+			switch obj.(*v1alpha1.ClusterServiceVersion).Status.Phase {
+			case v1alpha1.CSVPhaseFailed:
+				return false, errors.New("CSV failed")
+			case v1alpha1.CSVPhaseSucceeded:
+				log.Info("CSV Succeeded")
+				return true, nil
+			}
+			return false, nil
+		})
+	waiter.LogOnError(record, err)
 	kubernetescli.Execute("get", "csv", upgradeCSV, "-n", sub.Namespace, "-o", "wide")
 	kubernetescli.Execute("get", "installplan", "-n", sub.Namespace)
 	Expect(err).ToNot(HaveOccurred())
@@ -189,15 +182,18 @@ func executeUpgradeTest(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
 	// kubernetescli.Execute("get", "apicurioregistry", "-o", "yaml")
 
 	//wait for deployments
-	kubernetesutils.WaitForOperatorDeploymentReady(suiteCtx.Clientset, sub.Namespace, utils.OperatorDeploymentNameOlm)
-	apicurioutils.WaitForRegistryReady(suiteCtx, ctx.RegistryNamespace, ctx.RegistryName, int32(ctx.Replicas))
+	kubernetesutils.WaitForOperatorDeploymentReady(suiteCtx, sub.Namespace, utils.OperatorDeploymentNameOlm)
+	apicurioutils.WaitForRegistryReady(suiteCtx, ctx.RegistryNamespace, ctx.RegistryName, ctx.RegistryReplicas())
 
 	//verify artifacts after upgrade
 	functional.BasicRegistryAPITest(ctx)
 
 	log.Info("Verifiying test artifacts")
-	artifacts, err = registryClient.ListArtifacts()
+	Expect(manifest.Verify(registryClient)).To(Succeed())
+	functional.VerifyReferences(registryClient, references)
+
+	afterUpgrade, err := snapshot.Take(registryClient)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(len(artifacts)).To(BeIdenticalTo(50))
+	Expect(snapshot.Diff(beforeUpgrade, afterUpgrade)).To(BeEmpty())
 
 }
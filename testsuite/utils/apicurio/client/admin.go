@@ -0,0 +1,114 @@
+package resources
+
+import (
+	"bytes"
+	"net/http"
+)
+
+//ExportData downloads a ZIP file with every group, artifact, version, rule and content stored in the registry
+func (r *ApicurioRegistryApiClientImpl) ExportData() ([]byte, error) {
+	return r.doRequest(http.MethodGet, r.v2URL("/admin/export"), nil, map[string]string{"Accept": "application/zip"}, http.StatusOK)
+}
+
+//ImportData loads a ZIP file previously created with ExportData, the registry is expected to be empty
+func (r *ApicurioRegistryApiClientImpl) ImportData(data []byte) error {
+	_, err := r.doRequest(http.MethodPost, r.v2URL("/admin/import"), bytes.NewBuffer(data), map[string]string{"Content-Type": "application/zip"}, http.StatusNoContent)
+	return err
+}
+
+func (r *ApicurioRegistryApiClientImpl) ListRoleMappings() ([]RoleMapping, error) {
+	mappings := make([]RoleMapping, 0)
+	err := r.doJSON(http.MethodGet, r.v2URL("/admin/roleMappings"), nil, &mappings, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return mappings, nil
+}
+
+func (r *ApicurioRegistryApiClientImpl) CreateRoleMapping(mapping *RoleMapping) error {
+	return r.doJSON(http.MethodPost, r.v2URL("/admin/roleMappings"), mapping, nil, http.StatusNoContent)
+}
+
+func (r *ApicurioRegistryApiClientImpl) GetRoleMapping(principalId string) (*RoleMapping, error) {
+	mapping := &RoleMapping{}
+	err := r.doJSON(http.MethodGet, r.v2URL("/admin/roleMappings/%v", principalId), nil, mapping, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+func (r *ApicurioRegistryApiClientImpl) UpdateRoleMapping(principalId string, role RoleType) error {
+	return r.doJSON(http.MethodPut, r.v2URL("/admin/roleMappings/%v", principalId), &updateRole{Role: role}, nil, http.StatusNoContent)
+}
+
+func (r *ApicurioRegistryApiClientImpl) DeleteRoleMapping(principalId string) error {
+	_, err := r.doRequest(http.MethodDelete, r.v2URL("/admin/roleMappings/%v", principalId), nil, nil, http.StatusNoContent)
+	return err
+}
+
+func (r *ApicurioRegistryApiClientImpl) ListConfigProperties() ([]ConfigProperty, error) {
+	properties := make([]ConfigProperty, 0)
+	err := r.doJSON(http.MethodGet, r.v2URL("/admin/config/properties"), nil, &properties, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return properties, nil
+}
+
+func (r *ApicurioRegistryApiClientImpl) GetConfigProperty(name string) (*ConfigProperty, error) {
+	property := &ConfigProperty{}
+	err := r.doJSON(http.MethodGet, r.v2URL("/admin/config/properties/%v", name), nil, property, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return property, nil
+}
+
+func (r *ApicurioRegistryApiClientImpl) UpdateConfigProperty(name string, value string) error {
+	return r.doJSON(http.MethodPut, r.v2URL("/admin/config/properties/%v", name), &updateConfigProperty{Value: value}, nil, http.StatusNoContent)
+}
+
+//ResetConfigProperty removes the value set at runtime, so the property goes back to its configured default
+func (r *ApicurioRegistryApiClientImpl) ResetConfigProperty(name string) error {
+	_, err := r.doRequest(http.MethodDelete, r.v2URL("/admin/config/properties/%v", name), nil, nil, http.StatusNoContent)
+	return err
+}
+
+//ListLoggers returns the loggers whose level was changed at runtime
+func (r *ApicurioRegistryApiClientImpl) ListLoggers() ([]NamedLogConfiguration, error) {
+	loggers := make([]NamedLogConfiguration, 0)
+	err := r.doJSON(http.MethodGet, r.v2URL("/admin/loggers"), nil, &loggers, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return loggers, nil
+}
+
+func (r *ApicurioRegistryApiClientImpl) GetLogger(name string) (*NamedLogConfiguration, error) {
+	logger := &NamedLogConfiguration{}
+	err := r.doJSON(http.MethodGet, r.v2URL("/admin/loggers/%v", name), nil, logger, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return logger, nil
+}
+
+func (r *ApicurioRegistryApiClientImpl) SetLogLevel(name string, level LogLevel) (*NamedLogConfiguration, error) {
+	logger := &NamedLogConfiguration{}
+	err := r.doJSON(http.MethodPut, r.v2URL("/admin/loggers/%v", name), &logConfiguration{Level: level}, logger, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return logger, nil
+}
+
+//RemoveLogger removes the level set at runtime, returning the level the logger falls back to
+func (r *ApicurioRegistryApiClientImpl) RemoveLogger(name string) (*NamedLogConfiguration, error) {
+	logger := &NamedLogConfiguration{}
+	err := r.doJSON(http.MethodDelete, r.v2URL("/admin/loggers/%v", name), nil, logger, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return logger, nil
+}
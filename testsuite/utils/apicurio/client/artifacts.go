@@ -0,0 +1,168 @@
+package resources
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+//ListGroups returns the ids of every group that contains at least one artifact
+func (r *ApicurioRegistryApiClientImpl) ListGroups() ([]string, error) {
+	groups := map[string]bool{}
+	for offset := 0; ; offset += listPageSize {
+		page := ArtifactSearchResults{}
+		url := r.v2URL("/search/artifacts") + fmt.Sprintf("?offset=%v&limit=%v", offset, listPageSize)
+		err := r.doJSON(http.MethodGet, url, nil, &page, http.StatusOK)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range page.Artifacts {
+			groups[groupOrDefault(a.GroupId)] = true
+		}
+		if len(page.Artifacts) == 0 || offset+len(page.Artifacts) >= page.Count {
+			break
+		}
+	}
+
+	list := make([]string, 0, len(groups))
+	for g := range groups {
+		list = append(list, g)
+	}
+	sort.Strings(list)
+	return list, nil
+}
+
+//ListArtifactsInGroup returns every artifact in the group, walking all the pages of the listing
+func (r *ApicurioRegistryApiClientImpl) ListArtifactsInGroup(groupId string) ([]SearchedArtifact, error) {
+	artifacts := make([]SearchedArtifact, 0)
+	for offset := 0; ; offset += listPageSize {
+		page := ArtifactSearchResults{}
+		url := r.v2URL("/groups/%v/artifacts", groupOrDefault(groupId)) + fmt.Sprintf("?offset=%v&limit=%v", offset, listPageSize)
+		err := r.doJSON(http.MethodGet, url, nil, &page, http.StatusOK)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, page.Artifacts...)
+		if len(page.Artifacts) == 0 || len(artifacts) >= page.Count {
+			break
+		}
+	}
+	return artifacts, nil
+}
+
+func (r *ApicurioRegistryApiClientImpl) DeleteArtifactsInGroup(groupId string) error {
+	_, err := r.doRequest(http.MethodDelete, r.v2URL("/groups/%v/artifacts", groupOrDefault(groupId)), nil, nil, http.StatusNoContent)
+	return err
+}
+
+func (r *ApicurioRegistryApiClientImpl) CreateArtifactInGroup(req *CreateArtifactRequest) (*ArtifactMetaData, error) {
+	headers := versionHeaders(req.ArtifactType, req.Version, req.Name, req.Description)
+	if req.ArtifactId != "" {
+		headers["X-Registry-ArtifactId"] = req.ArtifactId
+	}
+	if req.ArtifactType != "" {
+		headers["X-Registry-ArtifactType"] = string(req.ArtifactType)
+	}
+
+	body, err := contentBody(req.Content, req.References, headers)
+	if err != nil {
+		return nil, err
+	}
+	data, err := r.doRequest(http.MethodPost, r.v2URL("/groups/%v/artifacts", groupOrDefault(req.GroupId)), body, headers, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &ArtifactMetaData{}
+	err = json.Unmarshal(data, metadata)
+	if err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+//ReadLatestArtifact returns the content of the latest version of the artifact
+func (r *ApicurioRegistryApiClientImpl) ReadLatestArtifact(groupId string, artifactId string) (string, error) {
+	data, err := r.doRequest(http.MethodGet, r.v2URL("/groups/%v/artifacts/%v", groupOrDefault(groupId), artifactId), nil, nil, http.StatusOK)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (r *ApicurioRegistryApiClientImpl) DeleteArtifactInGroup(groupId string, artifactId string) error {
+	_, err := r.doRequest(http.MethodDelete, r.v2URL("/groups/%v/artifacts/%v", groupOrDefault(groupId), artifactId), nil, nil, http.StatusNoContent)
+	return err
+}
+
+func (r *ApicurioRegistryApiClientImpl) GetArtifactMetaData(groupId string, artifactId string) (*ArtifactMetaData, error) {
+	metadata := &ArtifactMetaData{}
+	err := r.doJSON(http.MethodGet, r.v2URL("/groups/%v/artifacts/%v/meta", groupOrDefault(groupId), artifactId), nil, metadata, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+func (r *ApicurioRegistryApiClientImpl) UpdateArtifactMetaData(groupId string, artifactId string, metadata *EditableMetaData) error {
+	return r.doJSON(http.MethodPut, r.v2URL("/groups/%v/artifacts/%v/meta", groupOrDefault(groupId), artifactId), metadata, nil, http.StatusNoContent)
+}
+
+func (r *ApicurioRegistryApiClientImpl) GetArtifactVersionMetaData(groupId string, artifactId string, version string) (*VersionMetaData, error) {
+	metadata := &VersionMetaData{}
+	err := r.doJSON(http.MethodGet, r.v2URL("/groups/%v/artifacts/%v/versions/%v/meta", groupOrDefault(groupId), artifactId, version), nil, metadata, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+func (r *ApicurioRegistryApiClientImpl) ReadContentByGlobalId(globalId int64) (string, error) {
+	data, err := r.doRequest(http.MethodGet, r.v2URL("/ids/globalIds/%v", strconv.FormatInt(globalId, 10)), nil, nil, http.StatusOK)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (r *ApicurioRegistryApiClientImpl) ReadContentByContentId(contentId int64) (string, error) {
+	data, err := r.doRequest(http.MethodGet, r.v2URL("/ids/contentIds/%v", strconv.FormatInt(contentId, 10)), nil, nil, http.StatusOK)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+//contentBody the raw content, or the content and its references when there are references to send
+func contentBody(content string, references []ArtifactReference, headers map[string]string) (io.Reader, error) {
+	if len(references) == 0 {
+		return bytes.NewBufferString(content), nil
+	}
+	data, err := json.Marshal(&contentCreateRequest{Content: content, References: references})
+	if err != nil {
+		return nil, err
+	}
+	headers["Content-Type"] = extendedContentType
+	return bytes.NewBuffer(data), nil
+}
+
+func versionHeaders(artifactType ArtifactType, version string, name string, description string) map[string]string {
+	headers := map[string]string{
+		"Content-Type": artifactType.ContentType(),
+		"Accept":       "application/json",
+	}
+	if version != "" {
+		headers["X-Registry-Version"] = version
+	}
+	if name != "" {
+		headers["X-Registry-Name"] = name
+	}
+	if description != "" {
+		headers["X-Registry-Description"] = description
+	}
+	return headers
+}
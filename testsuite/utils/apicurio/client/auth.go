@@ -0,0 +1,141 @@
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+//tokenExpiryMargin access tokens are renewed this long before they actually expire
+const tokenExpiryMargin time.Duration = 10 * time.Second
+
+//Authenticator adds credentials to the requests sent to the registry
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+//KeycloakTokenURL returns the OIDC token endpoint of a keycloak realm, keycloakURL is expected to include the /auth context path
+func KeycloakTokenURL(keycloakURL string, realm string) string {
+	return strings.TrimSuffix(keycloakURL, "/") + "/realms/" + realm + "/protocol/openid-connect/token"
+}
+
+type basicAuth struct {
+	username string
+	password string
+}
+
+//NewBasicAuth authenticates requests using http basic authentication
+func NewBasicAuth(username string, password string) Authenticator {
+	return &basicAuth{username: username, password: password}
+}
+
+func (a *basicAuth) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(a.username, a.password)
+	return nil
+}
+
+type bearerToken struct {
+	token string
+}
+
+//NewBearerToken authenticates requests with a fixed access token
+func NewBearerToken(token string) Authenticator {
+	return &bearerToken{token: token}
+}
+
+func (a *bearerToken) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	return nil
+}
+
+//oidcTokenSource issues access tokens from an OIDC token endpoint and caches them until they are about to expire
+type oidcTokenSource struct {
+	tokenURL   string
+	form       url.Values
+	httpClient *http.Client
+
+	lock        sync.Mutex
+	accessToken string
+	expiry      time.Time
+}
+
+type oidcTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+//NewOIDCPasswordGrant authenticates requests with access tokens issued for a user, using the OIDC resource owner password grant
+func NewOIDCPasswordGrant(tokenURL string, clientID string, username string, password string, httpClient *http.Client) Authenticator {
+	form := url.Values{}
+	form.Set("grant_type", "password")
+	form.Set("client_id", clientID)
+	form.Set("username", username)
+	form.Set("password", password)
+	return &oidcTokenSource{tokenURL: tokenURL, form: form, httpClient: httpClient}
+}
+
+//NewOIDCClientCredentials authenticates requests with access tokens issued for a confidential client, using the OIDC client credentials grant.
+//Tokens are requested again automatically when they expire
+func NewOIDCClientCredentials(tokenURL string, clientID string, clientSecret string, httpClient *http.Client) Authenticator {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", clientID)
+	form.Set("client_secret", clientSecret)
+	return &oidcTokenSource{tokenURL: tokenURL, form: form, httpClient: httpClient}
+}
+
+func (s *oidcTokenSource) Authenticate(req *http.Request) error {
+	token, err := s.token()
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+func (s *oidcTokenSource) token() (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.accessToken != "" && time.Now().Before(s.expiry) {
+		return s.accessToken, nil
+	}
+
+	httpClient := s.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.PostForm(s.tokenURL, s.form)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode > 299 {
+		return "", fmt.Errorf("token request status code is %v body is %v", resp.StatusCode, string(data))
+	}
+
+	tokenResponse := oidcTokenResponse{}
+	err = json.Unmarshal(data, &tokenResponse)
+	if err != nil {
+		return "", err
+	}
+	if tokenResponse.AccessToken == "" {
+		return "", fmt.Errorf("token response does not contain an access token")
+	}
+
+	s.accessToken = tokenResponse.AccessToken
+	s.expiry = time.Now().Add(time.Duration(tokenResponse.ExpiresIn)*time.Second - tokenExpiryMargin)
+
+	return s.accessToken, nil
+}
@@ -0,0 +1,225 @@
+package resources
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+)
+
+const ccompatPath string = "/apis/ccompat/v6"
+
+const (
+	CompatibilityBackward           CompatibilityLevel = "BACKWARD"
+	CompatibilityBackwardTransitive CompatibilityLevel = "BACKWARD_TRANSITIVE"
+	CompatibilityForward            CompatibilityLevel = "FORWARD"
+	CompatibilityForwardTransitive  CompatibilityLevel = "FORWARD_TRANSITIVE"
+	CompatibilityFull               CompatibilityLevel = "FULL"
+	CompatibilityFullTransitive     CompatibilityLevel = "FULL_TRANSITIVE"
+	CompatibilityNone               CompatibilityLevel = "NONE"
+)
+
+//CompatibilityLevel confluent compatibility level of a subject or of the whole registry
+type CompatibilityLevel string
+
+//LatestVersion alias of the latest version of a subject
+const LatestVersion string = "latest"
+
+//SchemaReference reference from a schema to a schema registered under another subject
+type SchemaReference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+//SchemaRequest schema sent to be registered, looked up or checked for compatibility, SchemaType defaults to AVRO
+type SchemaRequest struct {
+	Schema     string            `json:"schema"`
+	SchemaType string            `json:"schemaType,omitempty"`
+	References []SchemaReference `json:"references,omitempty"`
+}
+
+//Schema schema as returned by the confluent compatible api, fields not known in the context of the request are left empty
+type Schema struct {
+	Subject    string            `json:"subject,omitempty"`
+	Id         int               `json:"id,omitempty"`
+	Version    int               `json:"version,omitempty"`
+	Schema     string            `json:"schema"`
+	SchemaType string            `json:"schemaType,omitempty"`
+	References []SchemaReference `json:"references,omitempty"`
+}
+
+type schemaId struct {
+	Id int `json:"id"`
+}
+
+type compatibilityCheck struct {
+	IsCompatible bool `json:"is_compatible"`
+}
+
+type compatibilityConfig struct {
+	Compatibility CompatibilityLevel `json:"compatibility,omitempty"`
+	//CompatibilityLevel is the field name used in responses of get operations
+	CompatibilityLevel CompatibilityLevel `json:"compatibilityLevel,omitempty"`
+}
+
+func (c *compatibilityConfig) level() CompatibilityLevel {
+	if c.CompatibilityLevel != "" {
+		return c.CompatibilityLevel
+	}
+	return c.Compatibility
+}
+
+//CCompatApiClient client of the confluent schema registry compatible api of apicurio registry
+type CCompatApiClient interface {
+	ListSubjects() ([]string, error)
+	RegisterSchema(subject string, schema *SchemaRequest) (int, error)
+	LookupSchema(subject string, schema *SchemaRequest) (*Schema, error)
+	ListSubjectVersions(subject string) ([]int, error)
+	GetSubjectVersion(subject string, version string) (*Schema, error)
+	DeleteSubject(subject string) ([]int, error)
+	DeleteSubjectVersion(subject string, version string) (int, error)
+	GetSchemaById(id int) (*Schema, error)
+
+	TestCompatibility(subject string, version string, schema *SchemaRequest) (bool, error)
+
+	GetGlobalCompatibility() (CompatibilityLevel, error)
+	UpdateGlobalCompatibility(level CompatibilityLevel) error
+	GetSubjectCompatibility(subject string) (CompatibilityLevel, error)
+	UpdateSubjectCompatibility(subject string, level CompatibilityLevel) error
+	DeleteSubjectCompatibility(subject string) error
+}
+
+//CCompatApiClientImpl shares the http plumbing and credentials handling of the registry api client
+type CCompatApiClientImpl struct {
+	registry *ApicurioRegistryApiClientImpl
+}
+
+//NewCCompatApiClientForEndpoint creates a confluent compatible api client for the registry reachable at endpoint, auth can be nil for unsecured registries
+func NewCCompatApiClientForEndpoint(endpoint *types.RegistryEndpoint, auth Authenticator) (CCompatApiClient, error) {
+	httpClient, err := endpoint.HTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	return &CCompatApiClientImpl{registry: newApicurioRegistryApiClient(endpoint.URL(), httpClient, auth)}, nil
+}
+
+func (c *CCompatApiClientImpl) url(pathFormat string, pathParams ...string) string {
+	return c.registry.apiURL(ccompatPath, pathFormat, pathParams...)
+}
+
+func (c *CCompatApiClientImpl) ListSubjects() ([]string, error) {
+	subjects := make([]string, 0)
+	err := c.registry.doJSON(http.MethodGet, c.url("/subjects"), nil, &subjects, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return subjects, nil
+}
+
+//RegisterSchema registers the schema under the subject, returning the global id of the schema, registering an already existing schema returns its id
+func (c *CCompatApiClientImpl) RegisterSchema(subject string, schema *SchemaRequest) (int, error) {
+	id := &schemaId{}
+	err := c.registry.doJSON(http.MethodPost, c.url("/subjects/%v/versions", subject), schema, id, http.StatusOK)
+	if err != nil {
+		return 0, err
+	}
+	return id.Id, nil
+}
+
+//LookupSchema returns the version of the subject with the given schema
+func (c *CCompatApiClientImpl) LookupSchema(subject string, schema *SchemaRequest) (*Schema, error) {
+	result := &Schema{}
+	err := c.registry.doJSON(http.MethodPost, c.url("/subjects/%v", subject), schema, result, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *CCompatApiClientImpl) ListSubjectVersions(subject string) ([]int, error) {
+	versions := make([]int, 0)
+	err := c.registry.doJSON(http.MethodGet, c.url("/subjects/%v/versions", subject), nil, &versions, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+//GetSubjectVersion returns one version of the subject, version can be a version number or LatestVersion
+func (c *CCompatApiClientImpl) GetSubjectVersion(subject string, version string) (*Schema, error) {
+	result := &Schema{}
+	err := c.registry.doJSON(http.MethodGet, c.url("/subjects/%v/versions/%v", subject, version), nil, result, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//DeleteSubject deletes every version of the subject, returning the deleted version numbers
+func (c *CCompatApiClientImpl) DeleteSubject(subject string) ([]int, error) {
+	versions := make([]int, 0)
+	err := c.registry.doJSON(http.MethodDelete, c.url("/subjects/%v", subject), nil, &versions, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (c *CCompatApiClientImpl) DeleteSubjectVersion(subject string, version string) (int, error) {
+	var deleted int
+	err := c.registry.doJSON(http.MethodDelete, c.url("/subjects/%v/versions/%v", subject, version), nil, &deleted, http.StatusOK)
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+func (c *CCompatApiClientImpl) GetSchemaById(id int) (*Schema, error) {
+	result := &Schema{}
+	err := c.registry.doJSON(http.MethodGet, c.url("/schemas/ids/%v", strconv.Itoa(id)), nil, result, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//TestCompatibility checks the schema against one version of the subject, using the compatibility level configured for the subject
+func (c *CCompatApiClientImpl) TestCompatibility(subject string, version string, schema *SchemaRequest) (bool, error) {
+	result := &compatibilityCheck{}
+	err := c.registry.doJSON(http.MethodPost, c.url("/compatibility/subjects/%v/versions/%v", subject, version), schema, result, http.StatusOK)
+	if err != nil {
+		return false, err
+	}
+	return result.IsCompatible, nil
+}
+
+func (c *CCompatApiClientImpl) GetGlobalCompatibility() (CompatibilityLevel, error) {
+	config := &compatibilityConfig{}
+	err := c.registry.doJSON(http.MethodGet, c.url("/config"), nil, config, http.StatusOK)
+	if err != nil {
+		return "", err
+	}
+	return config.level(), nil
+}
+
+func (c *CCompatApiClientImpl) UpdateGlobalCompatibility(level CompatibilityLevel) error {
+	return c.registry.doJSON(http.MethodPut, c.url("/config"), &compatibilityConfig{Compatibility: level}, nil, http.StatusOK)
+}
+
+func (c *CCompatApiClientImpl) GetSubjectCompatibility(subject string) (CompatibilityLevel, error) {
+	config := &compatibilityConfig{}
+	err := c.registry.doJSON(http.MethodGet, c.url("/config/%v", subject), nil, config, http.StatusOK)
+	if err != nil {
+		return "", err
+	}
+	return config.level(), nil
+}
+
+func (c *CCompatApiClientImpl) UpdateSubjectCompatibility(subject string, level CompatibilityLevel) error {
+	return c.registry.doJSON(http.MethodPut, c.url("/config/%v", subject), &compatibilityConfig{Compatibility: level}, nil, http.StatusOK)
+}
+
+func (c *CCompatApiClientImpl) DeleteSubjectCompatibility(subject string) error {
+	return c.registry.doJSON(http.MethodDelete, c.url("/config/%v", subject), nil, nil, http.StatusOK)
+}
@@ -4,141 +4,272 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
 )
 
 const (
-	Avro ArtifactType = "AVRO"
+	Avro       ArtifactType = "AVRO"
+	Protobuf   ArtifactType = "PROTOBUF"
+	JsonSchema ArtifactType = "JSON"
+	OpenAPI    ArtifactType = "OPENAPI"
+	AsyncAPI   ArtifactType = "ASYNCAPI"
+	GraphQL    ArtifactType = "GRAPHQL"
+	KConnect   ArtifactType = "KCONNECT"
+	WSDL       ArtifactType = "WSDL"
+	XSD        ArtifactType = "XSD"
+	XML        ArtifactType = "XML"
 )
 
 type ArtifactType string
 
+//ArtifactTypes every artifact type supported by the registry
+var ArtifactTypes []ArtifactType = []ArtifactType{Avro, Protobuf, JsonSchema, OpenAPI, AsyncAPI, GraphQL, KConnect, WSDL, XSD, XML}
+
+//ContentType media type used to send content of this artifact type to the registry
+func (t ArtifactType) ContentType() string {
+	switch t {
+	case Protobuf:
+		return "application/x-protobuf"
+	case GraphQL:
+		return "application/graphql"
+	case WSDL, XSD, XML:
+		return "application/xml"
+	default:
+		return "application/json"
+	}
+}
+
 type ApicurioRegistryApiClient interface {
 	CreateArtifact(id string, artifactType ArtifactType, data string) error
 	ReadArtifact(id string) (string, error)
 	DeleteArtifact(id string) error
 	ListArtifacts() ([]string, error)
+
+	//registry v2 api
+
+	ListGroups() ([]string, error)
+	ListArtifactsInGroup(groupId string) ([]SearchedArtifact, error)
+	DeleteArtifactsInGroup(groupId string) error
+	CreateArtifactInGroup(req *CreateArtifactRequest) (*ArtifactMetaData, error)
+	ReadLatestArtifact(groupId string, artifactId string) (string, error)
+	DeleteArtifactInGroup(groupId string, artifactId string) error
+	GetArtifactMetaData(groupId string, artifactId string) (*ArtifactMetaData, error)
+	UpdateArtifactMetaData(groupId string, artifactId string, metadata *EditableMetaData) error
+	GetArtifactVersionMetaData(groupId string, artifactId string, version string) (*VersionMetaData, error)
+	ReadContentByGlobalId(globalId int64) (string, error)
+	ReadContentByContentId(contentId int64) (string, error)
+
+	CreateArtifactVersion(req *CreateVersionRequest) (*VersionMetaData, error)
+	ListArtifactVersions(groupId string, artifactId string) ([]SearchedVersion, error)
+	ReadArtifactVersion(groupId string, artifactId string, version string) (string, error)
+	UpdateArtifactState(groupId string, artifactId string, state ArtifactState) error
+	UpdateArtifactVersionState(groupId string, artifactId string, version string, state ArtifactState) error
+
+	ListArtifactVersionReferences(groupId string, artifactId string, version string) ([]ArtifactReference, error)
+	ListReferencesByGlobalId(globalId int64) ([]ArtifactReference, error)
+	ReadDereferencedArtifactVersion(groupId string, artifactId string, version string) (string, error)
+	ReadDereferencedContentByGlobalId(globalId int64) (string, error)
+
+	ListGlobalRules() ([]RuleType, error)
+	CreateGlobalRule(rule *Rule) error
+	GetGlobalRule(ruleType RuleType) (*Rule, error)
+	UpdateGlobalRule(rule *Rule) error
+	DeleteGlobalRule(ruleType RuleType) error
+	DeleteAllGlobalRules() error
+	ListArtifactRules(groupId string, artifactId string) ([]RuleType, error)
+	CreateArtifactRule(groupId string, artifactId string, rule *Rule) error
+	GetArtifactRule(groupId string, artifactId string, ruleType RuleType) (*Rule, error)
+	UpdateArtifactRule(groupId string, artifactId string, rule *Rule) error
+	DeleteArtifactRule(groupId string, artifactId string, ruleType RuleType) error
+	DeleteArtifactRules(groupId string, artifactId string) error
+
+	SearchArtifacts(req *ArtifactSearchRequest) (*ArtifactSearchResults, error)
+	SearchVersions(req *VersionSearchRequest) (*VersionSearchResults, error)
+
+	ExportData() ([]byte, error)
+	ImportData(data []byte) error
+	ListRoleMappings() ([]RoleMapping, error)
+	CreateRoleMapping(mapping *RoleMapping) error
+	GetRoleMapping(principalId string) (*RoleMapping, error)
+	UpdateRoleMapping(principalId string, role RoleType) error
+	DeleteRoleMapping(principalId string) error
+	ListConfigProperties() ([]ConfigProperty, error)
+	GetConfigProperty(name string) (*ConfigProperty, error)
+	UpdateConfigProperty(name string, value string) error
+	ResetConfigProperty(name string) error
+	ListLoggers() ([]NamedLogConfiguration, error)
+	GetLogger(name string) (*NamedLogConfiguration, error)
+	SetLogLevel(name string, level LogLevel) (*NamedLogConfiguration, error)
+	RemoveLogger(name string) (*NamedLogConfiguration, error)
 }
 
 type ApicurioRegistryApiClientImpl struct {
-	host       string
-	port       string
+	baseURL    string
 	httpClient *http.Client
+	auth       Authenticator
 }
 
 func NewApicurioRegistryApiClient(host string, port string, httpClient *http.Client) ApicurioRegistryApiClient {
-	return &ApicurioRegistryApiClientImpl{
-		host:       host,
-		port:       port,
-		httpClient: httpClient,
-	}
+	return NewAuthenticatedApicurioRegistryApiClient(host, port, httpClient, nil)
 }
 
-func (r *ApicurioRegistryApiClientImpl) CreateArtifact(id string, artifactType ArtifactType, data string) error {
-	url := fmt.Sprintf("http://%v:%v/api/artifacts", r.host, r.port)
-	body := bytes.NewBuffer([]byte(data))
+//NewAuthenticatedApicurioRegistryApiClient creates a client that adds the credentials provided by auth to every request, auth can be nil for unsecured registries
+func NewAuthenticatedApicurioRegistryApiClient(host string, port string, httpClient *http.Client, auth Authenticator) ApicurioRegistryApiClient {
+	return newApicurioRegistryApiClient(fmt.Sprintf("http://%v:%v", host, port), httpClient, auth)
+}
 
-	req, err := http.NewRequest(http.MethodPost, url, body)
+//NewApicurioRegistryApiClientForEndpoint creates a client for the registry reachable at endpoint, honoring its scheme and trusted certificates, auth can be nil for unsecured registries
+func NewApicurioRegistryApiClientForEndpoint(endpoint *types.RegistryEndpoint, auth Authenticator) (ApicurioRegistryApiClient, error) {
+	httpClient, err := endpoint.HTTPClient()
 	if err != nil {
-		return err
-	}
-
-	switch artifactType {
-	case Avro:
-		req.Header.Set("Content-Type", fmt.Sprintf("application/json; artifactType=%v", Avro))
+		return nil, err
 	}
+	return newApicurioRegistryApiClient(endpoint.URL(), httpClient, auth), nil
+}
 
-	req.Header.Set("X-Registry-ArtifactId", id)
+//NewApicurioRegistryApiClientForURL creates a client for the registry served at baseURL, i.e. behind a proxy, auth can be nil for unsecured registries
+func NewApicurioRegistryApiClientForURL(baseURL string, httpClient *http.Client, auth Authenticator) ApicurioRegistryApiClient {
+	return newApicurioRegistryApiClient(strings.TrimSuffix(baseURL, "/"), httpClient, auth)
+}
 
-	resp, err := r.httpClient.Do(req)
-	if err != nil {
-		return err
+func newApicurioRegistryApiClient(baseURL string, httpClient *http.Client, auth Authenticator) *ApicurioRegistryApiClientImpl {
+	return &ApicurioRegistryApiClientImpl{
+		baseURL:    baseURL,
+		httpClient: httpClient,
+		auth:       auth,
 	}
-	defer resp.Body.Close()
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf(fmt.Sprintf("expected status 200 but received %v", resp.StatusCode))
+func (r *ApicurioRegistryApiClientImpl) CreateArtifact(id string, artifactType ArtifactType, data string) error {
+	headers := map[string]string{
+		"Content-Type":          fmt.Sprintf("%v; artifactType=%v", artifactType.ContentType(), artifactType),
+		"X-Registry-ArtifactId": id,
 	}
-
-	return nil
+	_, err := r.doRequest(http.MethodPost, r.legacyURL(""), bytes.NewBufferString(data), headers, http.StatusOK)
+	return err
 }
 
 func (r *ApicurioRegistryApiClientImpl) ReadArtifact(id string) (string, error) {
-	url := fmt.Sprintf("http://%v:%v/api/artifacts", r.host, r.port)
-
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	data, err := r.doRequest(http.MethodGet, r.legacyURL(id), nil, nil, http.StatusOK)
 	if err != nil {
 		return "", err
 	}
+	return string(data), nil
+}
+
+func (r *ApicurioRegistryApiClientImpl) DeleteArtifact(id string) error {
+	_, err := r.doRequest(http.MethodDelete, r.legacyURL(id), nil, nil, http.StatusNoContent)
+	return err
+}
 
-	resp, err := r.httpClient.Do(req)
+func (r *ApicurioRegistryApiClientImpl) ListArtifacts() ([]string, error) {
+	list := make([]string, 0)
+	err := r.doJSON(http.MethodGet, r.legacyURL(""), nil, &list, http.StatusOK)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return list, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf(fmt.Sprintf("expected status 200 but received %v", resp.StatusCode))
+//legacyURL url of the artifacts collection of the legacy api, or of one artifact if id is not empty
+func (r *ApicurioRegistryApiClientImpl) legacyURL(id string) string {
+	if id == "" {
+		return r.baseURL + "/api/artifacts"
 	}
+	return r.baseURL + "/api/artifacts/" + url.PathEscape(id)
+}
 
-	bytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-	data := string(bytes)
+const registryV2Path string = "/apis/registry/v2"
 
-	return data, nil
+//listPageSize number of items requested per page when listing every item of a collection
+const listPageSize int = 100
+
+func (r *ApicurioRegistryApiClientImpl) v2URL(pathFormat string, pathParams ...string) string {
+	return r.apiURL(registryV2Path, pathFormat, pathParams...)
 }
 
-func (r *ApicurioRegistryApiClientImpl) DeleteArtifact(id string) error {
-	url := fmt.Sprintf("http://%v:%v/api/artifacts", r.host, r.port)
+//apiURL builds the url of one resource of the api mounted at apiPath, escaping every path param
+func (r *ApicurioRegistryApiClientImpl) apiURL(apiPath string, pathFormat string, pathParams ...string) string {
+	escaped := make([]interface{}, len(pathParams))
+	for i, p := range pathParams {
+		escaped[i] = url.PathEscape(p)
+	}
+	return r.baseURL + apiPath + fmt.Sprintf(pathFormat, escaped...)
+}
 
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
-	if err != nil {
-		return err
+//do sends the request adding the client credentials, if any
+func (r *ApicurioRegistryApiClientImpl) do(req *http.Request) (*http.Response, error) {
+	if r.auth != nil {
+		err := r.auth.Authenticate(req)
+		if err != nil {
+			return nil, err
+		}
 	}
+	return r.httpClient.Do(req)
+}
 
-	resp, err := r.httpClient.Do(req)
+//doRequest executes one request against the registry, returning the response body if the status code is the expected one or a *RegistryError otherwise
+func (r *ApicurioRegistryApiClientImpl) doRequest(method string, url string, body io.Reader, headers map[string]string, expectedStatus int) ([]byte, error) {
+	req, err := http.NewRequest(method, url, body)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf(fmt.Sprintf("expected status %v but received %v", http.StatusNoContent, resp.StatusCode))
+	for k, v := range headers {
+		req.Header.Set(k, v)
 	}
 
-	return nil
-}
-
-func (r *ApicurioRegistryApiClientImpl) ListArtifacts() ([]string, error) {
-	url := fmt.Sprintf("http://%v:%v/api/artifacts", r.host, r.port)
-
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	resp, err := r.do(req)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
-	resp, err := r.httpClient.Do(req)
+	data, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(fmt.Sprintf("expected status 200 but received %v", resp.StatusCode))
+	if resp.StatusCode != expectedStatus {
+		return nil, newRegistryError(resp.StatusCode, data)
 	}
 
-	bytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	return data, nil
+}
+
+//doJSON sends in, if not nil, as json and decodes the response body into out, if not nil
+func (r *ApicurioRegistryApiClientImpl) doJSON(method string, url string, in interface{}, out interface{}, expectedStatus int) error {
+	var body io.Reader
+	headers := map[string]string{"Accept": "application/json"}
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewBuffer(data)
+		headers["Content-Type"] = "application/json"
 	}
-	var list []string = make([]string, 0)
-	err = json.Unmarshal(bytes, &list)
+
+	data, err := r.doRequest(method, url, body, headers, expectedStatus)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return list, nil
+	if out != nil && len(data) != 0 {
+		return json.Unmarshal(data, out)
+	}
+	return nil
+}
+
+func groupOrDefault(groupId string) string {
+	if groupId == "" {
+		return DefaultGroup
+	}
+	return groupId
 }
@@ -0,0 +1,372 @@
+package resources
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+//newFakeClient starts a fake registry, closed when the test ends, and a client of it
+func newFakeClient(t *testing.T) (*FakeRegistry, ApicurioRegistryApiClient) {
+	registry := NewFakeRegistry()
+	t.Cleanup(registry.Close)
+	return registry, registry.Client()
+}
+
+func createAvro(g *WithT, client ApicurioRegistryApiClient, groupId string, artifactId string) *ArtifactMetaData {
+	content, err := SampleContent(Avro)
+	g.Expect(err).ToNot(HaveOccurred())
+	metadata, err := client.CreateArtifactInGroup(&CreateArtifactRequest{GroupId: groupId, ArtifactId: artifactId, ArtifactType: Avro, Content: content})
+	g.Expect(err).ToNot(HaveOccurred())
+	return metadata
+}
+
+//seedSearch creates the artifacts the search tests look for, three of them in the search group and one in another group
+func seedSearch(g *WithT, client ApicurioRegistryApiClient) {
+	for i, name := range []string{"orders", "payments", "order lines"} {
+		id := "search-" + strconv.Itoa(i)
+		createAvro(g, client, "search", id)
+		err := client.UpdateArtifactMetaData("search", id, &EditableMetaData{
+			Name:        name,
+			Description: "schema of " + name,
+			Labels:      []string{"search", name},
+			Properties:  map[string]string{"team": "team-" + strconv.Itoa(i%2)},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+	createAvro(g, client, "other", "unrelated")
+}
+
+func searchIds(g *WithT, client ApicurioRegistryApiClient, req *ArtifactSearchRequest) []string {
+	results, err := client.SearchArtifacts(req)
+	g.Expect(err).ToNot(HaveOccurred())
+	ids := []string{}
+	for _, a := range results.Artifacts {
+		ids = append(ids, a.Id)
+	}
+	return ids
+}
+
+func TestLegacyApiReadsAndDeletesTheRequestedArtifact(t *testing.T) {
+	g := NewWithT(t)
+	_, client := newFakeClient(t)
+	g.Expect(client.CreateArtifact("first", Avro, "{\"type\":\"string\"}")).To(Succeed())
+	g.Expect(client.CreateArtifact("second", JsonSchema, "{\"type\":\"object\"}")).To(Succeed())
+
+	content, err := client.ReadArtifact("second")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(content).To(Equal("{\"type\":\"object\"}"))
+
+	g.Expect(client.DeleteArtifact("first")).To(Succeed())
+	ids, err := client.ListArtifacts()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(ids).To(ConsistOf("second"))
+
+	metadata, err := client.GetArtifactMetaData(DefaultGroup, "second")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(metadata.Type).To(Equal(JsonSchema))
+}
+
+func TestLegacyApiReturnsNotFoundForUnknownArtifacts(t *testing.T) {
+	g := NewWithT(t)
+	_, client := newFakeClient(t)
+	_, err := client.ReadArtifact("missing")
+	g.Expect(IsNotFound(err)).To(BeTrue())
+	g.Expect(IsNotFound(client.DeleteArtifact("missing"))).To(BeTrue())
+}
+
+func TestArtifactsCreatesInGroupsAndListsPageByPage(t *testing.T) {
+	g := NewWithT(t)
+	_, client := newFakeClient(t)
+	for i := 0; i < listPageSize+5; i++ {
+		createAvro(g, client, "paged", "artifact-"+strconv.Itoa(i))
+	}
+	createAvro(g, client, "other", "artifact-0")
+
+	artifacts, err := client.ListArtifactsInGroup("paged")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(artifacts).To(HaveLen(listPageSize + 5))
+
+	groups, err := client.ListGroups()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(groups).To(Equal([]string{"other", "paged"}))
+
+	g.Expect(client.DeleteArtifactsInGroup("paged")).To(Succeed())
+	artifacts, err = client.ListArtifactsInGroup("paged")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(artifacts).To(BeEmpty())
+}
+
+func TestArtifactsEscapesIdsInUrls(t *testing.T) {
+	g := NewWithT(t)
+	_, client := newFakeClient(t)
+	createAvro(g, client, "group with spaces", "com.example/price value")
+	metadata, err := client.GetArtifactMetaData("group with spaces", "com.example/price value")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(metadata.Id).To(Equal("com.example/price value"))
+	g.Expect(metadata.GroupId).To(Equal("group with spaces"))
+}
+
+func TestArtifactsUpdatesMetadata(t *testing.T) {
+	g := NewWithT(t)
+	_, client := newFakeClient(t)
+	createAvro(g, client, "meta", "price")
+	err := client.UpdateArtifactMetaData("meta", "price", &EditableMetaData{Name: "Price", Description: "price events", Labels: []string{"a"}, Properties: map[string]string{"k": "v"}})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	metadata, err := client.GetArtifactMetaData("meta", "price")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(metadata.Name).To(Equal("Price"))
+	g.Expect(metadata.Description).To(Equal("price events"))
+	g.Expect(metadata.Labels).To(ConsistOf("a"))
+	g.Expect(metadata.Properties).To(HaveKeyWithValue("k", "v"))
+}
+
+func TestArtifactsReadsContentByGlobalAndContentId(t *testing.T) {
+	g := NewWithT(t)
+	_, client := newFakeClient(t)
+	first := createAvro(g, client, "ids", "first")
+	second := createAvro(g, client, "ids", "second")
+	g.Expect(second.GlobalId).ToNot(Equal(first.GlobalId))
+	g.Expect(second.ContentId).To(Equal(first.ContentId))
+
+	byGlobalId, err := client.ReadContentByGlobalId(second.GlobalId)
+	g.Expect(err).ToNot(HaveOccurred())
+	byContentId, err := client.ReadContentByContentId(second.ContentId)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(byGlobalId).To(Equal(byContentId))
+
+	_, err = client.ReadContentByGlobalId(1000)
+	g.Expect(IsNotFound(err)).To(BeTrue())
+}
+
+func TestVersionsCreatesVersionsAndChangesTheirState(t *testing.T) {
+	g := NewWithT(t)
+	_, client := newFakeClient(t)
+	createAvro(g, client, "versions", "price")
+	version, err := client.CreateArtifactVersion(&CreateVersionRequest{GroupId: "versions", ArtifactId: "price", Version: "2.0", Content: "{\"type\":\"int\"}"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(version.Version).To(Equal("2.0"))
+
+	versions, err := client.ListArtifactVersions("versions", "price")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(versions).To(HaveLen(2))
+
+	latest, err := client.ReadLatestArtifact("versions", "price")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(latest).To(Equal("{\"type\":\"int\"}"))
+
+	g.Expect(client.UpdateArtifactVersionState("versions", "price", "2.0", Disabled)).To(Succeed())
+	metadata, err := client.GetArtifactMetaData("versions", "price")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(metadata.Version).To(Equal(versions[0].Version))
+
+	g.Expect(client.UpdateArtifactState("versions", "price", Deprecated)).To(Succeed())
+	versionMetadata, err := client.GetArtifactVersionMetaData("versions", "price", versions[0].Version)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(versionMetadata.State).To(Equal(Deprecated))
+
+	content, err := client.ReadArtifactVersion("versions", "price", "2.0")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(content).To(Equal("{\"type\":\"int\"}"))
+}
+
+func TestVersionsReturnsTypedErrorsForDuplicatesAndUnknownVersions(t *testing.T) {
+	g := NewWithT(t)
+	_, client := newFakeClient(t)
+	createAvro(g, client, "versions", "price")
+	_, err := client.CreateArtifactInGroup(&CreateArtifactRequest{GroupId: "versions", ArtifactId: "price", ArtifactType: Avro, Content: "{}"})
+	g.Expect(IsConflict(err)).To(BeTrue())
+	regErr, ok := AsRegistryError(err)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(regErr.Name).To(Equal("ArtifactAlreadyExistsException"))
+	g.Expect(regErr.ErrorCode).To(Equal(http.StatusConflict))
+	g.Expect(regErr.Message).To(ContainSubstring("price"))
+
+	_, err = client.ReadArtifactVersion("versions", "price", "7")
+	regErr, ok = AsRegistryError(err)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(regErr.StatusCode).To(Equal(http.StatusNotFound))
+	g.Expect(regErr.Name).To(Equal("VersionNotFoundException"))
+}
+
+func TestRulesManagesGlobalRules(t *testing.T) {
+	g := NewWithT(t)
+	_, client := newFakeClient(t)
+	g.Expect(client.CreateGlobalRule(&Rule{Type: ValidityRule, Config: "FULL"})).To(Succeed())
+	g.Expect(IsConflict(client.CreateGlobalRule(&Rule{Type: ValidityRule, Config: "FULL"}))).To(BeTrue())
+	g.Expect(client.UpdateGlobalRule(&Rule{Type: ValidityRule, Config: "SYNTAX_ONLY"})).To(Succeed())
+
+	rule, err := client.GetGlobalRule(ValidityRule)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(rule.Config).To(Equal("SYNTAX_ONLY"))
+	rules, err := client.ListGlobalRules()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(rules).To(ConsistOf(ValidityRule))
+
+	g.Expect(client.DeleteAllGlobalRules()).To(Succeed())
+	_, err = client.GetGlobalRule(ValidityRule)
+	g.Expect(IsNotFound(err)).To(BeTrue())
+}
+
+func TestRulesEnforcesTheValidityRule(t *testing.T) {
+	g := NewWithT(t)
+	_, client := newFakeClient(t)
+	createAvro(g, client, "rules", "price")
+	g.Expect(client.CreateArtifactRule("rules", "price", &Rule{Type: ValidityRule, Config: "FULL"})).To(Succeed())
+
+	_, err := client.CreateArtifactVersion(&CreateVersionRequest{GroupId: "rules", ArtifactId: "price", Content: "{\"type\":"})
+	regErr, ok := AsRegistryError(err)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(regErr.Name).To(Equal("RuleViolationException"))
+
+	g.Expect(client.UpdateArtifactRule("rules", "price", &Rule{Type: ValidityRule, Config: "NONE"})).To(Succeed())
+	_, err = client.CreateArtifactVersion(&CreateVersionRequest{GroupId: "rules", ArtifactId: "price", Content: "{\"type\":"})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(client.DeleteArtifactRules("rules", "price")).To(Succeed())
+	rules, err := client.ListArtifactRules("rules", "price")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(rules).To(BeEmpty())
+}
+
+func TestSearchFiltersArtifactsByNameDescriptionLabelsAndProperties(t *testing.T) {
+	g := NewWithT(t)
+	_, client := newFakeClient(t)
+	seedSearch(g, client)
+	g.Expect(searchIds(g, client, &ArtifactSearchRequest{Name: "order"})).To(ConsistOf("search-0", "search-2"))
+	g.Expect(searchIds(g, client, &ArtifactSearchRequest{Description: "of payments"})).To(ConsistOf("search-1"))
+	g.Expect(searchIds(g, client, &ArtifactSearchRequest{Labels: []string{"search", "orders"}})).To(ConsistOf("search-0"))
+	g.Expect(searchIds(g, client, &ArtifactSearchRequest{Properties: map[string]string{"team": "team-0"}})).To(ConsistOf("search-0", "search-2"))
+	g.Expect(searchIds(g, client, &ArtifactSearchRequest{Group: "other"})).To(ConsistOf("unrelated"))
+	g.Expect(searchIds(g, client, &ArtifactSearchRequest{Name: "missing"})).To(BeEmpty())
+}
+
+func TestSearchSortsAndPagesArtifacts(t *testing.T) {
+	g := NewWithT(t)
+	_, client := newFakeClient(t)
+	seedSearch(g, client)
+	req := &ArtifactSearchRequest{Group: "search", OrderBy: OrderByName, Order: OrderDesc, Limit: 2}
+	results, err := client.SearchArtifacts(req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(results.Count).To(Equal(3))
+	g.Expect(searchIds(g, client, req)).To(Equal([]string{"search-1", "search-0"}))
+
+	req.Offset = 2
+	g.Expect(searchIds(g, client, req)).To(Equal([]string{"search-2"}))
+}
+
+func TestSearchFiltersVersionsAcrossArtifacts(t *testing.T) {
+	g := NewWithT(t)
+	_, client := newFakeClient(t)
+	seedSearch(g, client)
+	_, err := client.CreateArtifactVersion(&CreateVersionRequest{GroupId: "search", ArtifactId: "search-0", ArtifactType: Avro, Content: "{\"type\":\"int\"}"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(client.UpdateArtifactVersionState("search", "search-0", "1", Deprecated)).To(Succeed())
+
+	results, err := client.SearchVersions(&VersionSearchRequest{GroupId: "search", ArtifactId: "search-0"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(results.Count).To(Equal(2))
+	g.Expect(results.Versions[0].ArtifactId).To(Equal("search-0"))
+
+	results, err = client.SearchVersions(&VersionSearchRequest{Labels: []string{"search"}, State: Deprecated})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(results.Versions).To(HaveLen(1))
+	g.Expect(results.Versions[0].Version).To(Equal("1"))
+}
+
+func TestReferencesCreatesAndDereferencesVersions(t *testing.T) {
+	g := NewWithT(t)
+	_, client := newFakeClient(t)
+	_, err := client.CreateArtifactInGroup(&CreateArtifactRequest{GroupId: "refs", ArtifactId: "address", ArtifactType: JsonSchema, Content: "{\"type\":\"object\",\"properties\":{\"city\":{\"type\":\"string\"}}}"})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	references := []ArtifactReference{{GroupId: "refs", ArtifactId: "address", Version: "1", Name: "address.json"}}
+	content := "{\"type\":\"object\",\"properties\":{\"address\":{\"$ref\":\"address.json\"}}}"
+	metadata, err := client.CreateArtifactInGroup(&CreateArtifactRequest{GroupId: "refs", ArtifactId: "customer", ArtifactType: JsonSchema, Content: content, References: references})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	found, err := client.ListArtifactVersionReferences("refs", "customer", metadata.Version)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(found).To(Equal(references))
+	found, err = client.ListReferencesByGlobalId(metadata.GlobalId)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(found).To(Equal(references))
+
+	raw, err := client.ReadArtifactVersion("refs", "customer", metadata.Version)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(raw).To(Equal(content))
+
+	expected := "{\"type\":\"object\",\"properties\":{\"address\":{\"type\":\"object\",\"properties\":{\"city\":{\"type\":\"string\"}}}}}"
+	dereferenced, err := client.ReadDereferencedArtifactVersion("refs", "customer", metadata.Version)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(dereferenced).To(MatchJSON(expected))
+	dereferenced, err = client.ReadDereferencedContentByGlobalId(metadata.GlobalId)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(dereferenced).To(MatchJSON(expected))
+
+	found, err = client.ListArtifactVersionReferences("refs", "address", "1")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(found).To(BeEmpty())
+}
+
+func TestAdminExportsAndImportsEveryArtifact(t *testing.T) {
+	g := NewWithT(t)
+	_, client := newFakeClient(t)
+	createAvro(g, client, "export", "price")
+	g.Expect(client.CreateGlobalRule(&Rule{Type: ValidityRule, Config: "FULL"})).To(Succeed())
+	data, err := client.ExportData()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	other := NewFakeRegistry()
+	defer other.Close()
+	g.Expect(other.Client().ImportData(data)).To(Succeed())
+
+	metadata, err := other.Client().GetArtifactMetaData("export", "price")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(metadata.GlobalId).To(BeEquivalentTo(1))
+	rules, err := other.Client().ListGlobalRules()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(rules).To(ConsistOf(ValidityRule))
+
+	g.Expect(IsConflict(other.Client().ImportData(data))).To(BeTrue())
+}
+
+func TestAuthenticationSendsCredentialsWithEveryRequest(t *testing.T) {
+	g := NewWithT(t)
+	registry, _ := newFakeClient(t)
+	authenticated := NewAuthenticatedApicurioRegistryApiClient(registry.Host(), registry.Port(), http.DefaultClient, NewBasicAuth("alice", "secret"))
+	metadata, err := authenticated.CreateArtifactInGroup(&CreateArtifactRequest{GroupId: "auth", ArtifactId: "price", ArtifactType: Avro, Content: "{\"type\":\"string\"}"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(metadata.CreatedBy).To(Equal("alice"))
+}
+
+func TestDecodesRegistryErrorBodies(t *testing.T) {
+	g := NewWithT(t)
+	err := newRegistryError(http.StatusNotFound, []byte("{\"error_code\":404,\"message\":\"No artifact\",\"detail\":\"trace\",\"name\":\"ArtifactNotFoundException\"}"))
+	g.Expect(err.StatusCode).To(Equal(http.StatusNotFound))
+	g.Expect(err.Name).To(Equal("ArtifactNotFoundException"))
+	g.Expect(err.Detail).To(Equal("trace"))
+	g.Expect(err.Error()).To(Equal("registry responded with status 404 ArtifactNotFoundException: No artifact"))
+	g.Expect(IsNotFound(err)).To(BeTrue())
+	g.Expect(IsConflict(err)).To(BeFalse())
+}
+
+func TestKeepsNonJsonBodiesAsTheMessage(t *testing.T) {
+	g := NewWithT(t)
+	err := newRegistryError(http.StatusUnauthorized, []byte("Unauthorized\n"))
+	g.Expect(err.ErrorCode).To(Equal(http.StatusUnauthorized))
+	g.Expect(err.Message).To(Equal("Unauthorized"))
+	g.Expect(IsUnauthorized(err)).To(BeTrue())
+}
+
+func TestMatchesWrappedErrors(t *testing.T) {
+	g := NewWithT(t)
+	err := fmt.Errorf("creating artifact: %w", newRegistryError(http.StatusForbidden, nil))
+	g.Expect(IsForbidden(err)).To(BeTrue())
+	_, ok := AsRegistryError(nil)
+	g.Expect(ok).To(BeFalse())
+}
@@ -0,0 +1,79 @@
+package resources
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+//RegistryError error response of the registry api, decoded from the json error body when the registry sends one
+type RegistryError struct {
+	//StatusCode http status code of the response
+	StatusCode int `json:"-"`
+	//ErrorCode error code reported by the registry, the same as the status code for the registry api and a confluent error code for the ccompat api, i.e. 40401
+	ErrorCode int `json:"error_code"`
+	//Name name of the registry exception, i.e. ArtifactNotFoundException
+	Name    string `json:"name"`
+	Message string `json:"message"`
+	Detail  string `json:"detail"`
+}
+
+func (e *RegistryError) Error() string {
+	msg := fmt.Sprintf("registry responded with status %v", e.StatusCode)
+	if e.Name != "" {
+		msg += " " + e.Name
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+//newRegistryError builds the error for an unexpected response, falling back to the raw body when it's not a registry error document
+func newRegistryError(statusCode int, body []byte) *RegistryError {
+	regErr := &RegistryError{}
+	if err := json.Unmarshal(body, regErr); err != nil {
+		regErr = &RegistryError{Message: strings.TrimSpace(string(body))}
+	}
+	regErr.StatusCode = statusCode
+	if regErr.ErrorCode == 0 {
+		regErr.ErrorCode = statusCode
+	}
+	return regErr
+}
+
+//AsRegistryError returns the registry error wrapped in err, if any
+func AsRegistryError(err error) (*RegistryError, bool) {
+	var regErr *RegistryError
+	if errors.As(err, &regErr) {
+		return regErr, true
+	}
+	return nil, false
+}
+
+//IsNotFound true if the registry responded the requested resource does not exist
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+//IsConflict true if the registry rejected the request because it conflicts with stored data, i.e. the artifact already exists or a rule is violated
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+//IsUnauthorized true if the registry rejected the credentials of the request
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+//IsForbidden true if the registry denied the operation to the authenticated principal
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+func hasStatus(err error, statusCode int) bool {
+	regErr, ok := AsRegistryError(err)
+	return ok && regErr.StatusCode == statusCode
+}
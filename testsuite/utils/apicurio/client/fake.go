@@ -0,0 +1,879 @@
+package resources
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//fakeExportEntry name of the only file inside the ZIP files exported by FakeRegistry
+const fakeExportEntry string = "registry.json"
+
+//FakeRegistry in-memory implementation of the registry REST API, meant to unit test the client and the helpers built on top of it without a cluster.
+//It covers the legacy artifacts api, artifacts, versions, metadata, states, rules, references, search, ids, export/import, the registry error format, read-only principals,
+//and the health, system info and metrics endpoints.
+//Rules are stored but only the validity rule is enforced, content of json based artifact types must be valid json when it's enabled.
+type FakeRegistry struct {
+	server *httptest.Server
+
+	mu    sync.Mutex
+	state *fakeState
+	clock func() time.Time
+	//requests count of the requests served, per method, exposed as a metric
+	requests map[string]int
+	//down names of the health checks reporting DOWN
+	down map[string]bool
+	//readOnly principals that can only read, like users with the read-only role when role based authorization is enabled
+	readOnly map[string]bool
+}
+
+type fakeState struct {
+	NextGlobalId  int64                    `json:"nextGlobalId"`
+	NextContentId int64                    `json:"nextContentId"`
+	Contents      map[int64]string         `json:"contents"`
+	Artifacts     map[string]*fakeArtifact `json:"artifacts"`
+	GlobalRules   map[RuleType]string      `json:"globalRules"`
+}
+
+type fakeArtifact struct {
+	GroupId     string              `json:"groupId"`
+	Id          string              `json:"id"`
+	Type        ArtifactType        `json:"type"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Labels      []string            `json:"labels"`
+	Properties  map[string]string   `json:"properties"`
+	CreatedBy   string              `json:"createdBy"`
+	CreatedOn   string              `json:"createdOn"`
+	ModifiedOn  string              `json:"modifiedOn"`
+	Rules       map[RuleType]string `json:"rules"`
+	Versions    []*fakeVersion      `json:"versions"`
+}
+
+type fakeVersion struct {
+	Version     string              `json:"version"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	CreatedBy   string              `json:"createdBy"`
+	CreatedOn   string              `json:"createdOn"`
+	GlobalId    int64               `json:"globalId"`
+	ContentId   int64               `json:"contentId"`
+	State       ArtifactState       `json:"state"`
+	References  []ArtifactReference `json:"references,omitempty"`
+}
+
+//NewFakeRegistry starts a fake registry listening on a local port, callers must Close it
+func NewFakeRegistry() *FakeRegistry {
+	f := &FakeRegistry{
+		state:    newFakeState(),
+		clock:    time.Now,
+		requests: map[string]int{},
+		down:     map[string]bool{},
+		readOnly: map[string]bool{},
+	}
+	f.server = httptest.NewServer(f)
+	return f
+}
+
+func newFakeState() *fakeState {
+	return &fakeState{
+		NextGlobalId:  1,
+		NextContentId: 1,
+		Contents:      map[int64]string{},
+		Artifacts:     map[string]*fakeArtifact{},
+		GlobalRules:   map[RuleType]string{},
+	}
+}
+
+//URL base url of the fake registry, i.e. http://127.0.0.1:34567
+func (f *FakeRegistry) URL() string {
+	return f.server.URL
+}
+
+//Host host part of the url of the fake registry
+func (f *FakeRegistry) Host() string {
+	u, _ := url.Parse(f.server.URL)
+	return u.Hostname()
+}
+
+//Port port part of the url of the fake registry
+func (f *FakeRegistry) Port() string {
+	u, _ := url.Parse(f.server.URL)
+	return u.Port()
+}
+
+//Client returns a registry api client connected to the fake registry
+func (f *FakeRegistry) Client() ApicurioRegistryApiClient {
+	return newApicurioRegistryApiClient(f.server.URL, f.server.Client(), nil)
+}
+
+func (f *FakeRegistry) Close() {
+	f.server.Close()
+}
+
+//SetReadOnly gives the principal read-only access, the registry forbids it any other request
+func (f *FakeRegistry) SetReadOnly(principal string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.readOnly[principal] = true
+}
+
+func (f *FakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests[r.Method]++
+	path := r.URL.EscapedPath()
+	if r.Method != http.MethodGet && f.readOnly[principal(r)] {
+		writeFakeError(w, http.StatusForbidden, "ForbiddenException", "User "+principal(r)+" is not authorized to perform the requested operation.")
+		return
+	}
+	switch {
+	case strings.HasPrefix(path, "/health/"):
+		f.serveHealth(w, strings.TrimPrefix(path, "/health/"))
+	case path == "/metrics":
+		f.serveMetrics(w)
+	case path == registryV2Path+"/system/info":
+		writeFakeJSON(w, http.StatusOK, fakeSystemInfo)
+	case path == "/api/artifacts" || strings.HasPrefix(path, "/api/artifacts/"):
+		f.serveLegacy(w, r, splitPath(strings.TrimPrefix(path, "/api/artifacts")))
+	case strings.HasPrefix(path, registryV2Path+"/"):
+		f.serveV2(w, r, splitPath(strings.TrimPrefix(path, registryV2Path)))
+	default:
+		writeFakeError(w, http.StatusNotFound, "NotFoundException", "RESTEASY003210: Could not find resource for full path: "+path)
+	}
+}
+
+func splitPath(path string) []string {
+	segments := make([]string, 0)
+	for _, s := range strings.Split(strings.Trim(path, "/"), "/") {
+		if s == "" {
+			continue
+		}
+		unescaped, err := url.PathUnescape(s)
+		if err != nil {
+			unescaped = s
+		}
+		segments = append(segments, unescaped)
+	}
+	return segments
+}
+
+func (f *FakeRegistry) serveLegacy(w http.ResponseWriter, r *http.Request, segments []string) {
+	switch {
+	case len(segments) == 0 && r.Method == http.MethodGet:
+		ids := make([]string, 0)
+		for _, a := range f.sortedArtifacts() {
+			if a.GroupId == DefaultGroup {
+				ids = append(ids, a.Id)
+			}
+		}
+		writeFakeJSON(w, http.StatusOK, ids)
+	case len(segments) == 0 && r.Method == http.MethodPost:
+		artifactType := ArtifactType("")
+		if _, params, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil {
+			artifactType = ArtifactType(params["artifacttype"])
+		}
+		f.createArtifact(w, r, DefaultGroup, artifactType)
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		f.readLatest(w, DefaultGroup, segments[0])
+	case len(segments) == 1 && r.Method == http.MethodDelete:
+		f.deleteArtifact(w, DefaultGroup, segments[0])
+	default:
+		writeFakeMethodNotAllowed(w, r)
+	}
+}
+
+func (f *FakeRegistry) serveV2(w http.ResponseWriter, r *http.Request, segments []string) {
+	n := len(segments)
+	switch {
+	case n == 2 && segments[0] == "search" && segments[1] == "artifacts" && r.Method == http.MethodGet:
+		f.searchArtifacts(w, r)
+	case n == 2 && segments[0] == "search" && segments[1] == "versions" && r.Method == http.MethodGet:
+		f.searchVersions(w, r)
+	case n == 2 && segments[0] == "admin" && segments[1] == "export" && r.Method == http.MethodGet:
+		f.exportData(w)
+	case n == 2 && segments[0] == "admin" && segments[1] == "import" && r.Method == http.MethodPost:
+		f.importData(w, r)
+	case n >= 2 && segments[0] == "admin" && segments[1] == "rules":
+		f.serveRules(w, r, f.state.GlobalRules, segments[2:])
+	case n == 3 && segments[0] == "ids":
+		f.readContentById(w, r, segments[1], segments[2])
+	case n == 4 && segments[0] == "ids" && segments[1] == "globalIds" && segments[3] == "references":
+		f.readReferencesByGlobalId(w, segments[2])
+	case n == 3 && segments[0] == "groups" && segments[2] == "artifacts":
+		f.serveGroup(w, r, segments[1])
+	case n >= 4 && segments[0] == "groups" && segments[2] == "artifacts":
+		f.serveArtifact(w, r, segments[1], segments[3], segments[4:])
+	default:
+		writeFakeMethodNotAllowed(w, r)
+	}
+}
+
+func (f *FakeRegistry) serveGroup(w http.ResponseWriter, r *http.Request, groupId string) {
+	switch r.Method {
+	case http.MethodGet:
+		artifacts := make([]SearchedArtifact, 0)
+		for _, a := range f.sortedArtifacts() {
+			if a.GroupId == groupId {
+				artifacts = append(artifacts, a.searched())
+			}
+		}
+		from, to := pageBounds(r, len(artifacts))
+		writeFakeJSON(w, http.StatusOK, ArtifactSearchResults{Artifacts: artifacts[from:to], Count: len(artifacts)})
+	case http.MethodPost:
+		f.createArtifact(w, r, groupId, ArtifactType(r.Header.Get("X-Registry-ArtifactType")))
+	case http.MethodDelete:
+		for key, a := range f.state.Artifacts {
+			if a.GroupId == groupId {
+				delete(f.state.Artifacts, key)
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeMethodNotAllowed(w, r)
+	}
+}
+
+func (f *FakeRegistry) serveArtifact(w http.ResponseWriter, r *http.Request, groupId string, artifactId string, segments []string) {
+	artifact := f.state.Artifacts[fakeKey(groupId, artifactId)]
+	if artifact == nil {
+		writeArtifactNotFound(w, groupId, artifactId)
+		return
+	}
+
+	n := len(segments)
+	switch {
+	case n == 0 && r.Method == http.MethodGet:
+		f.writeVersionContent(w, r, artifact, artifact.latest())
+	case n == 0 && r.Method == http.MethodDelete:
+		f.deleteArtifact(w, groupId, artifactId)
+	case n == 1 && segments[0] == "meta" && r.Method == http.MethodGet:
+		writeFakeJSON(w, http.StatusOK, artifact.metadata())
+	case n == 1 && segments[0] == "meta" && r.Method == http.MethodPut:
+		metadata := &EditableMetaData{}
+		if !readFakeJSON(w, r, metadata) {
+			return
+		}
+		artifact.Name = metadata.Name
+		artifact.Description = metadata.Description
+		artifact.Labels = metadata.Labels
+		artifact.Properties = metadata.Properties
+		artifact.ModifiedOn = f.now()
+		w.WriteHeader(http.StatusNoContent)
+	case n == 1 && segments[0] == "state" && r.Method == http.MethodPut:
+		f.updateState(w, r, artifact.latest())
+	case n == 1 && segments[0] == "versions" && r.Method == http.MethodGet:
+		versions := make([]SearchedVersion, 0)
+		for _, v := range artifact.Versions {
+			versions = append(versions, artifact.searchedVersion(v))
+		}
+		from, to := pageBounds(r, len(versions))
+		writeFakeJSON(w, http.StatusOK, VersionSearchResults{Versions: versions[from:to], Count: len(versions)})
+	case n == 1 && segments[0] == "versions" && r.Method == http.MethodPost:
+		f.createVersion(w, r, artifact)
+	case n >= 2 && segments[0] == "versions":
+		version := artifact.version(segments[1])
+		if version == nil {
+			writeFakeError(w, http.StatusNotFound, "VersionNotFoundException", fmt.Sprintf("No version '%v' found for artifact with ID '%v' in group '%v'.", segments[1], artifactId, groupId))
+			return
+		}
+		switch {
+		case n == 2 && r.Method == http.MethodGet:
+			f.writeVersionContent(w, r, artifact, version)
+		case n == 3 && segments[2] == "references" && r.Method == http.MethodGet:
+			writeFakeJSON(w, http.StatusOK, version.references())
+		case n == 3 && segments[2] == "meta" && r.Method == http.MethodGet:
+			writeFakeJSON(w, http.StatusOK, artifact.versionMetadata(version))
+		case n == 3 && segments[2] == "state" && r.Method == http.MethodPut:
+			f.updateState(w, r, version)
+		default:
+			writeFakeMethodNotAllowed(w, r)
+		}
+	case segments[0] == "rules":
+		f.serveRules(w, r, artifact.Rules, segments[1:])
+	default:
+		writeFakeMethodNotAllowed(w, r)
+	}
+}
+
+//serveRules handles both global and artifact rules, that share the same api
+func (f *FakeRegistry) serveRules(w http.ResponseWriter, r *http.Request, rules map[RuleType]string, segments []string) {
+	if len(segments) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			ruleTypes := make([]RuleType, 0)
+			for t := range rules {
+				ruleTypes = append(ruleTypes, t)
+			}
+			sort.Slice(ruleTypes, func(i, j int) bool { return ruleTypes[i] < ruleTypes[j] })
+			writeFakeJSON(w, http.StatusOK, ruleTypes)
+		case http.MethodPost:
+			rule := &Rule{}
+			if !readFakeJSON(w, r, rule) {
+				return
+			}
+			if _, exists := rules[rule.Type]; exists {
+				writeFakeError(w, http.StatusConflict, "RuleAlreadyExistsException", fmt.Sprintf("A rule named '%v' already exists.", rule.Type))
+				return
+			}
+			rules[rule.Type] = rule.Config
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			for t := range rules {
+				delete(rules, t)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeFakeMethodNotAllowed(w, r)
+		}
+		return
+	}
+
+	ruleType := RuleType(segments[0])
+	config, exists := rules[ruleType]
+	if !exists {
+		writeFakeError(w, http.StatusNotFound, "RuleNotFoundException", fmt.Sprintf("No rule named '%v' was found.", ruleType))
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeFakeJSON(w, http.StatusOK, Rule{Type: ruleType, Config: config})
+	case http.MethodPut:
+		rule := &Rule{}
+		if !readFakeJSON(w, r, rule) {
+			return
+		}
+		rules[ruleType] = rule.Config
+		writeFakeJSON(w, http.StatusOK, Rule{Type: ruleType, Config: rule.Config})
+	case http.MethodDelete:
+		delete(rules, ruleType)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeMethodNotAllowed(w, r)
+	}
+}
+
+func (f *FakeRegistry) createArtifact(w http.ResponseWriter, r *http.Request, groupId string, artifactType ArtifactType) {
+	artifactId := r.Header.Get("X-Registry-ArtifactId")
+	if artifactId == "" {
+		artifactId = fmt.Sprintf("generated-%v", f.state.NextGlobalId)
+	}
+	if f.state.Artifacts[fakeKey(groupId, artifactId)] != nil {
+		writeFakeError(w, http.StatusConflict, "ArtifactAlreadyExistsException", fmt.Sprintf("An artifact with ID '%v' in group '%v' already exists.", artifactId, groupId))
+		return
+	}
+	if artifactType == "" {
+		//the real registry infers the type from the content, the fake just assumes avro
+		artifactType = Avro
+	}
+
+	content, references, ok := readFakeContent(w, r)
+	if !ok {
+		return
+	}
+	artifact := &fakeArtifact{
+		GroupId:     groupId,
+		Id:          artifactId,
+		Type:        artifactType,
+		Name:        r.Header.Get("X-Registry-Name"),
+		Description: r.Header.Get("X-Registry-Description"),
+		CreatedBy:   principal(r),
+		CreatedOn:   f.now(),
+		ModifiedOn:  f.now(),
+		Rules:       map[RuleType]string{},
+	}
+	if !f.checkRules(w, artifact, content) {
+		return
+	}
+	f.addVersion(artifact, r, content, references)
+	f.state.Artifacts[fakeKey(groupId, artifactId)] = artifact
+	writeFakeJSON(w, http.StatusOK, artifact.metadata())
+}
+
+func (f *FakeRegistry) createVersion(w http.ResponseWriter, r *http.Request, artifact *fakeArtifact) {
+	content, references, ok := readFakeContent(w, r)
+	if !ok {
+		return
+	}
+	if version := r.Header.Get("X-Registry-Version"); version != "" && artifact.version(version) != nil {
+		writeFakeError(w, http.StatusConflict, "VersionAlreadyExistsException", fmt.Sprintf("An artifact with ID '%v' in group '%v' already has a version '%v'.", artifact.Id, artifact.GroupId, version))
+		return
+	}
+	if !f.checkRules(w, artifact, content) {
+		return
+	}
+	version := f.addVersion(artifact, r, content, references)
+	artifact.ModifiedOn = f.now()
+	writeFakeJSON(w, http.StatusOK, artifact.versionMetadata(version))
+}
+
+func (f *FakeRegistry) addVersion(artifact *fakeArtifact, r *http.Request, content string, references []ArtifactReference) *fakeVersion {
+	version := &fakeVersion{
+		Version:     r.Header.Get("X-Registry-Version"),
+		Name:        r.Header.Get("X-Registry-Name"),
+		Description: r.Header.Get("X-Registry-Description"),
+		CreatedBy:   principal(r),
+		CreatedOn:   f.now(),
+		GlobalId:    f.state.NextGlobalId,
+		ContentId:   f.contentId(content),
+		State:       Enabled,
+		References:  references,
+	}
+	if version.Version == "" {
+		version.Version = strconv.Itoa(len(artifact.Versions) + 1)
+	}
+	f.state.NextGlobalId++
+	artifact.Versions = append(artifact.Versions, version)
+	return version
+}
+
+//checkRules enforces the validity rule configured for the artifact, or globally
+func (f *FakeRegistry) checkRules(w http.ResponseWriter, artifact *fakeArtifact, content string) bool {
+	validity, exists := artifact.Rules[ValidityRule]
+	if !exists {
+		validity, exists = f.state.GlobalRules[ValidityRule]
+	}
+	if !exists || validity == "NONE" || !artifact.Type.isJSON() {
+		return true
+	}
+	if !json.Valid([]byte(content)) {
+		writeFakeError(w, http.StatusConflict, "RuleViolationException", fmt.Sprintf("Syntax violation for %v artifact.", artifact.Type))
+		return false
+	}
+	return true
+}
+
+func (f *FakeRegistry) contentId(content string) int64 {
+	for id, c := range f.state.Contents {
+		if c == content {
+			return id
+		}
+	}
+	id := f.state.NextContentId
+	f.state.NextContentId++
+	f.state.Contents[id] = content
+	return id
+}
+
+func (f *FakeRegistry) readLatest(w http.ResponseWriter, groupId string, artifactId string) {
+	artifact := f.state.Artifacts[fakeKey(groupId, artifactId)]
+	if artifact == nil {
+		writeArtifactNotFound(w, groupId, artifactId)
+		return
+	}
+	writeFakeContent(w, f.state.Contents[artifact.latest().ContentId])
+}
+
+func (f *FakeRegistry) deleteArtifact(w http.ResponseWriter, groupId string, artifactId string) {
+	if f.state.Artifacts[fakeKey(groupId, artifactId)] == nil {
+		writeArtifactNotFound(w, groupId, artifactId)
+		return
+	}
+	delete(f.state.Artifacts, fakeKey(groupId, artifactId))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *FakeRegistry) updateState(w http.ResponseWriter, r *http.Request, version *fakeVersion) {
+	state := &updateState{}
+	if !readFakeJSON(w, r, state) {
+		return
+	}
+	version.State = state.State
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *FakeRegistry) readContentById(w http.ResponseWriter, r *http.Request, idType string, rawId string) {
+	id, err := strconv.ParseInt(rawId, 10, 64)
+	if err != nil {
+		writeFakeError(w, http.StatusBadRequest, "BadRequestException", "invalid id "+rawId)
+		return
+	}
+	switch idType {
+	case "contentIds":
+		if content, exists := f.state.Contents[id]; exists {
+			writeFakeContent(w, content)
+			return
+		}
+	case "globalIds":
+		for _, a := range f.state.Artifacts {
+			for _, v := range a.Versions {
+				if v.GlobalId == id {
+					f.writeVersionContent(w, r, a, v)
+					return
+				}
+			}
+		}
+	}
+	writeFakeError(w, http.StatusNotFound, "ContentNotFoundException", fmt.Sprintf("No content with id '%v' was found.", id))
+}
+
+func (f *FakeRegistry) searchArtifacts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	group := query.Get("group")
+	matches := make([]*fakeArtifact, 0)
+	for _, a := range f.sortedArtifacts() {
+		if group != "" && a.GroupId != group {
+			continue
+		}
+		if !fakeMatchesName(query.Get("name"), a.Name, a.Id) || !fakeContains(a.Description, query.Get("description")) {
+			continue
+		}
+		if !fakeMatchesFilters(query, a.Labels, a.Properties) {
+			continue
+		}
+		matches = append(matches, a)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return fakeLess(query, matches[i].Name, matches[j].Name, matches[i].CreatedOn, matches[j].CreatedOn)
+	})
+
+	artifacts := make([]SearchedArtifact, 0, len(matches))
+	for _, a := range matches {
+		artifacts = append(artifacts, a.searched())
+	}
+	from, to := pageBounds(r, len(artifacts))
+	writeFakeJSON(w, http.StatusOK, ArtifactSearchResults{Artifacts: artifacts[from:to], Count: len(artifacts)})
+}
+
+//searchVersions the fake keeps labels and properties per artifact, so versions are filtered using the ones of their artifact
+func (f *FakeRegistry) searchVersions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	versions := make([]SearchedVersion, 0)
+	for _, a := range f.sortedArtifacts() {
+		if !fakeMatchesParam(query, "groupId", a.GroupId) || !fakeMatchesParam(query, "artifactId", a.Id) {
+			continue
+		}
+		if !fakeMatchesFilters(query, a.Labels, a.Properties) {
+			continue
+		}
+		for _, v := range a.Versions {
+			if !fakeMatchesParam(query, "version", v.Version) || !fakeMatchesParam(query, "state", string(v.State)) {
+				continue
+			}
+			if !fakeMatchesName(query.Get("name"), v.Name, "") || !fakeContains(v.Description, query.Get("description")) {
+				continue
+			}
+			version := a.searchedVersion(v)
+			version.GroupId = a.GroupId
+			version.ArtifactId = a.Id
+			versions = append(versions, version)
+		}
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return fakeLess(query, versions[i].Name, versions[j].Name, versions[i].CreatedOn, versions[j].CreatedOn)
+	})
+	from, to := pageBounds(r, len(versions))
+	writeFakeJSON(w, http.StatusOK, VersionSearchResults{Versions: versions[from:to], Count: len(versions)})
+}
+
+func (f *FakeRegistry) exportData(w http.ResponseWriter) {
+	data, err := json.Marshal(f.state)
+	if err != nil {
+		writeFakeError(w, http.StatusInternalServerError, "RegistryException", err.Error())
+		return
+	}
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	entry, err := zw.Create(fakeExportEntry)
+	if err == nil {
+		_, err = entry.Write(data)
+	}
+	if err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		writeFakeError(w, http.StatusInternalServerError, "RegistryException", err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+func (f *FakeRegistry) importData(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeFakeError(w, http.StatusBadRequest, "BadRequestException", err.Error())
+		return
+	}
+	if len(f.state.Artifacts) != 0 {
+		writeFakeError(w, http.StatusConflict, "ConflictException", "the registry already contains data")
+		return
+	}
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		writeFakeError(w, http.StatusBadRequest, "BadRequestException", err.Error())
+		return
+	}
+	for _, file := range zr.File {
+		if file.Name != fakeExportEntry {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			writeFakeError(w, http.StatusBadRequest, "BadRequestException", err.Error())
+			return
+		}
+		defer rc.Close()
+		state := newFakeState()
+		if err := json.NewDecoder(rc).Decode(state); err != nil {
+			writeFakeError(w, http.StatusBadRequest, "BadRequestException", err.Error())
+			return
+		}
+		f.state = state
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeFakeError(w, http.StatusBadRequest, "BadRequestException", "missing "+fakeExportEntry)
+}
+
+func (f *FakeRegistry) sortedArtifacts() []*fakeArtifact {
+	artifacts := make([]*fakeArtifact, 0, len(f.state.Artifacts))
+	for _, a := range f.state.Artifacts {
+		artifacts = append(artifacts, a)
+	}
+	sort.Slice(artifacts, func(i, j int) bool {
+		return fakeKey(artifacts[i].GroupId, artifacts[i].Id) < fakeKey(artifacts[j].GroupId, artifacts[j].Id)
+	})
+	return artifacts
+}
+
+func (f *FakeRegistry) now() string {
+	return f.clock().UTC().Format("2006-01-02T15:04:05Z")
+}
+
+func (a *fakeArtifact) latest() *fakeVersion {
+	for i := len(a.Versions) - 1; i >= 0; i-- {
+		if a.Versions[i].State != Disabled {
+			return a.Versions[i]
+		}
+	}
+	return a.Versions[len(a.Versions)-1]
+}
+
+func (a *fakeArtifact) version(version string) *fakeVersion {
+	if version == "latest" {
+		return a.latest()
+	}
+	for _, v := range a.Versions {
+		if v.Version == version {
+			return v
+		}
+	}
+	return nil
+}
+
+func (a *fakeArtifact) metadata() ArtifactMetaData {
+	latest := a.latest()
+	return ArtifactMetaData{
+		GroupId:     a.GroupId,
+		Id:          a.Id,
+		Name:        a.Name,
+		Description: a.Description,
+		CreatedBy:   a.CreatedBy,
+		CreatedOn:   a.CreatedOn,
+		ModifiedOn:  a.ModifiedOn,
+		Version:     latest.Version,
+		Type:        a.Type,
+		GlobalId:    latest.GlobalId,
+		ContentId:   latest.ContentId,
+		State:       latest.State,
+		Labels:      a.Labels,
+		Properties:  a.Properties,
+	}
+}
+
+func (a *fakeArtifact) versionMetadata(v *fakeVersion) VersionMetaData {
+	return VersionMetaData{
+		GroupId:     a.GroupId,
+		Id:          a.Id,
+		Version:     v.Version,
+		Name:        v.Name,
+		Description: v.Description,
+		CreatedBy:   v.CreatedBy,
+		CreatedOn:   v.CreatedOn,
+		Type:        a.Type,
+		GlobalId:    v.GlobalId,
+		ContentId:   v.ContentId,
+		State:       v.State,
+	}
+}
+
+func (a *fakeArtifact) searched() SearchedArtifact {
+	return SearchedArtifact{
+		GroupId:     a.GroupId,
+		Id:          a.Id,
+		Name:        a.Name,
+		Description: a.Description,
+		CreatedBy:   a.CreatedBy,
+		CreatedOn:   a.CreatedOn,
+		ModifiedOn:  a.ModifiedOn,
+		Type:        a.Type,
+		State:       a.latest().State,
+		Labels:      a.Labels,
+	}
+}
+
+func (a *fakeArtifact) searchedVersion(v *fakeVersion) SearchedVersion {
+	return SearchedVersion{
+		Version:     v.Version,
+		Name:        v.Name,
+		Description: v.Description,
+		CreatedBy:   v.CreatedBy,
+		CreatedOn:   v.CreatedOn,
+		Type:        a.Type,
+		GlobalId:    v.GlobalId,
+		ContentId:   v.ContentId,
+		State:       v.State,
+	}
+}
+
+func (t ArtifactType) isJSON() bool {
+	switch t {
+	case Avro, JsonSchema, OpenAPI, AsyncAPI, KConnect:
+		return true
+	}
+	return false
+}
+
+func fakeKey(groupId string, artifactId string) string {
+	return groupId + "/" + artifactId
+}
+
+//principal user of the request, only basic authentication is understood by the fake
+func principal(r *http.Request) string {
+	if user, _, ok := r.BasicAuth(); ok {
+		return user
+	}
+	return ""
+}
+
+//pageBounds range of a listing of size items selected by the offset and limit query params of the request
+//fakeMatchesName like the registry, the name filter matches substrings of the name or of the id
+func fakeMatchesName(filter string, name string, id string) bool {
+	return filter == "" || fakeContains(name, filter) || (id != "" && fakeContains(id, filter))
+}
+
+func fakeContains(value string, filter string) bool {
+	return strings.Contains(strings.ToLower(value), strings.ToLower(filter))
+}
+
+func fakeMatchesParam(query url.Values, name string, value string) bool {
+	filter := query.Get(name)
+	return filter == "" || filter == value
+}
+
+//fakeMatchesFilters every label must be present, ignoring case, and every key:value property must be set
+func fakeMatchesFilters(query url.Values, labels []string, properties map[string]string) bool {
+	for _, label := range query["labels"] {
+		found := false
+		for _, l := range labels {
+			if strings.EqualFold(l, label) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, property := range query["properties"] {
+		kv := strings.SplitN(property, ":", 2)
+		if len(kv) != 2 {
+			return false
+		}
+		if value, exists := properties[kv[0]]; !exists || value != kv[1] {
+			return false
+		}
+	}
+	return true
+}
+
+//fakeLess search results are sorted by name unless sorting by createdOn is requested, ascending by default
+func fakeLess(query url.Values, nameA string, nameB string, createdA string, createdB string) bool {
+	a, b := nameA, nameB
+	if SortBy(query.Get("orderby")) == OrderByCreatedOn {
+		a, b = createdA, createdB
+	}
+	if SortOrder(query.Get("order")) == OrderDesc {
+		return a > b
+	}
+	return a < b
+}
+
+func pageBounds(r *http.Request, size int) (int, int) {
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+	if offset > size {
+		offset = size
+	}
+	end := offset + limit
+	if end > size {
+		end = size
+	}
+	return offset, end
+}
+
+func readFakeBody(w http.ResponseWriter, r *http.Request) (string, bool) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeFakeError(w, http.StatusBadRequest, "BadRequestException", err.Error())
+		return "", false
+	}
+	return string(data), true
+}
+
+func readFakeJSON(w http.ResponseWriter, r *http.Request, out interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(out); err != nil {
+		writeFakeError(w, http.StatusBadRequest, "BadRequestException", err.Error())
+		return false
+	}
+	return true
+}
+
+func writeFakeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeFakeContent(w http.ResponseWriter, content string) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(content))
+}
+
+func writeArtifactNotFound(w http.ResponseWriter, groupId string, artifactId string) {
+	writeFakeError(w, http.StatusNotFound, "ArtifactNotFoundException", fmt.Sprintf("No artifact with ID '%v' in group '%v' was found.", artifactId, groupId))
+}
+
+func writeFakeMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeFakeError(w, http.StatusMethodNotAllowed, "NotAllowedException", fmt.Sprintf("%v %v is not supported by the fake registry", r.Method, r.URL.Path))
+}
+
+//writeFakeError writes an error body in the same format the registry uses
+func writeFakeError(w http.ResponseWriter, status int, name string, message string) {
+	writeFakeJSON(w, status, &RegistryError{
+		ErrorCode: status,
+		Name:      name,
+		Message:   message,
+		Detail:    "io.apicurio.registry." + name + ": " + message,
+	})
+}
@@ -0,0 +1,73 @@
+package resources
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+//FakeRegistryVersion version reported by the system info endpoint of FakeRegistry
+const FakeRegistryVersion string = "2.0.1.Final"
+
+var fakeSystemInfo = map[string]string{
+	"name":        "Apicurio Registry (Fake)",
+	"description": "In-memory fake of the Apicurio Registry API",
+	"version":     FakeRegistryVersion,
+	"builtOn":     "2021-06-01T00:00:00Z",
+}
+
+//fakeHealthChecks checks reported by the fake, named like the ones of the real registry
+var fakeHealthChecks = map[string][]string{
+	"ready": {"PersistenceSimpleReadinessCheck", "PersistenceTimeoutReadinessCheck", "ResponseTimeoutReadinessCheck"},
+	"live":  {"PersistenceExceptionLivenessCheck", "ResponseErrorLivenessCheck"},
+}
+
+//SetHealthCheckDown makes the named health check report DOWN, or UP again
+func (f *FakeRegistry) SetHealthCheckDown(name string, down bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.down[name] = down
+}
+
+func (f *FakeRegistry) serveHealth(w http.ResponseWriter, kind string) {
+	names, exists := fakeHealthChecks[kind]
+	if !exists {
+		writeFakeError(w, http.StatusNotFound, "NotFoundException", "no health endpoint "+kind)
+		return
+	}
+	status := "UP"
+	checks := []map[string]string{}
+	for _, name := range names {
+		checkStatus := "UP"
+		if f.down[name] {
+			checkStatus = "DOWN"
+			status = "DOWN"
+		}
+		checks = append(checks, map[string]string{"name": name, "status": checkStatus})
+	}
+	httpStatus := http.StatusOK
+	if status != "UP" {
+		httpStatus = http.StatusServiceUnavailable
+	}
+	writeFakeJSON(w, httpStatus, map[string]interface{}{"status": status, "checks": checks})
+}
+
+func (f *FakeRegistry) serveMetrics(w http.ResponseWriter) {
+	var metrics strings.Builder
+	metrics.WriteString("# HELP jvm_memory_used_bytes The amount of used memory\n")
+	metrics.WriteString("# TYPE jvm_memory_used_bytes gauge\n")
+	metrics.WriteString("jvm_memory_used_bytes{area=\"heap\",id=\"fake\",} 1.048576E7\n")
+	metrics.WriteString("# HELP rest_requests_total Requests served by the registry\n")
+	metrics.WriteString("# TYPE rest_requests_total counter\n")
+	methods := make([]string, 0, len(f.requests))
+	for method := range f.requests {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	for _, method := range methods {
+		metrics.WriteString(fmt.Sprintf("rest_requests_total{method=\"%v\",} %v.0\n", method, f.requests[method]))
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	writeFakeContent(w, metrics.String())
+}
@@ -0,0 +1,147 @@
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+//readFakeContent reads the content of a create request, along with its references when it's sent in the extended form
+func readFakeContent(w http.ResponseWriter, r *http.Request) (string, []ArtifactReference, bool) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != extendedContentType {
+		content, ok := readFakeBody(w, r)
+		return content, nil, ok
+	}
+	req := &contentCreateRequest{}
+	if !readFakeJSON(w, r, req) {
+		return "", nil, false
+	}
+	return req.Content, req.References, true
+}
+
+func (v *fakeVersion) references() []ArtifactReference {
+	if v.References == nil {
+		return []ArtifactReference{}
+	}
+	return v.References
+}
+
+func (f *FakeRegistry) readReferencesByGlobalId(w http.ResponseWriter, rawId string) {
+	id, err := strconv.ParseInt(rawId, 10, 64)
+	if err != nil {
+		writeFakeError(w, http.StatusBadRequest, "BadRequestException", "invalid id "+rawId)
+		return
+	}
+	for _, a := range f.state.Artifacts {
+		for _, v := range a.Versions {
+			if v.GlobalId == id {
+				writeFakeJSON(w, http.StatusOK, v.references())
+				return
+			}
+		}
+	}
+	writeFakeError(w, http.StatusNotFound, "ArtifactNotFoundException", fmt.Sprintf("No artifact with ID '%v' was found.", id))
+}
+
+//writeVersionContent writes the content of the version, with its references inlined if the request asks to dereference it
+func (f *FakeRegistry) writeVersionContent(w http.ResponseWriter, r *http.Request, artifact *fakeArtifact, version *fakeVersion) {
+	content := f.state.Contents[version.ContentId]
+	if r.URL.Query().Get("dereference") != "true" || len(version.References) == 0 {
+		writeFakeContent(w, content)
+		return
+	}
+	dereferenced, err := f.dereference(artifact.Type, content, version.References)
+	if err != nil {
+		writeFakeError(w, http.StatusBadRequest, "BadRequestException", err.Error())
+		return
+	}
+	writeFakeContent(w, dereferenced)
+}
+
+//dereference inlines the referenced contents, recursively. The fake only understands avro named types and json schema $refs,
+//each avro named type is inlined where it's first used, any other artifact type is returned unchanged
+func (f *FakeRegistry) dereference(artifactType ArtifactType, content string, references []ArtifactReference) (string, error) {
+	if artifactType != Avro && artifactType != JsonSchema {
+		return content, nil
+	}
+	var document interface{}
+	if err := json.Unmarshal([]byte(content), &document); err != nil {
+		return "", err
+	}
+	for _, ref := range references {
+		artifact := f.state.Artifacts[fakeKey(ref.GroupId, ref.ArtifactId)]
+		if artifact == nil {
+			return "", fmt.Errorf("reference %v points to a missing artifact %v/%v", ref.Name, ref.GroupId, ref.ArtifactId)
+		}
+		version := artifact.version(ref.Version)
+		if version == nil {
+			return "", fmt.Errorf("reference %v points to a missing version %v of %v/%v", ref.Name, ref.Version, ref.GroupId, ref.ArtifactId)
+		}
+		referenced, err := f.dereference(artifact.Type, f.state.Contents[version.ContentId], version.References)
+		if err != nil {
+			return "", err
+		}
+		var referencedDocument interface{}
+		if err := json.Unmarshal([]byte(referenced), &referencedDocument); err != nil {
+			return "", err
+		}
+		if artifactType == Avro {
+			document, _ = inlineAvroType(document, ref.Name, referencedDocument)
+		} else {
+			document = inlineJSONRef(document, ref.Name, referencedDocument)
+		}
+	}
+	data, err := json.Marshal(document)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+//inlineAvroType replaces the first use of the named type by its definition, returning whether it was found
+func inlineAvroType(node interface{}, name string, definition interface{}) (interface{}, bool) {
+	switch n := node.(type) {
+	case string:
+		if n == name {
+			return definition, true
+		}
+	case []interface{}:
+		for i := range n {
+			if replaced, found := inlineAvroType(n[i], name, definition); found {
+				n[i] = replaced
+				return n, true
+			}
+		}
+	case map[string]interface{}:
+		for _, key := range []string{"type", "fields", "items", "values"} {
+			if child, exists := n[key]; exists {
+				if replaced, found := inlineAvroType(child, name, definition); found {
+					n[key] = replaced
+					return n, true
+				}
+			}
+		}
+	}
+	return node, false
+}
+
+//inlineJSONRef replaces every {"$ref": name} object by the referenced schema
+func inlineJSONRef(node interface{}, name string, definition interface{}) interface{} {
+	switch n := node.(type) {
+	case []interface{}:
+		for i := range n {
+			n[i] = inlineJSONRef(n[i], name, definition)
+		}
+	case map[string]interface{}:
+		if ref, isRef := n["$ref"]; isRef && ref == name {
+			return definition
+		}
+		for key := range n {
+			n[key] = inlineJSONRef(n[key], name, definition)
+		}
+	}
+	return node
+}
@@ -0,0 +1,35 @@
+package resources
+
+import (
+	"embed"
+	"fmt"
+)
+
+//go:embed fixtures
+var fixtures embed.FS
+
+var fixtureFiles map[ArtifactType]string = map[ArtifactType]string{
+	Avro:       "fixtures/avro.avsc",
+	Protobuf:   "fixtures/protobuf.proto",
+	JsonSchema: "fixtures/json-schema.json",
+	OpenAPI:    "fixtures/openapi.json",
+	AsyncAPI:   "fixtures/asyncapi.json",
+	GraphQL:    "fixtures/graphql.graphql",
+	KConnect:   "fixtures/kconnect.json",
+	WSDL:       "fixtures/wsdl.wsdl",
+	XSD:        "fixtures/xsd.xsd",
+	XML:        "fixtures/xml.xml",
+}
+
+//SampleContent returns a valid sample artifact of the given type, useful to seed registries with realistic content
+func SampleContent(artifactType ArtifactType) (string, error) {
+	file, ok := fixtureFiles[artifactType]
+	if !ok {
+		return "", fmt.Errorf("no sample content for artifact type %v", artifactType)
+	}
+	data, err := fixtures.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
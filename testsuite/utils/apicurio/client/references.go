@@ -0,0 +1,46 @@
+package resources
+
+import (
+	"net/http"
+	"strconv"
+)
+
+//ListArtifactVersionReferences returns the references of one version of the artifact
+func (r *ApicurioRegistryApiClientImpl) ListArtifactVersionReferences(groupId string, artifactId string, version string) ([]ArtifactReference, error) {
+	references := make([]ArtifactReference, 0)
+	err := r.doJSON(http.MethodGet, r.v2URL("/groups/%v/artifacts/%v/versions/%v/references", groupOrDefault(groupId), artifactId, version), nil, &references, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return references, nil
+}
+
+func (r *ApicurioRegistryApiClientImpl) ListReferencesByGlobalId(globalId int64) ([]ArtifactReference, error) {
+	references := make([]ArtifactReference, 0)
+	err := r.doJSON(http.MethodGet, r.v2URL("/ids/globalIds/%v/references", strconv.FormatInt(globalId, 10)), nil, &references, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return references, nil
+}
+
+//ReadDereferencedArtifactVersion returns the content of one version of the artifact with every reference inlined.
+//Registries not supporting dereferencing ignore the request and return the content as it was created
+func (r *ApicurioRegistryApiClientImpl) ReadDereferencedArtifactVersion(groupId string, artifactId string, version string) (string, error) {
+	url := r.v2URL("/groups/%v/artifacts/%v/versions/%v", groupOrDefault(groupId), artifactId, version) + "?dereference=true"
+	data, err := r.doRequest(http.MethodGet, url, nil, nil, http.StatusOK)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+//ReadDereferencedContentByGlobalId returns the content of the version with the given global id with every reference inlined
+func (r *ApicurioRegistryApiClientImpl) ReadDereferencedContentByGlobalId(globalId int64) (string, error) {
+	url := r.v2URL("/ids/globalIds/%v", strconv.FormatInt(globalId, 10)) + "?dereference=true"
+	data, err := r.doRequest(http.MethodGet, url, nil, nil, http.StatusOK)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,77 @@
+package resources
+
+import (
+	"net/http"
+)
+
+func (r *ApicurioRegistryApiClientImpl) ListGlobalRules() ([]RuleType, error) {
+	rules := make([]RuleType, 0)
+	err := r.doJSON(http.MethodGet, r.v2URL("/admin/rules"), nil, &rules, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *ApicurioRegistryApiClientImpl) CreateGlobalRule(rule *Rule) error {
+	return r.doJSON(http.MethodPost, r.v2URL("/admin/rules"), rule, nil, http.StatusNoContent)
+}
+
+func (r *ApicurioRegistryApiClientImpl) GetGlobalRule(ruleType RuleType) (*Rule, error) {
+	rule := &Rule{}
+	err := r.doJSON(http.MethodGet, r.v2URL("/admin/rules/%v", string(ruleType)), nil, rule, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (r *ApicurioRegistryApiClientImpl) UpdateGlobalRule(rule *Rule) error {
+	return r.doJSON(http.MethodPut, r.v2URL("/admin/rules/%v", string(rule.Type)), rule, nil, http.StatusOK)
+}
+
+func (r *ApicurioRegistryApiClientImpl) DeleteGlobalRule(ruleType RuleType) error {
+	_, err := r.doRequest(http.MethodDelete, r.v2URL("/admin/rules/%v", string(ruleType)), nil, nil, http.StatusNoContent)
+	return err
+}
+
+func (r *ApicurioRegistryApiClientImpl) DeleteAllGlobalRules() error {
+	_, err := r.doRequest(http.MethodDelete, r.v2URL("/admin/rules"), nil, nil, http.StatusNoContent)
+	return err
+}
+
+func (r *ApicurioRegistryApiClientImpl) ListArtifactRules(groupId string, artifactId string) ([]RuleType, error) {
+	rules := make([]RuleType, 0)
+	err := r.doJSON(http.MethodGet, r.v2URL("/groups/%v/artifacts/%v/rules", groupOrDefault(groupId), artifactId), nil, &rules, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *ApicurioRegistryApiClientImpl) CreateArtifactRule(groupId string, artifactId string, rule *Rule) error {
+	return r.doJSON(http.MethodPost, r.v2URL("/groups/%v/artifacts/%v/rules", groupOrDefault(groupId), artifactId), rule, nil, http.StatusNoContent)
+}
+
+func (r *ApicurioRegistryApiClientImpl) GetArtifactRule(groupId string, artifactId string, ruleType RuleType) (*Rule, error) {
+	rule := &Rule{}
+	err := r.doJSON(http.MethodGet, r.v2URL("/groups/%v/artifacts/%v/rules/%v", groupOrDefault(groupId), artifactId, string(ruleType)), nil, rule, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (r *ApicurioRegistryApiClientImpl) UpdateArtifactRule(groupId string, artifactId string, rule *Rule) error {
+	return r.doJSON(http.MethodPut, r.v2URL("/groups/%v/artifacts/%v/rules/%v", groupOrDefault(groupId), artifactId, string(rule.Type)), rule, nil, http.StatusOK)
+}
+
+func (r *ApicurioRegistryApiClientImpl) DeleteArtifactRule(groupId string, artifactId string, ruleType RuleType) error {
+	_, err := r.doRequest(http.MethodDelete, r.v2URL("/groups/%v/artifacts/%v/rules/%v", groupOrDefault(groupId), artifactId, string(ruleType)), nil, nil, http.StatusNoContent)
+	return err
+}
+
+func (r *ApicurioRegistryApiClientImpl) DeleteArtifactRules(groupId string, artifactId string) error {
+	_, err := r.doRequest(http.MethodDelete, r.v2URL("/groups/%v/artifacts/%v/rules", groupOrDefault(groupId), artifactId), nil, nil, http.StatusNoContent)
+	return err
+}
@@ -0,0 +1,125 @@
+package resources
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+const (
+	OrderAsc  SortOrder = "asc"
+	OrderDesc SortOrder = "desc"
+)
+
+//SortOrder direction search results are sorted in
+type SortOrder string
+
+const (
+	OrderByName      SortBy = "name"
+	OrderByCreatedOn SortBy = "createdOn"
+)
+
+//SortBy field search results are sorted by
+type SortBy string
+
+//ArtifactSearchRequest filters and paging of an artifacts search, empty fields are not sent.
+//Name and Description match substrings, every label and every property must match, properties are matched by key and value
+type ArtifactSearchRequest struct {
+	Group       string
+	Name        string
+	Description string
+	Labels      []string
+	Properties  map[string]string
+
+	Offset  int
+	Limit   int
+	OrderBy SortBy
+	Order   SortOrder
+}
+
+//VersionSearchRequest filters and paging of a versions search across every artifact, empty fields are not sent
+type VersionSearchRequest struct {
+	GroupId     string
+	ArtifactId  string
+	Version     string
+	Name        string
+	Description string
+	Labels      []string
+	Properties  map[string]string
+	State       ArtifactState
+
+	Offset  int
+	Limit   int
+	OrderBy SortBy
+	Order   SortOrder
+}
+
+//SearchArtifacts returns one page of the artifacts matching the request
+func (r *ApicurioRegistryApiClientImpl) SearchArtifacts(req *ArtifactSearchRequest) (*ArtifactSearchResults, error) {
+	query := url.Values{}
+	addParam(query, "group", req.Group)
+	addParam(query, "name", req.Name)
+	addParam(query, "description", req.Description)
+	addFilters(query, req.Labels, req.Properties)
+	addPaging(query, req.Offset, req.Limit, req.OrderBy, req.Order)
+
+	results := &ArtifactSearchResults{}
+	err := r.doJSON(http.MethodGet, r.v2URL("/search/artifacts")+"?"+query.Encode(), nil, results, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+//SearchVersions returns one page of the versions matching the request, registries not serving the versions search respond 404
+func (r *ApicurioRegistryApiClientImpl) SearchVersions(req *VersionSearchRequest) (*VersionSearchResults, error) {
+	query := url.Values{}
+	addParam(query, "groupId", req.GroupId)
+	addParam(query, "artifactId", req.ArtifactId)
+	addParam(query, "version", req.Version)
+	addParam(query, "name", req.Name)
+	addParam(query, "description", req.Description)
+	addParam(query, "state", string(req.State))
+	addFilters(query, req.Labels, req.Properties)
+	addPaging(query, req.Offset, req.Limit, req.OrderBy, req.Order)
+
+	results := &VersionSearchResults{}
+	err := r.doJSON(http.MethodGet, r.v2URL("/search/versions")+"?"+query.Encode(), nil, results, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func addParam(query url.Values, name string, value string) {
+	if value != "" {
+		query.Set(name, value)
+	}
+}
+
+//addFilters labels and properties are sent as repeated params, properties in the key:value form the registry expects
+func addFilters(query url.Values, labels []string, properties map[string]string) {
+	for _, label := range labels {
+		query.Add("labels", label)
+	}
+	keys := make([]string, 0, len(properties))
+	for k := range properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		query.Add("properties", k+":"+properties[k])
+	}
+}
+
+func addPaging(query url.Values, offset int, limit int, orderBy SortBy, order SortOrder) {
+	if offset > 0 {
+		query.Set("offset", strconv.Itoa(offset))
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	addParam(query, "orderby", string(orderBy))
+	addParam(query, "order", string(order))
+}
@@ -0,0 +1,219 @@
+package resources
+
+//DefaultGroup is the group the registry uses for artifacts created without an explicit group
+const DefaultGroup = "default"
+
+const (
+	Enabled    ArtifactState = "ENABLED"
+	Disabled   ArtifactState = "DISABLED"
+	Deprecated ArtifactState = "DEPRECATED"
+)
+
+//ArtifactState lifecycle state of an artifact version
+type ArtifactState string
+
+//ArtifactMetaData metadata of the latest version of an artifact, as returned by the registry v2 API
+type ArtifactMetaData struct {
+	GroupId     string            `json:"groupId,omitempty"`
+	Id          string            `json:"id"`
+	Name        string            `json:"name,omitempty"`
+	Description string            `json:"description,omitempty"`
+	CreatedBy   string            `json:"createdBy,omitempty"`
+	CreatedOn   string            `json:"createdOn,omitempty"`
+	ModifiedBy  string            `json:"modifiedBy,omitempty"`
+	ModifiedOn  string            `json:"modifiedOn,omitempty"`
+	Version     string            `json:"version,omitempty"`
+	Type        ArtifactType      `json:"type,omitempty"`
+	GlobalId    int64             `json:"globalId,omitempty"`
+	ContentId   int64             `json:"contentId,omitempty"`
+	State       ArtifactState     `json:"state,omitempty"`
+	Labels      []string          `json:"labels,omitempty"`
+	Properties  map[string]string `json:"properties,omitempty"`
+}
+
+//VersionMetaData metadata of one specific version of an artifact
+type VersionMetaData struct {
+	GroupId     string            `json:"groupId,omitempty"`
+	Id          string            `json:"id"`
+	Version     string            `json:"version"`
+	Name        string            `json:"name,omitempty"`
+	Description string            `json:"description,omitempty"`
+	CreatedBy   string            `json:"createdBy,omitempty"`
+	CreatedOn   string            `json:"createdOn,omitempty"`
+	Type        ArtifactType      `json:"type,omitempty"`
+	GlobalId    int64             `json:"globalId,omitempty"`
+	ContentId   int64             `json:"contentId,omitempty"`
+	State       ArtifactState     `json:"state,omitempty"`
+	Labels      []string          `json:"labels,omitempty"`
+	Properties  map[string]string `json:"properties,omitempty"`
+}
+
+//EditableMetaData the subset of metadata fields users are allowed to modify
+type EditableMetaData struct {
+	Name        string            `json:"name,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Labels      []string          `json:"labels,omitempty"`
+	Properties  map[string]string `json:"properties,omitempty"`
+}
+
+//SearchedArtifact one entry of an artifacts listing
+type SearchedArtifact struct {
+	GroupId     string        `json:"groupId,omitempty"`
+	Id          string        `json:"id"`
+	Name        string        `json:"name,omitempty"`
+	Description string        `json:"description,omitempty"`
+	CreatedBy   string        `json:"createdBy,omitempty"`
+	CreatedOn   string        `json:"createdOn,omitempty"`
+	ModifiedBy  string        `json:"modifiedBy,omitempty"`
+	ModifiedOn  string        `json:"modifiedOn,omitempty"`
+	Type        ArtifactType  `json:"type,omitempty"`
+	State       ArtifactState `json:"state,omitempty"`
+	Labels      []string      `json:"labels,omitempty"`
+}
+
+//ArtifactSearchResults one page of an artifacts listing
+type ArtifactSearchResults struct {
+	Artifacts []SearchedArtifact `json:"artifacts"`
+	Count     int                `json:"count"`
+}
+
+//CreateArtifactRequest encapsulates parameters of CreateArtifactInGroup
+type CreateArtifactRequest struct {
+	GroupId      string
+	ArtifactId   string
+	ArtifactType ArtifactType
+	Version      string
+	Name         string
+	Description  string
+
+	Content string
+	//References to other artifacts the content uses, i.e. avro named types, protobuf imports or json schema $refs
+	References []ArtifactReference
+}
+
+//SearchedVersion one entry of an artifact versions listing, GroupId and ArtifactId are only set by versions searches
+type SearchedVersion struct {
+	GroupId     string            `json:"groupId,omitempty"`
+	ArtifactId  string            `json:"artifactId,omitempty"`
+	Version     string            `json:"version"`
+	Name        string            `json:"name,omitempty"`
+	Description string            `json:"description,omitempty"`
+	CreatedBy   string            `json:"createdBy,omitempty"`
+	CreatedOn   string            `json:"createdOn,omitempty"`
+	Type        ArtifactType      `json:"type,omitempty"`
+	GlobalId    int64             `json:"globalId,omitempty"`
+	ContentId   int64             `json:"contentId,omitempty"`
+	State       ArtifactState     `json:"state,omitempty"`
+	Labels      []string          `json:"labels,omitempty"`
+	Properties  map[string]string `json:"properties,omitempty"`
+}
+
+//VersionSearchResults one page of an artifact versions listing
+type VersionSearchResults struct {
+	Versions []SearchedVersion `json:"versions"`
+	Count    int               `json:"count"`
+}
+
+//CreateVersionRequest encapsulates parameters of CreateArtifactVersion, ArtifactType is only used to choose the content type of the request
+type CreateVersionRequest struct {
+	GroupId      string
+	ArtifactId   string
+	ArtifactType ArtifactType
+	Version      string
+	Name         string
+	Description  string
+
+	Content string
+	//References to other artifacts the content uses, i.e. avro named types, protobuf imports or json schema $refs
+	References []ArtifactReference
+}
+
+//ArtifactReference points from the content of a version to one version of another artifact,
+//Name is how the content refers to it, i.e. the avro full name, the protobuf import or the json schema $ref
+type ArtifactReference struct {
+	GroupId    string `json:"groupId"`
+	ArtifactId string `json:"artifactId"`
+	Version    string `json:"version"`
+	Name       string `json:"name"`
+}
+
+//extendedContentType content type of create requests sending the content along with its references
+const extendedContentType string = "application/create.extended+json"
+
+type contentCreateRequest struct {
+	Content    string              `json:"content"`
+	References []ArtifactReference `json:"references,omitempty"`
+}
+
+type updateState struct {
+	State ArtifactState `json:"state"`
+}
+
+const (
+	ValidityRule      RuleType = "VALIDITY"
+	CompatibilityRule RuleType = "COMPATIBILITY"
+	IntegrityRule     RuleType = "INTEGRITY"
+)
+
+//RuleType kind of rule the registry applies to new content
+type RuleType string
+
+//Rule one rule configured globally or for one artifact, config values depend on the rule type, i.e. FULL, BACKWARD, NONE
+type Rule struct {
+	Type   RuleType `json:"type"`
+	Config string   `json:"config"`
+}
+
+const (
+	ReadOnlyRole  RoleType = "READ_ONLY"
+	DeveloperRole RoleType = "DEVELOPER"
+	AdminRole     RoleType = "ADMIN"
+)
+
+//RoleType role granted to a principal when role based authorization is enabled in the registry
+type RoleType string
+
+//RoleMapping role granted to one principal
+type RoleMapping struct {
+	PrincipalId   string   `json:"principalId"`
+	Role          RoleType `json:"role"`
+	PrincipalName string   `json:"principalName,omitempty"`
+}
+
+type updateRole struct {
+	Role RoleType `json:"role"`
+}
+
+//ConfigProperty dynamic configuration property of the registry, values are always sent as strings
+type ConfigProperty struct {
+	Name        string `json:"name"`
+	Value       string `json:"value"`
+	Type        string `json:"type,omitempty"`
+	Label       string `json:"label,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type updateConfigProperty struct {
+	Value string `json:"value"`
+}
+
+const (
+	LogLevelTrace LogLevel = "TRACE"
+	LogLevelDebug LogLevel = "DEBUG"
+	LogLevelInfo  LogLevel = "INFO"
+	LogLevelWarn  LogLevel = "WARN"
+	LogLevelError LogLevel = "ERROR"
+)
+
+//LogLevel level of a logger configured at runtime
+type LogLevel string
+
+//NamedLogConfiguration level configured at runtime for one logger
+type NamedLogConfiguration struct {
+	Name  string   `json:"name"`
+	Level LogLevel `json:"level"`
+}
+
+type logConfiguration struct {
+	Level LogLevel `json:"level"`
+}
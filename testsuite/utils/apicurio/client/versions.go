@@ -0,0 +1,64 @@
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+//CreateArtifactVersion adds a new version to an existing artifact, the new version becomes the latest one
+func (r *ApicurioRegistryApiClientImpl) CreateArtifactVersion(req *CreateVersionRequest) (*VersionMetaData, error) {
+	headers := versionHeaders(req.ArtifactType, req.Version, req.Name, req.Description)
+
+	body, err := contentBody(req.Content, req.References, headers)
+	if err != nil {
+		return nil, err
+	}
+	data, err := r.doRequest(http.MethodPost, r.v2URL("/groups/%v/artifacts/%v/versions", groupOrDefault(req.GroupId), req.ArtifactId), body, headers, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &VersionMetaData{}
+	err = json.Unmarshal(data, metadata)
+	if err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+//ListArtifactVersions returns every version of the artifact, walking all the pages of the listing
+func (r *ApicurioRegistryApiClientImpl) ListArtifactVersions(groupId string, artifactId string) ([]SearchedVersion, error) {
+	versions := make([]SearchedVersion, 0)
+	for offset := 0; ; offset += listPageSize {
+		page := VersionSearchResults{}
+		url := r.v2URL("/groups/%v/artifacts/%v/versions", groupOrDefault(groupId), artifactId) + fmt.Sprintf("?offset=%v&limit=%v", offset, listPageSize)
+		err := r.doJSON(http.MethodGet, url, nil, &page, http.StatusOK)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, page.Versions...)
+		if len(page.Versions) == 0 || len(versions) >= page.Count {
+			break
+		}
+	}
+	return versions, nil
+}
+
+//ReadArtifactVersion returns the content of one specific version of the artifact
+func (r *ApicurioRegistryApiClientImpl) ReadArtifactVersion(groupId string, artifactId string, version string) (string, error) {
+	data, err := r.doRequest(http.MethodGet, r.v2URL("/groups/%v/artifacts/%v/versions/%v", groupOrDefault(groupId), artifactId, version), nil, nil, http.StatusOK)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+//UpdateArtifactState changes the state of the latest version of the artifact
+func (r *ApicurioRegistryApiClientImpl) UpdateArtifactState(groupId string, artifactId string, state ArtifactState) error {
+	return r.doJSON(http.MethodPut, r.v2URL("/groups/%v/artifacts/%v/state", groupOrDefault(groupId), artifactId), &updateState{State: state}, nil, http.StatusNoContent)
+}
+
+func (r *ApicurioRegistryApiClientImpl) UpdateArtifactVersionState(groupId string, artifactId string, version string, state ArtifactState) error {
+	return r.doJSON(http.MethodPut, r.v2URL("/groups/%v/artifacts/%v/versions/%v/state", groupOrDefault(groupId), artifactId, version), &updateState{State: state}, nil, http.StatusNoContent)
+}
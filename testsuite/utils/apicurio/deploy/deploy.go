@@ -1,30 +1,48 @@
 package deploy
 
 import (
-	"errors"
+	"os"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
-	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils"
 	apicurioutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kafkasql"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/keycloak"
+	kubernetesutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetes"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/logs"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/sql"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/storage"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
-	apicurio "github.com/Apicurio/apicurio-registry-operator/api/v1"
 
 	. "github.com/onsi/gomega"
 )
 
+var log = logf.Log.WithName("deploy")
+
+//StorageProviders storages the registry can be deployed with, test contexts choose one by name in their Storage field.
+//Testsuites can register providers of their own, i.e: variants using a database outside of the cluster
+var StorageProviders *storage.Registry = storage.NewRegistry(
+	&sql.StorageProvider{},
+	&kafkasql.StorageProvider{},
+)
+
+//DeployRegistryStorage deploys the storage of the test context and a registry using it, and waits for the registry to be ready
 func DeployRegistryStorage(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
 
-	var registry *apicurio.ApicurioRegistry
-	if ctx.Storage == utils.StorageSql {
-		registry = sql.SqlDeployResource(suiteCtx, ctx)
-	} else if ctx.Storage == utils.StorageKafkaSql {
-		registry = kafkasql.KafkaSqlDeployResource(suiteCtx, ctx)
-	} else {
-		Expect(errors.New("Storage not implemented")).ToNot(HaveOccurred())
+	provider := storageProvider(ctx)
+
+	if ctx.RegistryName == "" {
+		ctx.RegistryName = "apicurio-registry-" + ctx.Storage
 	}
 
+	kubernetesutils.ValidateClusterCapacity(suiteCtx, "registry with "+ctx.Storage+" storage",
+		append(registryResourceDemands(suiteCtx, ctx), provider.ResourceDemands(ctx)...)...)
+
+	provider.Deploy(suiteCtx, ctx)
+
+	log.Info("Deploying apicurio registry")
+	registry := provider.RegistryResource(suiteCtx, ctx)
+
 	if ctx.Auth {
 		registry.Spec.Configuration.Security.Keycloak = keycloak.KeycloakConfigResource(ctx)
 	}
@@ -33,12 +51,40 @@ func DeployRegistryStorage(suiteCtx *types.SuiteContext, ctx *types.TestContext)
 
 }
 
+//registryResourceDemands pods of the registry, with the resources they actually get from the installed operator
+func registryResourceDemands(suiteCtx *types.SuiteContext, ctx *types.TestContext) []kubernetesutils.ResourceDemand {
+	resources, err := apicurioutils.RegistryResources(suiteCtx, ctx)
+	Expect(err).ToNot(HaveOccurred())
+	return []kubernetesutils.ResourceDemand{{Name: "registry", Pods: int(ctx.RegistryReplicas()), Requests: resources.Requests}}
+}
+
+//RemoveRegistryDeployment deletes the registry of the test context and removes its storage
 func RemoveRegistryDeployment(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
-	if ctx.Storage == utils.StorageSql {
-		sql.RemoveJpaRegistry(suiteCtx, ctx)
-	} else if ctx.Storage == utils.StorageKafkaSql {
-		kafkasql.RemoveKafkaSqlRegistry(suiteCtx, ctx)
-	} else {
-		Expect(errors.New("Storage not implemented")).ToNot(HaveOccurred())
+	provider := storageProvider(ctx)
+
+	apicurioutils.DeleteRegistryAndWait(suiteCtx, ctx.RegistryNamespace, ctx.RegistryName)
+
+	provider.Remove(suiteCtx, ctx)
+}
+
+//SaveStorageDiagnostics saves the diagnostics of the storage of the test context with the logs of the running test
+func SaveStorageDiagnostics(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
+	if ctx.Storage == "" {
+		return
+	}
+	provider, err := StorageProviders.Get(ctx.Storage)
+	if err != nil {
+		log.Info("Skipping storage diagnostics", "reason", err.Error())
+		return
 	}
+	dir := logs.TestLogsDir(suiteCtx, ctx) + "storage/"
+	os.MkdirAll(dir, os.ModePerm)
+	log.Info("Collecting storage diagnostics", "storage", ctx.Storage, "dir", dir)
+	provider.Diagnostics(suiteCtx, ctx, dir)
+}
+
+func storageProvider(ctx *types.TestContext) storage.Provider {
+	provider, err := StorageProviders.Get(ctx.Storage)
+	Expect(err).ToNot(HaveOccurred())
+	return provider
 }
@@ -0,0 +1,340 @@
+package apicurio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	policy "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	kubernetesutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetes"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+	apicurio "github.com/Apicurio/apicurio-registry-operator/api/v1"
+)
+
+//GetManagedObject reads into obj the object of its kind the operator manages for the registry, list has to be the list type of the same kind.
+//The object is looked up in the managed resources of the registry, or by the registry name label for older operators.
+//Returns false if there is no such object
+func GetManagedObject(suiteCtx *types.SuiteContext, registry *apicurio.ApicurioRegistry, obj client.Object, list client.ObjectList) (bool, error) {
+	gvk, err := apiutil.GVKForObject(obj, scheme.Scheme)
+	if err != nil {
+		return false, err
+	}
+	key, found := managedResourceKey(registry, gvk.Kind)
+	if !found {
+		err = suiteCtx.K8sClient.List(context.TODO(), list, client.InNamespace(registry.Namespace), client.MatchingLabels{"app": registry.Name})
+		if err != nil {
+			return false, err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil || len(items) == 0 {
+			return false, err
+		}
+		accessor, err := meta.Accessor(items[0])
+		if err != nil {
+			return false, err
+		}
+		key = client.ObjectKey{Namespace: accessor.GetNamespace(), Name: accessor.GetName()}
+	}
+	err = suiteCtx.K8sClient.Get(context.TODO(), key, obj)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+//managedResourceKey key of the managed resource of the kind listed in the status of the registry
+func managedResourceKey(registry *apicurio.ApicurioRegistry, kind string) (client.ObjectKey, bool) {
+	for _, r := range registry.Status.ManagedResources {
+		if r.Kind != kind {
+			continue
+		}
+		namespace := r.Namespace
+		if namespace == "" {
+			namespace = registry.Namespace
+		}
+		return client.ObjectKey{Namespace: namespace, Name: r.Name}, true
+	}
+	return client.ObjectKey{}, false
+}
+
+//ManagedObjects objects the operator generates for one registry, optional kinds are nil when the operator doesn't manage them
+type ManagedObjects struct {
+	Registry   *apicurio.ApicurioRegistry
+	Deployment *appsv1.Deployment
+	Service    *corev1.Service
+	//Pods every pod in the namespace of the registry, selectors are checked against them
+	Pods []corev1.Pod
+
+	//PodDisruptionBudget of the version the cluster serves, converted to the policy/v1beta1 type with its apiVersion kept
+	PodDisruptionBudget *policy.PodDisruptionBudget
+	NetworkPolicy       *networking.NetworkPolicy
+	Ingress             *networking.Ingress
+}
+
+//GetManagedObjects reads the objects the operator generated for the registry, the deployment and the service are required
+func GetManagedObjects(suiteCtx *types.SuiteContext, registry *apicurio.ApicurioRegistry) (*ManagedObjects, error) {
+	objects := &ManagedObjects{Registry: registry}
+
+	objects.Deployment = &appsv1.Deployment{}
+	if err := getRequiredManagedObject(suiteCtx, registry, objects.Deployment, &appsv1.DeploymentList{}); err != nil {
+		return nil, err
+	}
+	objects.Service = &corev1.Service{}
+	if err := getRequiredManagedObject(suiteCtx, registry, objects.Service, &corev1.ServiceList{}); err != nil {
+		return nil, err
+	}
+
+	pdbGVK, err := kubernetesutils.PodDisruptionBudgetGVK(suiteCtx)
+	if err != nil {
+		return nil, err
+	}
+	pdb := kubernetesutils.UnstructuredObject(pdbGVK)
+	found, err := GetManagedObject(suiteCtx, registry, pdb, kubernetesutils.UnstructuredList(pdbGVK))
+	if err != nil {
+		return nil, err
+	} else if found {
+		if objects.PodDisruptionBudget, err = kubernetesutils.ToPodDisruptionBudget(pdb); err != nil {
+			return nil, err
+		}
+	}
+	objects.NetworkPolicy = &networking.NetworkPolicy{}
+	found, err = GetManagedObject(suiteCtx, registry, objects.NetworkPolicy, &networking.NetworkPolicyList{})
+	if err != nil {
+		return nil, err
+	} else if !found {
+		objects.NetworkPolicy = nil
+	}
+	objects.Ingress = &networking.Ingress{}
+	found, err = GetManagedObject(suiteCtx, registry, objects.Ingress, &networking.IngressList{})
+	if err != nil {
+		return nil, err
+	} else if !found {
+		objects.Ingress = nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := suiteCtx.K8sClient.List(context.TODO(), pods, client.InNamespace(registry.Namespace)); err != nil {
+		return nil, err
+	}
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp == nil {
+			objects.Pods = append(objects.Pods, pod)
+		}
+	}
+	return objects, nil
+}
+
+func getRequiredManagedObject(suiteCtx *types.SuiteContext, registry *apicurio.ApicurioRegistry, obj client.Object, list client.ObjectList) error {
+	found, err := GetManagedObject(suiteCtx, registry, obj, list)
+	if err == nil && !found {
+		err = fmt.Errorf("registry %v has no %T", registry.Name, obj)
+	}
+	return err
+}
+
+//VerifyManagedObjects verifies selectors, ports, hosts and owner references of the PodDisruptionBudget, NetworkPolicy and Ingress the operator generated for the registry.
+//Kinds older operators don't generate are skipped
+func VerifyManagedObjects(suiteCtx *types.SuiteContext, registry *apicurio.ApicurioRegistry, replicas int32) {
+	objects, err := GetManagedObjects(suiteCtx, registry)
+	Expect(err).ToNot(HaveOccurred())
+	if objects.PodDisruptionBudget == nil {
+		log.Info("Operator manages no pod disruption budget, skipping its verification", "registry", registry.Name)
+	}
+	if objects.NetworkPolicy == nil {
+		log.Info("Operator manages no network policy, skipping its verification", "registry", registry.Name)
+	}
+	problems := objects.Problems(replicas)
+	Expect(problems).To(BeEmpty(), "objects generated for registry %v", registry.Name)
+}
+
+//Problems inconsistencies between the generated objects, the registry and its pods, empty if everything is as expected
+func (m *ManagedObjects) Problems(replicas int32) []string {
+	problems := []string{}
+	registryPods := m.selectedPods(m.Deployment.Spec.Selector)
+	//pods of a previous pod template may still be running, the operator can roll the deployment out again right after it's ready
+	if int32(len(registryPods)) < replicas {
+		problems = append(problems, fmt.Sprintf("deployment selects %v pods %v, expected at least %v replicas", len(registryPods), registryPods, replicas))
+	}
+
+	if pdb := m.PodDisruptionBudget; pdb != nil {
+		problems = append(problems, m.ownerProblems("pod disruption budget", pdb)...)
+		problems = append(problems, m.selectorProblems("pod disruption budget", pdb.Spec.Selector, registryPods)...)
+		problems = append(problems, budgetProblems(pdb, replicas)...)
+	}
+
+	if np := m.NetworkPolicy; np != nil {
+		problems = append(problems, m.ownerProblems("network policy", np)...)
+		problems = append(problems, m.selectorProblems("network policy", &np.Spec.PodSelector, registryPods)...)
+		if !m.networkPolicyAllowsService(np) {
+			problems = append(problems, fmt.Sprintf("network policy %v doesn't allow ingress to the ports of service %v", np.Name, m.Service.Name))
+		}
+	}
+
+	if ingress := m.Ingress; ingress != nil {
+		problems = append(problems, m.ownerProblems("ingress", ingress)...)
+		problems = append(problems, m.ingressProblems(ingress)...)
+	}
+	return problems
+}
+
+//selectedPods names of the pods matched by the selector, nothing matches a nil selector
+func (m *ManagedObjects) selectedPods(selector *metav1.LabelSelector) []string {
+	pods := []string{}
+	if selector == nil {
+		return pods
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return pods
+	}
+	for _, pod := range m.Pods {
+		if s.Matches(labels.Set(pod.Labels)) {
+			pods = append(pods, pod.Name)
+		}
+	}
+	sort.Strings(pods)
+	return pods
+}
+
+//selectorProblems the selector has to match exactly the pods of the registry, not the pods of its storage
+func (m *ManagedObjects) selectorProblems(kind string, selector *metav1.LabelSelector, registryPods []string) []string {
+	if selector == nil {
+		return []string{kind + " has no selector"}
+	}
+	selected := m.selectedPods(selector)
+	if strings.Join(selected, ",") != strings.Join(registryPods, ",") {
+		return []string{fmt.Sprintf("%v selector %v matches pods %v, registry pods are %v", kind, metav1.FormatLabelSelector(selector), selected, registryPods)}
+	}
+	return nil
+}
+
+//ownerProblems generated objects have to be controlled by the registry, so they are garbage collected with it
+func (m *ManagedObjects) ownerProblems(kind string, obj metav1.Object) []string {
+	owner := metav1.GetControllerOf(obj)
+	if owner == nil {
+		return []string{fmt.Sprintf("%v %v has no controller owner reference", kind, obj.GetName())}
+	}
+	if owner.Kind != "ApicurioRegistry" || owner.Name != m.Registry.Name || owner.UID != m.Registry.UID {
+		return []string{fmt.Sprintf("%v %v is controlled by %v %v (%v), expected ApicurioRegistry %v (%v)", kind, obj.GetName(), owner.Kind, owner.Name, owner.UID, m.Registry.Name, m.Registry.UID)}
+	}
+	return nil
+}
+
+//budgetProblems the budget must let at least one pod be evicted once every replica is available, otherwise node drains block on single replica registries
+func budgetProblems(pdb *policy.PodDisruptionBudget, replicas int32) []string {
+	if pdb.Spec.MaxUnavailable != nil {
+		maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(pdb.Spec.MaxUnavailable, int(replicas), true)
+		if err != nil {
+			return []string{fmt.Sprintf("pod disruption budget %v has an invalid maxUnavailable: %v", pdb.Name, err)}
+		}
+		if maxUnavailable < 1 {
+			return []string{fmt.Sprintf("pod disruption budget %v maxUnavailable %v allows no disruption of %v replicas", pdb.Name, pdb.Spec.MaxUnavailable.String(), replicas)}
+		}
+		return nil
+	}
+	if pdb.Spec.MinAvailable != nil {
+		minAvailable, err := intstr.GetScaledValueFromIntOrPercent(pdb.Spec.MinAvailable, int(replicas), true)
+		if err != nil {
+			return []string{fmt.Sprintf("pod disruption budget %v has an invalid minAvailable: %v", pdb.Name, err)}
+		}
+		if minAvailable >= int(replicas) {
+			return []string{fmt.Sprintf("pod disruption budget %v minAvailable %v allows no disruption of %v replicas", pdb.Name, pdb.Spec.MinAvailable.String(), replicas)}
+		}
+		return nil
+	}
+	return []string{fmt.Sprintf("pod disruption budget %v sets neither minAvailable nor maxUnavailable", pdb.Name)}
+}
+
+//networkPolicyAllowsService checks some ingress rule of the policy lets traffic reach the port the service targets
+func (m *ManagedObjects) networkPolicyAllowsService(np *networking.NetworkPolicy) bool {
+	if len(m.Service.Spec.Ports) == 0 {
+		return false
+	}
+	targetPort := m.Service.Spec.Ports[0].TargetPort
+	targets := map[string]bool{targetPort.String(): true}
+	for _, c := range m.Deployment.Spec.Template.Spec.Containers {
+		for _, p := range c.Ports {
+			if strconv.Itoa(int(p.ContainerPort)) == targetPort.String() || p.Name == targetPort.String() {
+				targets[strconv.Itoa(int(p.ContainerPort))] = true
+				if p.Name != "" {
+					targets[p.Name] = true
+				}
+			}
+		}
+	}
+	for _, rule := range np.Spec.Ingress {
+		if len(rule.Ports) == 0 {
+			return true
+		}
+		for _, p := range rule.Ports {
+			if p.Port == nil || targets[p.Port.String()] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//ingressProblems every rule has to use the host of the registry and route to the registry service
+func (m *ManagedObjects) ingressProblems(ingress *networking.Ingress) []string {
+	problems := []string{}
+	if len(ingress.Spec.Rules) == 0 {
+		return []string{fmt.Sprintf("ingress %v has no rules", ingress.Name)}
+	}
+	service := m.Service
+	for _, rule := range ingress.Spec.Rules {
+		if m.Registry.Spec.Deployment.Host != "" && rule.Host != m.Registry.Spec.Deployment.Host {
+			problems = append(problems, fmt.Sprintf("ingress %v host %q, expected %q", ingress.Name, rule.Host, m.Registry.Spec.Deployment.Host))
+		}
+		if rule.HTTP == nil || len(rule.HTTP.Paths) == 0 {
+			problems = append(problems, fmt.Sprintf("ingress %v rule of host %q has no paths", ingress.Name, rule.Host))
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			backend := path.Backend.Service
+			if backend == nil {
+				problems = append(problems, fmt.Sprintf("ingress %v path %q has no service backend", ingress.Name, path.Path))
+				continue
+			}
+			if backend.Name != service.Name {
+				problems = append(problems, fmt.Sprintf("ingress %v path %q routes to service %v, expected %v", ingress.Name, path.Path, backend.Name, service.Name))
+			}
+			if !servicePortMatches(service, backend.Port) {
+				problems = append(problems, fmt.Sprintf("ingress %v path %q routes to port %v, service %v doesn't expose it", ingress.Name, path.Path, backendPort(backend.Port), service.Name))
+			}
+		}
+	}
+	return problems
+}
+
+func servicePortMatches(service *corev1.Service, port networking.ServiceBackendPort) bool {
+	for _, p := range service.Spec.Ports {
+		if (port.Name != "" && p.Name == port.Name) || (port.Name == "" && p.Port == port.Number) {
+			return true
+		}
+	}
+	return false
+}
+
+func backendPort(port networking.ServiceBackendPort) string {
+	if port.Name != "" {
+		return port.Name
+	}
+	return strconv.Itoa(int(port.Number))
+}
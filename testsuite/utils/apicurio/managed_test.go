@@ -0,0 +1,154 @@
+package apicurio
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	policy "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	apicurio "github.com/Apicurio/apicurio-registry-operator/api/v1"
+)
+
+//managedSelector labels of the registry pods
+var managedSelector map[string]string = map[string]string{"app": "registry"}
+
+//managedObjects mimics what the operator generates for a registry with the given replicas, plus a storage pod in the same namespace
+func managedObjects(replicas int32) *ManagedObjects {
+	registry := &apicurio.ApicurioRegistry{ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: "testsuite", UID: kubetypes.UID("registry-uid")}}
+	registry.Spec.Deployment.Host = "registry.127.0.0.1.nip.io"
+	controller := true
+	meta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: "testsuite", Labels: managedSelector, OwnerReferences: []metav1.OwnerReference{
+			{APIVersion: "registry.apicur.io/v1", Kind: "ApicurioRegistry", Name: "registry", UID: registry.UID, Controller: &controller},
+		}}
+	}
+
+	deployment := &appsv1.Deployment{ObjectMeta: meta("registry-deployment")}
+	deployment.Spec.Selector = &metav1.LabelSelector{MatchLabels: managedSelector}
+	deployment.Spec.Template.Spec.Containers = []corev1.Container{{Name: "registry", Ports: []corev1.ContainerPort{{ContainerPort: 8080, Protocol: corev1.ProtocolTCP}}}}
+	service := &corev1.Service{ObjectMeta: meta("registry-service"), Spec: corev1.ServiceSpec{
+		Selector: managedSelector,
+		Ports:    []corev1.ServicePort{{Protocol: corev1.ProtocolTCP, Port: 8080, TargetPort: intstr.FromInt(8080)}},
+	}}
+	pathType := networking.PathTypePrefix
+	ingress := &networking.Ingress{ObjectMeta: meta("registry-ingress"), Spec: networking.IngressSpec{Rules: []networking.IngressRule{{
+		Host: registry.Spec.Deployment.Host,
+		IngressRuleValue: networking.IngressRuleValue{HTTP: &networking.HTTPIngressRuleValue{Paths: []networking.HTTPIngressPath{{
+			Path:     "/",
+			PathType: &pathType,
+			Backend:  networking.IngressBackend{Service: &networking.IngressServiceBackend{Name: service.Name, Port: networking.ServiceBackendPort{Number: 8080}}},
+		}}}},
+	}}}}
+	maxUnavailable := intstr.FromInt(1)
+	pdb := &policy.PodDisruptionBudget{ObjectMeta: meta("registry-pdb"), Spec: policy.PodDisruptionBudgetSpec{
+		Selector:       &metav1.LabelSelector{MatchLabels: managedSelector},
+		MaxUnavailable: &maxUnavailable,
+	}}
+	port := intstr.FromInt(8080)
+	networkPolicy := &networking.NetworkPolicy{ObjectMeta: meta("registry-networkpolicy"), Spec: networking.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{MatchLabels: managedSelector},
+		Ingress:     []networking.NetworkPolicyIngressRule{{Ports: []networking.NetworkPolicyPort{{Port: &port}}}},
+	}}
+
+	pods := []corev1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "postgresql-0", Labels: map[string]string{"app": "postgresql"}}}}
+	for i := int32(0); i < replicas; i++ {
+		pods = append(pods, corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("registry-deployment-%v", i), Labels: managedSelector}})
+	}
+	return &ManagedObjects{Registry: registry, Deployment: deployment, Service: service, Pods: pods, PodDisruptionBudget: pdb, NetworkPolicy: networkPolicy, Ingress: ingress}
+}
+
+func TestManagedObjectsHasNoProblemsWithTheObjectsGeneratedForSingleReplicaAndClusteredRegistries(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(managedObjects(1).Problems(1)).To(BeEmpty())
+	g.Expect(managedObjects(3).Problems(3)).To(BeEmpty())
+}
+
+func TestManagedObjectsSkipsTheKindsTheOperatorDoesntManage(t *testing.T) {
+	g := NewWithT(t)
+	objects := managedObjects(1)
+	objects.PodDisruptionBudget = nil
+	objects.NetworkPolicy = nil
+	g.Expect(objects.Problems(1)).To(BeEmpty())
+}
+
+func TestManagedObjectsReportsMissingReplicas(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(managedObjects(2).Problems(3)).To(ConsistOf(ContainSubstring("deployment selects 2 pods")))
+}
+
+func TestManagedObjectsAcceptsPodsOfAPreviousRolloutStillRunning(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(managedObjects(3).Problems(2)).To(BeEmpty())
+}
+
+func TestManagedObjectsReportsSelectorsNotMatchingExactlyTheRegistryPods(t *testing.T) {
+	g := NewWithT(t)
+	objects := managedObjects(3)
+	objects.PodDisruptionBudget.Spec.Selector = &metav1.LabelSelector{}
+	objects.NetworkPolicy.Spec.PodSelector = metav1.LabelSelector{MatchLabels: map[string]string{"app": "other"}}
+	g.Expect(objects.Problems(3)).To(ConsistOf(
+		ContainSubstring("pod disruption budget selector <none> matches pods [postgresql-0 registry-deployment-0"),
+		ContainSubstring("network policy selector app=other matches pods []"),
+	))
+}
+
+func TestManagedObjectsReportsBudgetsBlockingEveryDisruption(t *testing.T) {
+	g := NewWithT(t)
+	objects := managedObjects(1)
+	minAvailable := intstr.FromInt(1)
+	objects.PodDisruptionBudget.Spec.MaxUnavailable = nil
+	objects.PodDisruptionBudget.Spec.MinAvailable = &minAvailable
+	g.Expect(objects.Problems(1)).To(ConsistOf(ContainSubstring("minAvailable 1 allows no disruption of 1 replicas")))
+
+	clustered := managedObjects(3)
+	clustered.PodDisruptionBudget.Spec.MaxUnavailable = nil
+	clustered.PodDisruptionBudget.Spec.MinAvailable = &minAvailable
+	g.Expect(clustered.Problems(3)).To(BeEmpty())
+
+	zero := intstr.FromString("0%")
+	clustered.PodDisruptionBudget.Spec.MinAvailable = nil
+	clustered.PodDisruptionBudget.Spec.MaxUnavailable = &zero
+	g.Expect(clustered.Problems(3)).To(ConsistOf(ContainSubstring("maxUnavailable 0% allows no disruption")))
+}
+
+func TestManagedObjectsReportsNetworkPoliciesNotAllowingTrafficToTheRegistry(t *testing.T) {
+	g := NewWithT(t)
+	objects := managedObjects(1)
+	other := intstr.FromInt(9090)
+	objects.NetworkPolicy.Spec.Ingress[0].Ports[0].Port = &other
+	g.Expect(objects.Problems(1)).To(ConsistOf(ContainSubstring("doesn't allow ingress to the ports of service registry-service")))
+
+	objects.NetworkPolicy.Spec.Ingress[0].Ports = nil
+	g.Expect(objects.Problems(1)).To(BeEmpty())
+}
+
+func TestManagedObjectsReportsWrongIngressHostsBackendsAndOwners(t *testing.T) {
+	g := NewWithT(t)
+	objects := managedObjects(1)
+	backend := objects.Ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service
+	backend.Name = "other-service"
+	backend.Port = networking.ServiceBackendPort{Number: 80}
+	objects.Ingress.Spec.Rules[0].Host = "other.127.0.0.1.nip.io"
+	objects.Ingress.OwnerReferences = nil
+	g.Expect(objects.Problems(1)).To(ConsistOf(
+		ContainSubstring("ingress registry-ingress has no controller owner reference"),
+		ContainSubstring(`host "other.127.0.0.1.nip.io", expected "registry.127.0.0.1.nip.io"`),
+		ContainSubstring("routes to service other-service, expected registry-service"),
+		ContainSubstring("routes to port 80, service registry-service doesn't expose it"),
+	))
+}
+
+func TestManagedObjectsReportsObjectsControlledByAnotherRegistry(t *testing.T) {
+	g := NewWithT(t)
+	objects := managedObjects(1)
+	objects.PodDisruptionBudget.OwnerReferences[0].UID = kubetypes.UID("previous-registry-uid")
+	g.Expect(objects.Problems(1)).To(ConsistOf(ContainSubstring("pod disruption budget registry-pdb is controlled by ApicurioRegistry registry (previous-registry-uid)")))
+}
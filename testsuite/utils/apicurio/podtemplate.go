@@ -0,0 +1,255 @@
+package apicurio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+	apicurio "github.com/Apicurio/apicurio-registry-operator/api/v1"
+)
+
+//RegistryContainerName container of the registry in the pod template operators merge spec.deployment.podTemplateSpecPreview into
+const RegistryContainerName string = "registry"
+
+//RegistryContainer the container named like RegistryContainerName, older operators name it after the registry.
+//Pod specs with a single container of another name return it, nil if there is no registry container
+func RegistryContainer(podSpec *corev1.PodSpec, registryName string) *corev1.Container {
+	for i := range podSpec.Containers {
+		if name := podSpec.Containers[i].Name; name == RegistryContainerName || name == registryName {
+			return &podSpec.Containers[i]
+		}
+	}
+	if len(podSpec.Containers) == 1 {
+		return &podSpec.Containers[0]
+	}
+	return nil
+}
+
+//spec fields of newer operators, missing in the api the testsuite is built with
+var (
+	EnvField         = []string{"spec", "configuration", "env"}
+	PodTemplateField = []string{"spec", "deployment", "podTemplateSpecPreview"}
+)
+
+//createRegistry creates the registry, with the pod template customizations of the test context and the resources of its deployment size.
+//Customizations missing in the api are sent as raw fields, operators not supporting them prune them from the registry
+func createRegistry(suiteCtx *types.SuiteContext, ctx *types.TestContext, registry *apicurio.ApicurioRegistry) error {
+	supported, err := supportsPodTemplate(suiteCtx, registry.Namespace)
+	if err != nil {
+		return err
+	}
+	if !supported {
+		log.Info("Operator doesn't support pod template customizations, registry pods get the operator default resources instead of the ones of the deployment size",
+			"size", ctx.Size)
+	}
+	u, err := podTemplateRegistry(registry, registryPodTemplate(ctx, supported))
+	if err != nil {
+		return err
+	}
+	if err := suiteCtx.K8sClient.Create(context.TODO(), u); err != nil {
+		return err
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, registry)
+}
+
+//RegistryResources resources the pods of the registry of the test context get: the ones of its pod template customizations or deployment size,
+//or the operator defaults if the installed operator doesn't support pod template customizations.
+//The operator defaults are only known once a registry is deployed, until then they are empty
+func RegistryResources(suiteCtx *types.SuiteContext, ctx *types.TestContext) (corev1.ResourceRequirements, error) {
+	supported, err := supportsPodTemplate(suiteCtx, ctx.RegistryNamespace)
+	if err != nil {
+		return corev1.ResourceRequirements{}, err
+	}
+	if supported {
+		return *registryPodTemplate(ctx, true).Resources, nil
+	}
+	if suiteCtx.OperatorDefaultResources == nil {
+		log.Info("Operator default resources of the registry unknown until the first registry is deployed, they are not counted")
+		return corev1.ResourceRequirements{}, nil
+	}
+	return *suiteCtx.OperatorDefaultResources, nil
+}
+
+//recordOperatorDefaultResources keeps the resources the operator gave the registry pods, if it doesn't support pod template customizations
+func recordOperatorDefaultResources(suiteCtx *types.SuiteContext, registry *apicurio.ApicurioRegistry) error {
+	if suiteCtx.OperatorDefaultResources != nil || suiteCtx.PodTemplateSupported == nil || *suiteCtx.PodTemplateSupported {
+		return nil
+	}
+	deployment, err := findRegistryDeployment(suiteCtx, registry)
+	if err != nil || deployment == nil {
+		return err
+	}
+	container := RegistryContainer(&deployment.Spec.Template.Spec, registry.Name)
+	if container == nil {
+		return fmt.Errorf("deployment %v has no registry container", deployment.Name)
+	}
+	resources := container.Resources.DeepCopy()
+	log.Info("Operator default resources of the registry", "requests", resources.Requests, "limits", resources.Limits)
+	suiteCtx.OperatorDefaultResources = resources
+	return nil
+}
+
+//supportsPodTemplate creates a registry with pod template customizations in dry run mode, once per suite,
+//the api server prunes them from the registry if the installed operator doesn't support them
+func supportsPodTemplate(suiteCtx *types.SuiteContext, namespace string) (bool, error) {
+	if suiteCtx.PodTemplateSupported != nil {
+		return *suiteCtx.PodTemplateSupported, nil
+	}
+	pruned, err := dryRunPrunedFields(suiteCtx, namespace, &types.PodTemplate{Labels: map[string]string{"e2e": "pod-template-support"}}, PodTemplateField)
+	if err != nil {
+		return false, err
+	}
+	supported := len(pruned) == 0
+	suiteCtx.PodTemplateSupported = &supported
+	return supported, nil
+}
+
+//registryPodTemplate the pod template customizations of the test context, with the registry resources of the deployment size
+//unless the customizations set resources or the operator doesn't support pod template customizations
+func registryPodTemplate(ctx *types.TestContext, supported bool) *types.PodTemplate {
+	pt := &types.PodTemplate{}
+	if ctx.PodTemplate != nil {
+		customized := *ctx.PodTemplate
+		pt = &customized
+	}
+	if pt.Resources == nil && supported {
+		resources := ctx.Size.Profile().Registry
+		pt.Resources = &resources
+	}
+	return pt
+}
+
+//podTemplateRegistry the registry as unstructured content, with the pod template customizations set
+func podTemplateRegistry(registry *apicurio.ApicurioRegistry, pt *types.PodTemplate) (*unstructured.Unstructured, error) {
+	customized := registry.DeepCopy()
+	if pt.Affinity != nil {
+		customized.Spec.Deployment.Affinity = pt.Affinity
+	}
+	customized.Spec.Deployment.Tolerations = append(customized.Spec.Deployment.Tolerations, pt.Tolerations...)
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(customized)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(apicurio.GroupVersion.WithKind("ApicurioRegistry"))
+
+	if len(pt.Env) != 0 {
+		env, err := toUnstructuredList(pt.Env)
+		if err != nil {
+			return nil, err
+		}
+		if err := unstructured.SetNestedSlice(u.Object, env, EnvField...); err != nil {
+			return nil, err
+		}
+	}
+
+	podTemplate := map[string]interface{}{}
+	if len(pt.Labels) != 0 {
+		podTemplate["metadata"] = map[string]interface{}{"labels": toInterfaceMap(pt.Labels)}
+	}
+	if len(pt.Annotations) != 0 {
+		metadata, _ := podTemplate["metadata"].(map[string]interface{})
+		if metadata == nil {
+			metadata = map[string]interface{}{}
+		}
+		metadata["annotations"] = toInterfaceMap(pt.Annotations)
+		podTemplate["metadata"] = metadata
+	}
+	spec := map[string]interface{}{}
+	container := map[string]interface{}{"name": RegistryContainerName}
+	if pt.Resources != nil {
+		resources, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pt.Resources)
+		if err != nil {
+			return nil, err
+		}
+		container["resources"] = resources
+	}
+	if len(pt.VolumeMounts) != 0 {
+		mounts, err := toUnstructuredList(pt.VolumeMounts)
+		if err != nil {
+			return nil, err
+		}
+		container["volumeMounts"] = mounts
+	}
+	if len(container) > 1 {
+		spec["containers"] = []interface{}{container}
+	}
+	if len(pt.Volumes) != 0 {
+		volumes, err := toUnstructuredList(pt.Volumes)
+		if err != nil {
+			return nil, err
+		}
+		spec["volumes"] = volumes
+	}
+	if len(spec) != 0 {
+		podTemplate["spec"] = spec
+	}
+	if len(podTemplate) != 0 {
+		if err := unstructured.SetNestedMap(u.Object, podTemplate, PodTemplateField...); err != nil {
+			return nil, err
+		}
+	}
+	return u, nil
+}
+
+//PrunedFields which of the given spec fields are requested in the pod template of the test context but pruned by the api server,
+//which drops the fields the installed operator doesn't know about. The registry is created in dry run mode, so nothing has to be deployed before
+func PrunedFields(suiteCtx *types.SuiteContext, ctx *types.TestContext, fields ...[]string) ([]string, error) {
+	if ctx.PodTemplate == nil {
+		return nil, nil
+	}
+	return dryRunPrunedFields(suiteCtx, ctx.RegistryNamespace, ctx.PodTemplate, fields...)
+}
+
+//dryRunPrunedFields creates a registry with the pod template customizations in dry run mode and reports the requested fields it lost
+func dryRunPrunedFields(suiteCtx *types.SuiteContext, namespace string, pt *types.PodTemplate, fields ...[]string) ([]string, error) {
+	requested, err := podTemplateRegistry(&apicurio.ApicurioRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-template-support", Namespace: namespace},
+	}, pt)
+	if err != nil {
+		return nil, err
+	}
+	created := requested.DeepCopy()
+	if err := suiteCtx.K8sClient.Create(context.TODO(), created, client.DryRunAll); err != nil {
+		return nil, err
+	}
+	return prunedFields(requested, created, fields...), nil
+}
+
+func prunedFields(requested *unstructured.Unstructured, current *unstructured.Unstructured, fields ...[]string) []string {
+	pruned := []string{}
+	for _, field := range fields {
+		_, isRequested, _ := unstructured.NestedFieldNoCopy(requested.Object, field...)
+		_, exists, _ := unstructured.NestedFieldNoCopy(current.Object, field...)
+		if isRequested && !exists {
+			pruned = append(pruned, strings.Join(field, "."))
+		}
+	}
+	return pruned
+}
+
+func toUnstructuredList(items interface{}) ([]interface{}, error) {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	list := []interface{}{}
+	err = json.Unmarshal(data, &list)
+	return list, err
+}
+
+func toInterfaceMap(values map[string]string) map[string]interface{} {
+	m := map[string]interface{}{}
+	for k, v := range values {
+		m[k] = v
+	}
+	return m
+}
@@ -0,0 +1,132 @@
+package apicurio
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+	apicurio "github.com/Apicurio/apicurio-registry-operator/api/v1"
+)
+
+//podTemplateFixtures a sql registry and pod template customizations of every kind but affinity
+func podTemplateFixtures() (*apicurio.ApicurioRegistry, *types.PodTemplate) {
+	registry := &apicurio.ApicurioRegistry{ObjectMeta: metav1.ObjectMeta{Name: "apicurio-registry-sql", Namespace: "testsuite"}}
+	registry.Spec.Configuration.Persistence = "sql"
+
+	podTemplate := &types.PodTemplate{
+		Env: []corev1.EnvVar{{Name: "E2E_CUSTOM_ENV", Value: "custom-value"}},
+		Resources: &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1200Mi")},
+		},
+		Tolerations:  []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+		Annotations:  map[string]string{"e2e": "annotation"},
+		Labels:       map[string]string{"e2e": "label"},
+		Volumes:      []corev1.Volume{{Name: "extra", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+		VolumeMounts: []corev1.VolumeMount{{Name: "extra", MountPath: "/extra"}},
+	}
+	return registry, podTemplate
+}
+
+func TestPodTemplateSetsTheCustomizationsInTheDeploymentSpecAndInTheRawFieldsOfNewerOperators(t *testing.T) {
+	g := NewWithT(t)
+	registry, podTemplate := podTemplateFixtures()
+	u, err := podTemplateRegistry(registry, podTemplate)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(u.GetKind()).To(Equal("ApicurioRegistry"))
+	g.Expect(u.GetAPIVersion()).To(Equal("registry.apicur.io/v1"))
+	g.Expect(u.GetName()).To(Equal("apicurio-registry-sql"))
+
+	persistence, _, _ := unstructured.NestedString(u.Object, "spec", "configuration", "persistence")
+	g.Expect(persistence).To(Equal("sql"))
+	tolerations, _, _ := unstructured.NestedSlice(u.Object, "spec", "deployment", "tolerations")
+	g.Expect(tolerations).To(Equal([]interface{}{map[string]interface{}{"key": "dedicated", "operator": "Exists"}}))
+
+	env, _, _ := unstructured.NestedSlice(u.Object, EnvField...)
+	g.Expect(env).To(Equal([]interface{}{map[string]interface{}{"name": "E2E_CUSTOM_ENV", "value": "custom-value"}}))
+
+	template, found, err := unstructured.NestedMap(u.Object, PodTemplateField...)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(found).To(BeTrue())
+	g.Expect(template).To(Equal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels":      map[string]interface{}{"e2e": "label"},
+			"annotations": map[string]interface{}{"e2e": "annotation"},
+		},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{map[string]interface{}{
+				"name":         RegistryContainerName,
+				"resources":    map[string]interface{}{"limits": map[string]interface{}{"memory": "1200Mi"}},
+				"volumeMounts": []interface{}{map[string]interface{}{"name": "extra", "mountPath": "/extra"}},
+			}},
+			"volumes": []interface{}{map[string]interface{}{"name": "extra", "emptyDir": map[string]interface{}{}}},
+		},
+	}))
+	g.Expect(registry.Spec.Deployment.Tolerations).To(BeEmpty(), "the registry passed is not modified")
+}
+
+func TestPodTemplateLeavesTheRawFieldsOutWhenOnlyAffinityAndTolerationsAreCustomized(t *testing.T) {
+	g := NewWithT(t)
+	registry, podTemplate := podTemplateFixtures()
+	u, err := podTemplateRegistry(registry, &types.PodTemplate{Tolerations: podTemplate.Tolerations})
+	g.Expect(err).ToNot(HaveOccurred())
+	_, found, _ := unstructured.NestedFieldNoCopy(u.Object, EnvField...)
+	g.Expect(found).To(BeFalse())
+	_, found, _ = unstructured.NestedFieldNoCopy(u.Object, PodTemplateField...)
+	g.Expect(found).To(BeFalse())
+}
+
+func TestPodTemplateSetsTheRegistryResourcesOfTheDeploymentSizeUnlessCustomized(t *testing.T) {
+	g := NewWithT(t)
+	_, podTemplate := podTemplateFixtures()
+	small := types.SmallSize.Profile().Registry
+	g.Expect(registryPodTemplate(&types.TestContext{Size: types.SmallSize}, true).Resources).To(Equal(&small))
+	normal := types.NormalSize.Profile().Registry
+	g.Expect(registryPodTemplate(&types.TestContext{}, true).Resources).To(Equal(&normal))
+
+	pt := registryPodTemplate(&types.TestContext{Size: types.SmallSize, PodTemplate: podTemplate}, true)
+	g.Expect(pt.Resources).To(Equal(podTemplate.Resources))
+	g.Expect(pt.Env).To(Equal(podTemplate.Env))
+
+	pt = registryPodTemplate(&types.TestContext{Size: types.SmallSize, PodTemplate: &types.PodTemplate{Labels: podTemplate.Labels}}, true)
+	g.Expect(pt.Resources).To(Equal(&small))
+	g.Expect(pt.Labels).To(Equal(podTemplate.Labels))
+}
+
+func TestPodTemplateDoesntSetTheRegistryResourcesOfTheDeploymentSizeIfTheOperatorDoesntSupportPodTemplates(t *testing.T) {
+	g := NewWithT(t)
+	_, podTemplate := podTemplateFixtures()
+	g.Expect(registryPodTemplate(&types.TestContext{Size: types.SmallSize}, false).Resources).To(BeNil())
+
+	pt := registryPodTemplate(&types.TestContext{Size: types.SmallSize, PodTemplate: podTemplate}, false)
+	g.Expect(pt.Resources).To(Equal(podTemplate.Resources))
+}
+
+func TestPrunedFieldsReportsTheRequestedFieldsTheApiServerPruned(t *testing.T) {
+	g := NewWithT(t)
+	registry, podTemplate := podTemplateFixtures()
+	requested, err := podTemplateRegistry(registry, podTemplate)
+	g.Expect(err).ToNot(HaveOccurred())
+	current := requested.DeepCopy()
+	unstructured.RemoveNestedField(current.Object, PodTemplateField...)
+
+	g.Expect(prunedFields(requested, requested, EnvField, PodTemplateField)).To(BeEmpty())
+	g.Expect(prunedFields(requested, current, EnvField, PodTemplateField)).To(Equal([]string{"spec.deployment.podTemplateSpecPreview"}))
+}
+
+func TestRegistryContainerOfNewerAndOlderOperators(t *testing.T) {
+	g := NewWithT(t)
+	sidecar := corev1.Container{Name: "sidecar"}
+	newer := &corev1.PodSpec{Containers: []corev1.Container{sidecar, {Name: RegistryContainerName}}}
+	g.Expect(RegistryContainer(newer, "my-registry").Name).To(Equal(RegistryContainerName))
+	older := &corev1.PodSpec{Containers: []corev1.Container{sidecar, {Name: "my-registry"}}}
+	g.Expect(RegistryContainer(older, "my-registry").Name).To(Equal("my-registry"))
+	single := &corev1.PodSpec{Containers: []corev1.Container{{Name: "apicurio-registry-sql"}}}
+	g.Expect(RegistryContainer(single, "my-registry").Name).To(Equal("apicurio-registry-sql"))
+	g.Expect(RegistryContainer(&corev1.PodSpec{Containers: []corev1.Container{sidecar, sidecar}}, "my-registry")).To(BeNil())
+}
@@ -0,0 +1,194 @@
+package probe
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//Metrics metric families of a prometheus text exposition, indexed by sample name.
+//Histogram and summary series, i.e. _bucket, _sum and _count, are kept as families of their own
+type Metrics map[string]*Family
+
+//Family samples sharing the same name, along with the type and help declared for them, if any
+type Family struct {
+	Name    string
+	Type    string
+	Help    string
+	Samples []Sample
+}
+
+//Sample one value of a metric
+type Sample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+//ParseMetrics parses the prometheus text exposition format
+func ParseMetrics(text string) (Metrics, error) {
+	metrics := Metrics{}
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			parseComment(metrics, line)
+			continue
+		}
+		name, sample, err := parseSample(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metrics line %v: %v", i+1, err)
+		}
+		family := metrics.family(name)
+		family.Samples = append(family.Samples, *sample)
+	}
+	return metrics, nil
+}
+
+func (m Metrics) family(name string) *Family {
+	family, exists := m[name]
+	if !exists {
+		family = &Family{Name: name}
+		m[name] = family
+	}
+	return family
+}
+
+//parseComment keeps HELP and TYPE declarations, any other comment is ignored
+func parseComment(metrics Metrics, line string) {
+	fields := strings.SplitN(strings.TrimSpace(strings.TrimPrefix(line, "#")), " ", 3)
+	if len(fields) < 3 {
+		return
+	}
+	switch fields[0] {
+	case "HELP":
+		metrics.family(fields[1]).Help = fields[2]
+	case "TYPE":
+		metrics.family(fields[1]).Type = fields[2]
+	}
+}
+
+//parseSample parses lines like name{label="value",...} value [timestamp]
+func parseSample(line string) (string, *Sample, error) {
+	end := strings.IndexAny(line, "{ \t")
+	if end <= 0 {
+		return "", nil, fmt.Errorf("missing value in %q", line)
+	}
+	name := line[:end]
+	rest := line[end:]
+
+	sample := &Sample{Labels: map[string]string{}}
+	if strings.HasPrefix(rest, "{") {
+		var err error
+		rest, err = parseLabels(rest[1:], sample.Labels)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 || len(fields) > 2 {
+		return "", nil, fmt.Errorf("expected value and optional timestamp in %q", line)
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return "", nil, err
+	}
+	sample.Value = value
+	return name, sample, nil
+}
+
+//parseLabels parses the labels up to the closing brace, returning what follows it
+func parseLabels(text string, labels map[string]string) (string, error) {
+	for {
+		text = strings.TrimLeft(text, " \t,")
+		if strings.HasPrefix(text, "}") {
+			return text[1:], nil
+		}
+		eq := strings.Index(text, "=")
+		if eq <= 0 || len(text) < eq+2 || text[eq+1] != '"' {
+			return "", fmt.Errorf("invalid label in %q", text)
+		}
+		key := strings.TrimSpace(text[:eq])
+
+		var value strings.Builder
+		i := eq + 2
+		for ; i < len(text) && text[i] != '"'; i++ {
+			if text[i] == '\\' && i+1 < len(text) {
+				i++
+				switch text[i] {
+				case 'n':
+					value.WriteByte('\n')
+				default:
+					value.WriteByte(text[i])
+				}
+				continue
+			}
+			value.WriteByte(text[i])
+		}
+		if i >= len(text) {
+			return "", fmt.Errorf("unterminated value of label %v", key)
+		}
+		labels[key] = value.String()
+		text = text[i+1:]
+	}
+}
+
+//Names sorted names of every family
+func (m Metrics) Names() []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//WithPrefix families whose name starts with prefix and have samples
+func (m Metrics) WithPrefix(prefix string) []*Family {
+	families := []*Family{}
+	for _, name := range m.Names() {
+		if strings.HasPrefix(name, prefix) && len(m[name].Samples) != 0 {
+			families = append(families, m[name])
+		}
+	}
+	return families
+}
+
+//Value value of the first sample of the metric having every given label, false if there is none
+func (m Metrics) Value(name string, labels map[string]string) (float64, bool) {
+	family, exists := m[name]
+	if !exists {
+		return 0, false
+	}
+	for _, s := range family.Samples {
+		if s.hasLabels(labels) {
+			return s.Value, true
+		}
+	}
+	return 0, false
+}
+
+//Sum total of the values of every sample of the metric having every given label
+func (m Metrics) Sum(name string, labels map[string]string) float64 {
+	total := 0.0
+	if family, exists := m[name]; exists {
+		for _, s := range family.Samples {
+			if s.hasLabels(labels) {
+				total += s.Value
+			}
+		}
+	}
+	return total
+}
+
+func (s *Sample) hasLabels(labels map[string]string) bool {
+	for k, v := range labels {
+		if s.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,157 @@
+package probe
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+)
+
+const (
+	StatusUp   HealthStatus = "UP"
+	StatusDown HealthStatus = "DOWN"
+)
+
+//HealthStatus status of the registry or of one of its health checks
+type HealthStatus string
+
+//Health report of the registry health endpoints
+type Health struct {
+	Status HealthStatus  `json:"status"`
+	Checks []HealthCheck `json:"checks"`
+}
+
+//HealthCheck result of one health check, data depends on the check
+type HealthCheck struct {
+	Name   string                 `json:"name"`
+	Status HealthStatus           `json:"status"`
+	Data   map[string]interface{} `json:"data,omitempty"`
+}
+
+//SystemInfo name and version of the registry build
+type SystemInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+	BuiltOn     string `json:"builtOn"`
+}
+
+//Prober reads the health, system info and metrics endpoints of a registry
+type Prober struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+//NewProber creates a prober for the registry reachable at endpoint, honoring its scheme and trusted certificates
+func NewProber(endpoint *types.RegistryEndpoint) (*Prober, error) {
+	httpClient, err := endpoint.HTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	return NewProberForURL(endpoint.URL(), httpClient), nil
+}
+
+//NewProberForURL creates a prober for the registry served at baseURL
+func NewProberForURL(baseURL string, httpClient *http.Client) *Prober {
+	return &Prober{baseURL: strings.TrimSuffix(baseURL, "/"), httpClient: httpClient}
+}
+
+//Ready returns the readiness report, a registry that is not ready responds 503 along with the report
+func (p *Prober) Ready() (*Health, error) {
+	return p.health("/health/ready")
+}
+
+//Live returns the liveness report, a registry that is not live responds 503 along with the report
+func (p *Prober) Live() (*Health, error) {
+	return p.health("/health/live")
+}
+
+func (p *Prober) health(path string) (*Health, error) {
+	data, err := p.get(path, "application/json", http.StatusOK, http.StatusServiceUnavailable)
+	if err != nil {
+		return nil, err
+	}
+	health := &Health{}
+	if err := json.Unmarshal(data, health); err != nil {
+		return nil, fmt.Errorf("invalid health report from %v: %v", path, err)
+	}
+	return health, nil
+}
+
+//SystemInfo returns the name and version of the running registry
+func (p *Prober) SystemInfo() (*SystemInfo, error) {
+	data, err := p.get("/apis/registry/v2/system/info", "application/json", http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	info := &SystemInfo{}
+	if err := json.Unmarshal(data, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+//Metrics reads and parses the prometheus metrics of the registry
+func (p *Prober) Metrics() (Metrics, error) {
+	data, err := p.get("/metrics", "text/plain", http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return ParseMetrics(string(data))
+}
+
+func (p *Prober) get(path string, accept string, expectedStatus ...int) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, p.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range expectedStatus {
+		if resp.StatusCode == status {
+			return data, nil
+		}
+	}
+	return nil, fmt.Errorf("%v responded with status %v: %v", path, resp.StatusCode, strings.TrimSpace(string(data)))
+}
+
+//IsUp true if the overall status and every check are UP
+func (h *Health) IsUp() bool {
+	if h.Status != StatusUp {
+		return false
+	}
+	return len(h.Down()) == 0
+}
+
+//Down names of the checks not reporting UP
+func (h *Health) Down() []string {
+	down := []string{}
+	for _, c := range h.Checks {
+		if c.Status != StatusUp {
+			down = append(down, c.Name)
+		}
+	}
+	return down
+}
+
+//Find checks whose name contains the given text, ignoring case
+func (h *Health) Find(text string) []HealthCheck {
+	found := []HealthCheck{}
+	for _, c := range h.Checks {
+		if strings.Contains(strings.ToLower(c.Name), strings.ToLower(text)) {
+			found = append(found, c)
+		}
+	}
+	return found
+}
@@ -0,0 +1,137 @@
+package probe
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	apicurioclient "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/client"
+)
+
+func TestParsesFamiliesLabelsAndValues(t *testing.T) {
+	g := NewWithT(t)
+	metrics, err := ParseMetrics(`
+# HELP jvm_threads_live_threads The current number of live threads
+# TYPE jvm_threads_live_threads gauge
+jvm_threads_live_threads 42.0
+# TYPE http_server_requests_seconds_count counter
+http_server_requests_seconds_count{method="GET",status="200",uri="/apis/registry/v2/groups",} 7.0 1622700000000
+http_server_requests_seconds_count{method="POST",status="200",uri="/apis/registry/v2/groups",} 3.0
+http_server_requests_seconds_count{method="GET",status="404",uri="quoted \"uri\"\nwith newline",} 1.0
+base_gc_total{name="G1 Young Generation"} 5
+`)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(metrics.Names()).To(Equal([]string{"base_gc_total", "http_server_requests_seconds_count", "jvm_threads_live_threads"}))
+
+	threads := metrics["jvm_threads_live_threads"]
+	g.Expect(threads.Type).To(Equal("gauge"))
+	g.Expect(threads.Help).To(Equal("The current number of live threads"))
+	g.Expect(threads.Samples).To(Equal([]Sample{{Labels: map[string]string{}, Value: 42}}))
+
+	value, found := metrics.Value("http_server_requests_seconds_count", map[string]string{"method": "POST"})
+	g.Expect(found).To(BeTrue())
+	g.Expect(value).To(Equal(3.0))
+	g.Expect(metrics.Sum("http_server_requests_seconds_count", map[string]string{"method": "GET"})).To(Equal(8.0))
+	g.Expect(metrics.Sum("http_server_requests_seconds_count", nil)).To(Equal(11.0))
+
+	value, found = metrics.Value("http_server_requests_seconds_count", map[string]string{"status": "404"})
+	g.Expect(found).To(BeTrue())
+	g.Expect(value).To(Equal(1.0))
+	g.Expect(metrics["http_server_requests_seconds_count"].Samples[2].Labels["uri"]).To(Equal("quoted \"uri\"\nwith newline"))
+
+	_, found = metrics.Value("missing", nil)
+	g.Expect(found).To(BeFalse())
+	g.Expect(metrics.WithPrefix("jvm_")).To(HaveLen(1))
+	g.Expect(metrics.WithPrefix("base_")).To(HaveLen(1))
+}
+
+func TestSkipsDeclarationsOfFamiliesWithoutSamples(t *testing.T) {
+	g := NewWithT(t)
+	metrics, err := ParseMetrics("# HELP empty_total nothing yet\n# TYPE empty_total counter\n")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(metrics.WithPrefix("empty")).To(BeEmpty())
+}
+
+func TestRejectsMalformedLines(t *testing.T) {
+	g := NewWithT(t)
+	for _, text := range []string{
+		"missing_value",
+		"bad_value{a=\"b\"} abc",
+		"bad_label{a=b} 1",
+		"unterminated{a=\"b} 1",
+		"too_many 1 2 3",
+	} {
+		_, err := ParseMetrics(text)
+		g.Expect(err).To(HaveOccurred(), text)
+	}
+}
+
+//newFakeProber starts a fake registry, closed when the test ends, and a prober of it
+func newFakeProber(t *testing.T) (*apicurioclient.FakeRegistry, *Prober) {
+	registry := apicurioclient.NewFakeRegistry()
+	t.Cleanup(registry.Close)
+	return registry, NewProberForURL(registry.URL(), http.DefaultClient)
+}
+
+func TestReadsTheHealthReports(t *testing.T) {
+	g := NewWithT(t)
+	_, prober := newFakeProber(t)
+	ready, err := prober.Ready()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(ready.IsUp()).To(BeTrue())
+	g.Expect(ready.Find("persistence")).ToNot(BeEmpty())
+
+	live, err := prober.Live()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(live.IsUp()).To(BeTrue())
+}
+
+func TestReportsTheChecksThatAreDown(t *testing.T) {
+	g := NewWithT(t)
+	registry, prober := newFakeProber(t)
+	registry.SetHealthCheckDown("PersistenceSimpleReadinessCheck", true)
+
+	ready, err := prober.Ready()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(ready.IsUp()).To(BeFalse())
+	g.Expect(ready.Status).To(Equal(StatusDown))
+	g.Expect(ready.Down()).To(Equal([]string{"PersistenceSimpleReadinessCheck"}))
+
+	live, err := prober.Live()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(live.IsUp()).To(BeTrue())
+
+	registry.SetHealthCheckDown("PersistenceSimpleReadinessCheck", false)
+	ready, err = prober.Ready()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(ready.IsUp()).To(BeTrue())
+}
+
+func TestReadsTheSystemInfo(t *testing.T) {
+	g := NewWithT(t)
+	_, prober := newFakeProber(t)
+	info, err := prober.SystemInfo()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(info.Version).To(Equal(apicurioclient.FakeRegistryVersion))
+	g.Expect(info.Name).ToNot(BeEmpty())
+}
+
+func TestReadsTheMetrics(t *testing.T) {
+	g := NewWithT(t)
+	registry, prober := newFakeProber(t)
+	_, err := registry.Client().ListGroups()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	metrics, err := prober.Metrics()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(metrics.WithPrefix("jvm_")).ToNot(BeEmpty())
+	g.Expect(metrics.Sum("rest_requests_total", map[string]string{"method": "GET"})).To(BeNumerically(">=", 1))
+}
+
+func TestFailsOnUnexpectedResponses(t *testing.T) {
+	g := NewWithT(t)
+	registry, _ := newFakeProber(t)
+	_, err := NewProberForURL(registry.URL()+"/missing", http.DefaultClient).Metrics()
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,138 @@
+package apicurio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+	apicurio "github.com/Apicurio/apicurio-registry-operator/api/v1"
+)
+
+//ReadyConditionType condition the operator sets to True once the registry is deployed and serving
+const ReadyConditionType string = "Ready"
+
+//RegistryReadiness readiness of one ApicurioRegistry and, when it's not ready, what is blocking it
+type RegistryReadiness struct {
+	Ready bool
+	//ConditionBased false for older operators not reporting status conditions, readiness is then guessed from the registry deployment
+	ConditionBased bool
+	Blocking       string
+}
+
+//GetRegistryReadiness reads the ApicurioRegistry and its deployment and evaluates their readiness
+func GetRegistryReadiness(suiteCtx *types.SuiteContext, namespace string, registryName string, registryReplicas int32) (*RegistryReadiness, error) {
+	registry := &apicurio.ApicurioRegistry{}
+	err := suiteCtx.K8sClient.Get(context.TODO(), kubetypes.NamespacedName{Name: registryName, Namespace: namespace}, registry)
+	if err != nil {
+		return nil, err
+	}
+	deployment, err := findRegistryDeployment(suiteCtx, registry)
+	if err != nil {
+		return nil, err
+	}
+	readiness := registryReadiness(registry, deployment, registryReplicas)
+	return &readiness, nil
+}
+
+//findRegistryDeployment the deployment listed in the managed resources of the registry, or the one labeled with the registry name for older operators.
+//Returns nil if there is no deployment yet
+func findRegistryDeployment(suiteCtx *types.SuiteContext, registry *apicurio.ApicurioRegistry) (*appsv1.Deployment, error) {
+	for _, r := range registry.Status.ManagedResources {
+		if r.Kind != "Deployment" {
+			continue
+		}
+		namespace := r.Namespace
+		if namespace == "" {
+			namespace = registry.Namespace
+		}
+		deployment, err := suiteCtx.Clientset.AppsV1().Deployments(namespace).Get(context.TODO(), r.Name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return deployment, err
+	}
+	labelsSet := labels.Set(map[string]string{"app": registry.Name})
+	deployments, err := suiteCtx.Clientset.AppsV1().Deployments(registry.Namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: labelsSet.AsSelector().String()})
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if deployments == nil || len(deployments.Items) == 0 {
+		return nil, nil
+	}
+	return &deployments.Items[0], nil
+}
+
+//registryReadiness evaluates the status conditions and info of the registry, falling back to the deployment available replicas when the operator reports no conditions.
+//Replicas are checked in both cases, the Ready condition doesn't wait for every replica of clustered registries
+func registryReadiness(registry *apicurio.ApicurioRegistry, deployment *appsv1.Deployment, replicas int32) RegistryReadiness {
+	if len(registry.Status.Conditions) == 0 {
+		return RegistryReadiness{ConditionBased: false, Blocking: deploymentBlocking(deployment, replicas)}.done()
+	}
+	readiness := RegistryReadiness{ConditionBased: true}
+
+	ready := meta.FindStatusCondition(registry.Status.Conditions, ReadyConditionType)
+	switch {
+	case ready == nil:
+		readiness.Blocking = "condition " + ReadyConditionType + " not reported" + otherConditions(registry.Status.Conditions)
+	case ready.ObservedGeneration != 0 && ready.ObservedGeneration < registry.Generation:
+		readiness.Blocking = fmt.Sprintf("condition %v observed generation %v, registry is at generation %v", ReadyConditionType, ready.ObservedGeneration, registry.Generation)
+	case ready.Status != metav1.ConditionTrue:
+		readiness.Blocking = describeCondition(ready) + otherConditions(registry.Status.Conditions)
+	case registry.Spec.Deployment.Host != "" && registry.Status.Info.Host != registry.Spec.Deployment.Host:
+		readiness.Blocking = fmt.Sprintf("status info host is %q, expected %q", registry.Status.Info.Host, registry.Spec.Deployment.Host)
+	default:
+		readiness.Blocking = deploymentBlocking(deployment, replicas)
+	}
+	return readiness.done()
+}
+
+func (r RegistryReadiness) done() RegistryReadiness {
+	r.Ready = r.Blocking == ""
+	return r
+}
+
+func deploymentBlocking(deployment *appsv1.Deployment, replicas int32) string {
+	if deployment == nil {
+		return "registry deployment not found"
+	}
+	if deployment.Status.AvailableReplicas != replicas {
+		return fmt.Sprintf("deployment %v has %v of %v replicas available", deployment.Name, deployment.Status.AvailableReplicas, replicas)
+	}
+	return ""
+}
+
+func describeCondition(c *metav1.Condition) string {
+	description := fmt.Sprintf("condition %v is %v", c.Type, c.Status)
+	if c.Reason != "" {
+		description += ", reason " + c.Reason
+	}
+	if c.Message != "" {
+		description += ": " + c.Message
+	}
+	return description
+}
+
+//otherConditions the conditions besides Ready that are True, they usually explain why the registry is not ready
+func otherConditions(conditions []metav1.Condition) string {
+	others := []string{}
+	for i := range conditions {
+		c := &conditions[i]
+		if c.Type != ReadyConditionType && c.Status == metav1.ConditionTrue {
+			others = append(others, describeCondition(c))
+		}
+	}
+	if len(others) == 0 {
+		return ""
+	}
+	sort.Strings(others)
+	return " (" + strings.Join(others, "; ") + ")"
+}
@@ -0,0 +1,103 @@
+package apicurio
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apicurio "github.com/Apicurio/apicurio-registry-operator/api/v1"
+)
+
+//readinessFixtures a registry at generation 2 and its deployment with one replica available
+func readinessFixtures() (*apicurio.ApicurioRegistry, *appsv1.Deployment) {
+	registry := &apicurio.ApicurioRegistry{ObjectMeta: metav1.ObjectMeta{Name: "registry", Generation: 2}}
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "registry-deployment"}}
+	deployment.Status.AvailableReplicas = 1
+	return registry, deployment
+}
+
+func condition(conditionType string, status metav1.ConditionStatus, reason string, message string) metav1.Condition {
+	return metav1.Condition{Type: conditionType, Status: status, Reason: reason, Message: message, ObservedGeneration: 2}
+}
+
+func TestReadinessWithoutConditionsIsReadyOnceTheDeploymentHasEveryReplicaAvailable(t *testing.T) {
+	g := NewWithT(t)
+	registry, deployment := readinessFixtures()
+	readiness := registryReadiness(registry, deployment, 1)
+	g.Expect(readiness).To(Equal(RegistryReadiness{Ready: true}))
+}
+
+func TestReadinessWithoutConditionsReportsTheMissingDeploymentAndTheUnavailableReplicas(t *testing.T) {
+	g := NewWithT(t)
+	registry, deployment := readinessFixtures()
+	readiness := registryReadiness(registry, nil, 1)
+	g.Expect(readiness.Ready).To(BeFalse())
+	g.Expect(readiness.Blocking).To(Equal("registry deployment not found"))
+
+	readiness = registryReadiness(registry, deployment, 3)
+	g.Expect(readiness.Blocking).To(Equal("deployment registry-deployment has 1 of 3 replicas available"))
+}
+
+func TestReadinessWithConditionsIsReadyWhenTheReadyConditionIsTrueAndReplicasAreAvailable(t *testing.T) {
+	g := NewWithT(t)
+	registry, deployment := readinessFixtures()
+	registry.Status.Conditions = []metav1.Condition{condition(ReadyConditionType, metav1.ConditionTrue, "Reconciled", "")}
+	readiness := registryReadiness(registry, deployment, 1)
+	g.Expect(readiness).To(Equal(RegistryReadiness{Ready: true, ConditionBased: true}))
+}
+
+func TestReadinessWithConditionsReportsTheReadyConditionAlongWithTheOtherTrueConditions(t *testing.T) {
+	g := NewWithT(t)
+	registry, deployment := readinessFixtures()
+	registry.Status.Conditions = []metav1.Condition{
+		condition(ReadyConditionType, metav1.ConditionFalse, "Error", "reconciliation failed"),
+		condition("ValidationError", metav1.ConditionTrue, "InvalidSpec", "unknown persistence xyz"),
+		condition("Warning", metav1.ConditionFalse, "", ""),
+	}
+	readiness := registryReadiness(registry, deployment, 1)
+	g.Expect(readiness.Ready).To(BeFalse())
+	g.Expect(readiness.ConditionBased).To(BeTrue())
+	g.Expect(readiness.Blocking).To(Equal("condition Ready is False, reason Error: reconciliation failed (condition ValidationError is True, reason InvalidSpec: unknown persistence xyz)"))
+}
+
+func TestReadinessWithConditionsWaitsForTheReadyConditionToBeReported(t *testing.T) {
+	g := NewWithT(t)
+	registry, deployment := readinessFixtures()
+	registry.Status.Conditions = []metav1.Condition{condition("Initializing", metav1.ConditionTrue, "", "")}
+	readiness := registryReadiness(registry, deployment, 1)
+	g.Expect(readiness.Blocking).To(Equal("condition Ready not reported (condition Initializing is True)"))
+}
+
+func TestReadinessWithConditionsWaitsForTheOperatorToObserveTheLatestGeneration(t *testing.T) {
+	g := NewWithT(t)
+	registry, deployment := readinessFixtures()
+	ready := condition(ReadyConditionType, metav1.ConditionTrue, "", "")
+	ready.ObservedGeneration = 1
+	registry.Status.Conditions = []metav1.Condition{ready}
+	readiness := registryReadiness(registry, deployment, 1)
+	g.Expect(readiness.Blocking).To(Equal("condition Ready observed generation 1, registry is at generation 2"))
+}
+
+func TestReadinessWithConditionsWaitsForTheHostInTheStatusInfo(t *testing.T) {
+	g := NewWithT(t)
+	registry, deployment := readinessFixtures()
+	registry.Spec.Deployment.Host = "registry.127.0.0.1.nip.io"
+	registry.Status.Conditions = []metav1.Condition{condition(ReadyConditionType, metav1.ConditionTrue, "", "")}
+	readiness := registryReadiness(registry, deployment, 1)
+	g.Expect(readiness.Blocking).To(Equal(`status info host is "", expected "registry.127.0.0.1.nip.io"`))
+
+	registry.Status.Info.Host = registry.Spec.Deployment.Host
+	g.Expect(registryReadiness(registry, deployment, 1).Ready).To(BeTrue())
+}
+
+func TestReadinessWithConditionsWaitsForEveryReplicaOfClusteredRegistries(t *testing.T) {
+	g := NewWithT(t)
+	registry, deployment := readinessFixtures()
+	registry.Status.Conditions = []metav1.Condition{condition(ReadyConditionType, metav1.ConditionTrue, "", "")}
+	readiness := registryReadiness(registry, deployment, 3)
+	g.Expect(readiness.Ready).To(BeFalse())
+	g.Expect(readiness.Blocking).To(Equal("deployment registry-deployment has 1 of 3 replicas available"))
+}
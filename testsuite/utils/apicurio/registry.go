@@ -2,23 +2,29 @@ package apicurio
 
 import (
 	"context"
+	"fmt"
 	"strconv"
-	"time"
 
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	. "github.com/onsi/gomega"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	kubetypes "k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils"
+	kubernetesutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetes"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetescli"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/timeouts"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/waiter"
 	apicurio "github.com/Apicurio/apicurio-registry-operator/api/v1"
+	routev1 "github.com/openshift/api/route/v1"
 )
 
 var log = logf.Log.WithName("apicurio")
@@ -32,6 +38,9 @@ func CreateRegistryAndWait(suiteCtx *types.SuiteContext, ctx *types.TestContext,
 		registry.Spec.Deployment.Host = registry.Name + ".127.0.0.1.nip.io"
 		ctx.RegistryHost = registry.Name + ".127.0.0.1.nip.io"
 		ctx.RegistryPort = "80"
+		if ctx.RegistryTLS != nil {
+			ctx.RegistryPort = "443"
+		}
 	}
 
 	//TODO review
@@ -40,7 +49,7 @@ func CreateRegistryAndWait(suiteCtx *types.SuiteContext, ctx *types.TestContext,
 		registry.Namespace = ctx.RegistryNamespace
 	}
 
-	err := suiteCtx.K8sClient.Create(context.TODO(), registry)
+	err := createRegistry(suiteCtx, ctx, registry)
 	Expect(err).ToNot(HaveOccurred())
 
 	var registryReplicas int32 = 1
@@ -49,36 +58,38 @@ func CreateRegistryAndWait(suiteCtx *types.SuiteContext, ctx *types.TestContext,
 	}
 
 	WaitForRegistryReady(suiteCtx, registry.Namespace, registry.Name, registryReplicas)
+	err = recordOperatorDefaultResources(suiteCtx, registry)
+	Expect(err).ToNot(HaveOccurred())
 
 	labelsSet := labels.Set(map[string]string{"app": registry.Name})
 
 	if suiteCtx.IsOpenshift {
 		kubernetescli.Execute("get", "route", "-n", ctx.RegistryNamespace)
 
-		//TODO make this timeout configurable
-		timeout := 90 * time.Second
+		timeout := timeouts.Get(timeouts.RegistryRoute)
 		log.Info("Waiting for registry route to be ready", "timeout", timeout)
-		err = wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-			routes, err := suiteCtx.OcpRouteClient.Routes(ctx.RegistryNamespace).List(context.TODO(), metav1.ListOptions{LabelSelector: labelsSet.AsSelector().String()})
-			if err != nil && !errors.IsNotFound(err) {
-				return false, err
-			}
-			if len(routes.Items) != 0 && len(routes.Items[0].Status.Ingress) != 0 {
-
+		readyRoutes := &routev1.RouteList{}
+		record, err := suiteCtx.Waiter.Timeout(timeout).Describe("registry route to be ready").
+			ForList(readyRoutes, func(list client.ObjectList) (bool, error) {
+				routes := list.(*routev1.RouteList)
+				if len(routes.Items) == 0 || len(routes.Items[0].Status.Ingress) == 0 {
+					return false, nil
+				}
 				host := routes.Items[0].Status.Ingress[0].Host
 
 				//the operator first sets the route with a non valid host, and later updates it
 				if (host == (registry.Name + "." + registry.Namespace)) || (host == registry.Name) {
 					return false, nil
-				} else {
-					log.Info("Registry route is ready", "default", registry.Name+"."+registry.Namespace, "ready", host)
-					return true, nil
 				}
-			}
-			return false, nil
-		})
+				log.Info("Registry route is ready", "default", registry.Name+"."+registry.Namespace, "ready", host)
+				return true, nil
+			}, client.InNamespace(ctx.RegistryNamespace), client.MatchingLabels(labelsSet))
+		waiter.LogOnError(record, err)
 		kubernetescli.Execute("get", "route", "-n", ctx.RegistryNamespace)
 		Expect(err).ToNot(HaveOccurred())
+		if ctx.RegistryTLS != nil {
+			configureOpenshiftTLS(suiteCtx, ctx, registry, readyRoutes.Items[0].Status.Ingress[0].Host)
+		}
 		routes, err := suiteCtx.OcpRouteClient.Routes(ctx.RegistryNamespace).List(context.TODO(), metav1.ListOptions{LabelSelector: labelsSet.AsSelector().String()})
 		Expect(err).ToNot(HaveOccurred())
 		Expect(len(routes.Items)).To(BeIdenticalTo(1))
@@ -87,6 +98,16 @@ func CreateRegistryAndWait(suiteCtx *types.SuiteContext, ctx *types.TestContext,
 
 		ctx.RegistryHost = route.Status.Ingress[0].Host
 		ctx.RegistryPort = "80"
+		if route.Spec.TLS != nil {
+			if ctx.RegistryTLS == nil {
+				ctx.RegistryTLS = &types.TLSConfig{}
+			}
+			ctx.RegistryTLS.CABundle = append(ctx.RegistryTLS.CABundle, routeCABundle(suiteCtx, &route)...)
+			ctx.RegistryPort = "443"
+			waitForTLSEndpoint(ctx)
+		}
+	} else if ctx.RegistryTLS != nil {
+		configureKubernetesTLS(suiteCtx, ctx, registry)
 	}
 
 	kubernetescli.Execute("get", "svc", "-n", ctx.RegistryNamespace)
@@ -107,61 +128,83 @@ func CreateRegistryAndWait(suiteCtx *types.SuiteContext, ctx *types.TestContext,
 
 	ctx.RegistryResource = &apicurioRegistry
 
+	VerifyManagedObjects(suiteCtx, &apicurioRegistry, registryReplicas)
+
 }
 
+//WaitForRegistryReady waits for the registry to be ready according to its status conditions, or to its deployment for older operators,
+//failing with the condition that blocked readiness on timeout
 func WaitForRegistryReady(suiteCtx *types.SuiteContext, namespace string, registryName string, registryReplicas int32) {
 
-	// var registryDeploymentName string = registryName
-
-	timeout := 15 * time.Second
-	log.Info("Waiting for registry CR", "timeout", timeout)
-	apicurioRegistry := apicurio.ApicurioRegistry{}
-	err := wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		err := suiteCtx.K8sClient.Get(context.TODO(),
-			kubetypes.NamespacedName{Name: registryName, Namespace: namespace},
-			&apicurioRegistry)
-
-		if err != nil {
-			if errors.IsNotFound(err) {
-				//continue waiting
-				return false, nil
-			}
-			return false, err
-		}
-		// TODO operator is not updating status
-		// if apicurioRegistry.Status.DeploymentName != "" {
-		// 	// registryDeploymentName = apicurioRegistry.Status.DeploymentName
-		// 	return true, nil
-		// }
-		return true, nil
-	})
+	key := kubetypes.NamespacedName{Name: registryName, Namespace: namespace}
+	record, err := suiteCtx.Waiter.Timeout(timeouts.Get(timeouts.RegistryCR)).ForObject(key, &apicurio.ApicurioRegistry{}, waiter.Exists)
+	waiter.LogOnError(record, err)
 	kubernetescli.Execute("get", "apicurioregistry", "-n", namespace)
 	Expect(err).ToNot(HaveOccurred())
 
-	timeout = 180 * time.Second
+	timeout := timeouts.Get(timeouts.RegistryReady)
 	if registryReplicas > 1 {
-		timeout = 300 * time.Second
+		timeout = timeouts.Get(timeouts.ClusteredRegistryReady)
 	}
-	log.Info("Waiting for registry deployment to be ready", "timeout", timeout)
-	err = wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		labelsSet := labels.Set(map[string]string{"app": registryName})
-
-		deployments, err := suiteCtx.Clientset.AppsV1().Deployments(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: labelsSet.AsSelector().String()})
-		if err != nil && !errors.IsNotFound(err) {
-			return false, err
-		}
-		if len(deployments.Items) != 0 {
-			registryDeployment := deployments.Items[0]
-			if registryDeployment.Status.AvailableReplicas == registryReplicas {
-				return true, nil
+	var readiness RegistryReadiness
+	record, err = suiteCtx.Waiter.Timeout(timeout).Describe("registry "+registryName+" to be ready").TriggeredBy(&appsv1.Deployment{}).
+		ForObject(key, &apicurio.ApicurioRegistry{}, func(obj client.Object, exists bool) (bool, error) {
+			if !exists {
+				return false, fmt.Errorf("registry %v removed while waiting for it to be ready", registryName)
 			}
-		}
-		return false, nil
-	})
+			registry := obj.(*apicurio.ApicurioRegistry)
+			deployment, err := findRegistryDeployment(suiteCtx, registry)
+			if err != nil {
+				return false, err
+			}
+			readiness = registryReadiness(registry, deployment, registryReplicas)
+			return readiness.Ready, nil
+		})
+	if waiter.IsTimeout(err) {
+		err = fmt.Errorf("registry %v not ready: %v", registryName, readiness.Blocking)
+	}
+	waiter.LogOnError(record, err)
 	kubernetescli.GetPods(namespace)
 	kubernetescli.Execute("get", "apicurioregistry", "-o", "yaml", "-n", namespace)
 	Expect(err).ToNot(HaveOccurred())
-	log.Info("Registry deployment is ready")
+	log.Info("Registry is ready", "conditionBased", readiness.ConditionBased)
+}
+
+//RestartRegistry deletes every pod of the registry and waits until all of them are replaced by new ready pods
+func RestartRegistry(suiteCtx *types.SuiteContext, namespace string, registryName string) {
+	labelsSet := labels.Set(map[string]string{"app": registryName})
+	listOptions := metav1.ListOptions{LabelSelector: labelsSet.AsSelector().String()}
+
+	pods, err := suiteCtx.Clientset.CoreV1().Pods(namespace).List(context.TODO(), listOptions)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(pods.Items).ToNot(BeEmpty())
+	oldPods := map[kubetypes.UID]bool{}
+	for _, pod := range pods.Items {
+		oldPods[pod.UID] = true
+	}
+	replicas := len(pods.Items)
+
+	log.Info("Restarting registry", "name", registryName, "pods", replicas)
+	err = suiteCtx.Clientset.CoreV1().Pods(namespace).DeleteCollection(context.TODO(), metav1.DeleteOptions{}, listOptions)
+	Expect(err).ToNot(HaveOccurred())
+
+	record, err := suiteCtx.Waiter.Timeout(timeouts.Get(timeouts.RegistryRestart)).Describe("registry pods to be replaced").Summarize(podSummary).
+		ForList(&corev1.PodList{}, func(list client.ObjectList) (bool, error) {
+			ready := 0
+			for _, pod := range list.(*corev1.PodList).Items {
+				if oldPods[pod.UID] {
+					return false, nil
+				}
+				if kubernetesutils.IsPodReady(&pod) {
+					ready++
+				}
+			}
+			return ready == replicas, nil
+		}, client.InNamespace(namespace), client.MatchingLabels{"app": registryName})
+	waiter.LogOnError(record, err)
+	kubernetescli.GetPods(namespace)
+	Expect(err).ToNot(HaveOccurred())
+	log.Info("Registry restarted")
 }
 
 //DeleteRegistryAndWait removes one ApicurioRegistry deployment and ensures it's deleted waiting
@@ -176,46 +219,28 @@ func DeleteRegistryAndWait(suiteCtx *types.SuiteContext, namespace string, regis
 	err = suiteCtx.K8sClient.Delete(context.TODO(), obj)
 	Expect(err).ToNot(HaveOccurred())
 
-	timeout := 15 * time.Second
-	log.Info("Waiting for registry CR to be removed", "timeout", timeout)
-	err = wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		existing := apicurio.ApicurioRegistry{}
-		err := suiteCtx.K8sClient.Get(context.TODO(),
-			kubetypes.NamespacedName{Name: registryName, Namespace: namespace},
-			&existing)
-		if err != nil {
-			if errors.IsNotFound(err) {
-				return true, nil
-			}
-			return false, err
-		}
-		return false, nil
-	})
+	record, err := suiteCtx.Waiter.Timeout(timeouts.Get(timeouts.RegistryCRRemoval)).Describe("registry CR to be removed").
+		ForObject(kubetypes.NamespacedName{Name: registryName, Namespace: namespace}, &apicurio.ApicurioRegistry{}, waiter.Deleted)
+	waiter.LogOnError(record, err)
 	kubernetescli.Execute("get", "apicurioregistry", "-n", namespace)
 	Expect(err).ToNot(HaveOccurred())
 
 	err = waitRegistryDeploymentDeleted(suiteCtx, namespace, registryName)
-
+	kubernetescli.Execute("get", "deployment", "-n", namespace)
+	Expect(err).ToNot(HaveOccurred())
 }
 
 func waitRegistryDeploymentDeleted(suiteCtx *types.SuiteContext, namespace string, registryName string) error {
-	timeout := 30 * time.Second
-	log.Info("Waiting for registry deployment to be removed", "timeout", timeout)
-	return wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		labelsSet := labels.Set(map[string]string{"app": registryName})
-
-		deployments, err := suiteCtx.Clientset.AppsV1().Deployments(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: labelsSet.AsSelector().String()})
-		if err != nil {
-			if errors.IsNotFound(err) {
-				return true, nil
-			}
-			return false, err
-		}
-		if len(deployments.Items) == 0 {
-			return true, nil
-		}
-		return false, nil
-	})
+	record, err := suiteCtx.Waiter.Timeout(timeouts.Get(timeouts.RegistryDeploymentRemoval)).Describe("registry deployment to be removed").
+		ForList(&appsv1.DeploymentList{}, waiter.Empty, client.InNamespace(namespace), client.MatchingLabels{"app": registryName})
+	waiter.LogOnError(record, err)
+	return err
+}
+
+//podSummary phase and readiness of a pod, statuses of pods are too verbose to be recorded
+func podSummary(obj runtime.Object) string {
+	pod := obj.(*corev1.Pod)
+	return fmt.Sprintf("%v ready=%v", pod.Status.Phase, kubernetesutils.IsPodReady(pod))
 }
 
 //ExistsRegistry verifies if the ApicurioRegistry CR named registryName exists
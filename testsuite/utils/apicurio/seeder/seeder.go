@@ -0,0 +1,351 @@
+package seeder
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	apicurioclient "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/client"
+)
+
+var log = logf.Log.WithName("seeder")
+
+const defaultWorkers int = 4
+const defaultMaxVersions int = 3
+
+//Options describe the dataset to generate, the same options always produce the same dataset
+type Options struct {
+	//Seed of the random generator used to build the dataset
+	Seed int64
+	//Prefix of every group and artifact id, so datasets of different tests don't collide
+	Prefix string
+	//Groups number of groups the artifacts are spread across, defaults to 1
+	Groups int
+	//Artifacts total number of artifacts
+	Artifacts int
+	//ArtifactTypes types to choose from, defaults to every type supported by the registry
+	ArtifactTypes []apicurioclient.ArtifactType
+	//MaxVersions each artifact gets between 1 and MaxVersions versions, defaults to 3
+	MaxVersions int
+	//Workers number of concurrent writers, defaults to 4
+	Workers int
+}
+
+//Manifest description of a generated dataset, the ids assigned by the registry are filled once the dataset is written
+type Manifest struct {
+	Seed      int64
+	Artifacts []*ArtifactManifest
+}
+
+//ArtifactManifest one artifact of the dataset, with its metadata as it's set on the latest version
+type ArtifactManifest struct {
+	GroupId     string
+	ArtifactId  string
+	Type        apicurioclient.ArtifactType
+	Name        string
+	Description string
+	Labels      []string
+	Properties  map[string]string
+	Rules       map[apicurioclient.RuleType]string
+	Versions    []*VersionManifest
+}
+
+//VersionManifest one version of an artifact, in creation order
+type VersionManifest struct {
+	Version   string
+	State     apicurioclient.ArtifactState
+	Content   string
+	GlobalId  int64
+	ContentId int64
+}
+
+func (o Options) withDefaults() Options {
+	if o.Groups <= 0 {
+		o.Groups = 1
+	}
+	if len(o.ArtifactTypes) == 0 {
+		o.ArtifactTypes = apicurioclient.ArtifactTypes
+	}
+	if o.MaxVersions <= 0 {
+		o.MaxVersions = defaultMaxVersions
+	}
+	if o.Workers <= 0 {
+		o.Workers = defaultWorkers
+	}
+	return o
+}
+
+//Generate builds the dataset described by the options without writing anything to a registry
+func Generate(opts Options) (*Manifest, error) {
+	opts = opts.withDefaults()
+	random := rand.New(rand.NewSource(opts.Seed))
+
+	manifest := &Manifest{Seed: opts.Seed}
+	for i := 0; i < opts.Artifacts; i++ {
+		artifactType := opts.ArtifactTypes[random.Intn(len(opts.ArtifactTypes))]
+		artifact := &ArtifactManifest{
+			GroupId:     fmt.Sprintf("%v-group-%v", opts.Prefix, i%opts.Groups),
+			ArtifactId:  fmt.Sprintf("%v-%v", opts.Prefix, i),
+			Type:        artifactType,
+			Name:        fmt.Sprintf("%v artifact %v", opts.Prefix, i),
+			Description: fmt.Sprintf("%v artifact generated with seed %v", artifactType, opts.Seed),
+			Labels:      []string{opts.Prefix, strings.ToLower(string(artifactType))},
+			Properties:  map[string]string{"seed": strconv.FormatInt(opts.Seed, 10), "index": strconv.Itoa(i)},
+			Rules:       map[apicurioclient.RuleType]string{},
+		}
+
+		if random.Intn(2) == 0 {
+			artifact.Rules[apicurioclient.ValidityRule] = "SYNTAX_ONLY"
+		}
+		//generated avro versions only add fields with default values
+		if artifactType == apicurioclient.Avro && random.Intn(2) == 0 {
+			artifact.Rules[apicurioclient.CompatibilityRule] = "BACKWARD"
+		}
+
+		versions := 1 + random.Intn(opts.MaxVersions)
+		for v := 1; v <= versions; v++ {
+			content, err := versionContent(artifactType, i, v)
+			if err != nil {
+				return nil, err
+			}
+			version := &VersionManifest{Version: strconv.Itoa(v), State: apicurioclient.Enabled, Content: content}
+			if v < versions && random.Intn(2) == 0 {
+				version.State = apicurioclient.Deprecated
+			}
+			artifact.Versions = append(artifact.Versions, version)
+		}
+
+		manifest.Artifacts = append(manifest.Artifacts, artifact)
+	}
+	return manifest, nil
+}
+
+//Seed generates the dataset described by the options and writes it to the registry using concurrent workers
+func Seed(client apicurioclient.ApicurioRegistryApiClient, opts Options) (*Manifest, error) {
+	opts = opts.withDefaults()
+	manifest, err := Generate(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Seeding registry", "seed", opts.Seed, "artifacts", len(manifest.Artifacts), "workers", opts.Workers)
+	err = forEachArtifact(manifest, opts.Workers, func(a *ArtifactManifest) error {
+		return writeArtifact(client, a)
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Registry seeded", "seed", opts.Seed, "artifacts", len(manifest.Artifacts), "versions", manifest.VersionsCount())
+	return manifest, nil
+}
+
+func writeArtifact(client apicurioclient.ApicurioRegistryApiClient, a *ArtifactManifest) error {
+	first := a.Versions[0]
+	metadata, err := client.CreateArtifactInGroup(&apicurioclient.CreateArtifactRequest{
+		GroupId:      a.GroupId,
+		ArtifactId:   a.ArtifactId,
+		ArtifactType: a.Type,
+		Version:      first.Version,
+		Content:      first.Content,
+	})
+	if err != nil {
+		return err
+	}
+	first.GlobalId = metadata.GlobalId
+	first.ContentId = metadata.ContentId
+
+	for _, ruleType := range a.ruleTypes() {
+		err = client.CreateArtifactRule(a.GroupId, a.ArtifactId, &apicurioclient.Rule{Type: ruleType, Config: a.Rules[ruleType]})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, v := range a.Versions[1:] {
+		metadata, err := client.CreateArtifactVersion(&apicurioclient.CreateVersionRequest{
+			GroupId:      a.GroupId,
+			ArtifactId:   a.ArtifactId,
+			ArtifactType: a.Type,
+			Version:      v.Version,
+			Content:      v.Content,
+		})
+		if err != nil {
+			return err
+		}
+		v.GlobalId = metadata.GlobalId
+		v.ContentId = metadata.ContentId
+	}
+
+	//metadata is kept per version, so it's set once the latest version exists
+	err = client.UpdateArtifactMetaData(a.GroupId, a.ArtifactId, &apicurioclient.EditableMetaData{
+		Name:        a.Name,
+		Description: a.Description,
+		Labels:      a.Labels,
+		Properties:  a.Properties,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, v := range a.Versions {
+		if v.State == apicurioclient.Enabled {
+			continue
+		}
+		err = client.UpdateArtifactVersionState(a.GroupId, a.ArtifactId, v.Version, v.State)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//forEachArtifact runs fn for every artifact of the manifest using the given number of workers, returning every failure
+func forEachArtifact(manifest *Manifest, workers int, fn func(a *ArtifactManifest) error) error {
+	artifacts := make(chan *ArtifactManifest)
+	var mu sync.Mutex
+	failures := make([]string, 0)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for a := range artifacts {
+				if err := fn(a); err != nil {
+					mu.Lock()
+					failures = append(failures, fmt.Sprintf("%v/%v: %v", a.GroupId, a.ArtifactId, err))
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, a := range manifest.Artifacts {
+		artifacts <- a
+	}
+	close(artifacts)
+	wg.Wait()
+
+	if len(failures) != 0 {
+		sort.Strings(failures)
+		return fmt.Errorf("%v artifacts failed: %v", len(failures), strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+//Groups ids of every group of the dataset, sorted
+func (m *Manifest) Groups() []string {
+	groups := map[string]bool{}
+	for _, a := range m.Artifacts {
+		groups[a.GroupId] = true
+	}
+	list := make([]string, 0, len(groups))
+	for g := range groups {
+		list = append(list, g)
+	}
+	sort.Strings(list)
+	return list
+}
+
+//ArtifactsInGroup artifacts of the dataset in the given group
+func (m *Manifest) ArtifactsInGroup(groupId string) []*ArtifactManifest {
+	artifacts := make([]*ArtifactManifest, 0)
+	for _, a := range m.Artifacts {
+		if a.GroupId == groupId {
+			artifacts = append(artifacts, a)
+		}
+	}
+	return artifacts
+}
+
+//VersionsCount total number of versions of the dataset
+func (m *Manifest) VersionsCount() int {
+	count := 0
+	for _, a := range m.Artifacts {
+		count += len(a.Versions)
+	}
+	return count
+}
+
+//Latest the latest version of the artifact
+func (a *ArtifactManifest) Latest() *VersionManifest {
+	return a.Versions[len(a.Versions)-1]
+}
+
+func (a *ArtifactManifest) ruleTypes() []apicurioclient.RuleType {
+	ruleTypes := make([]apicurioclient.RuleType, 0, len(a.Rules))
+	for t := range a.Rules {
+		ruleTypes = append(ruleTypes, t)
+	}
+	sort.Slice(ruleTypes, func(i, j int) bool { return ruleTypes[i] < ruleTypes[j] })
+	return ruleTypes
+}
+
+//versionContent content of one version of an artifact, unique per artifact and version and valid for the artifact type
+func versionContent(artifactType apicurioclient.ArtifactType, artifact int, version int) (string, error) {
+	marker := fmt.Sprintf("seeded artifact %v version %v", artifact, version)
+	switch artifactType {
+	case apicurioclient.Avro:
+		return avroSchema(artifact, version)
+	case apicurioclient.Protobuf:
+		return sampleContentWithComment(artifactType, "// "+marker+"\n")
+	case apicurioclient.GraphQL:
+		return sampleContentWithComment(artifactType, "# "+marker+"\n")
+	case apicurioclient.WSDL, apicurioclient.XSD, apicurioclient.XML:
+		return sampleContentWithComment(artifactType, "<!-- "+marker+" -->\n")
+	default:
+		//json based types, unknown extension properties are allowed by all of them
+		sample, err := apicurioclient.SampleContent(artifactType)
+		if err != nil {
+			return "", err
+		}
+		document := map[string]interface{}{}
+		err = json.Unmarshal([]byte(sample), &document)
+		if err != nil {
+			return "", err
+		}
+		document["x-seed"] = marker
+		data, err := json.MarshalIndent(document, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
+
+func sampleContentWithComment(artifactType apicurioclient.ArtifactType, comment string) (string, error) {
+	sample, err := apicurioclient.SampleContent(artifactType)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasSuffix(sample, "\n") {
+		sample += "\n"
+	}
+	return sample + comment, nil
+}
+
+//avroSchema every version adds one field with a default value, so versions are backward compatible
+func avroSchema(artifact int, version int) (string, error) {
+	fields := []map[string]interface{}{
+		{"name": "id", "type": "string"},
+	}
+	for f := 1; f < version; f++ {
+		fields = append(fields, map[string]interface{}{"name": "field" + strconv.Itoa(f), "type": "string", "default": ""})
+	}
+	schema := map[string]interface{}{
+		"type":      "record",
+		"name":      "Seeded" + strconv.Itoa(artifact),
+		"namespace": "io.apicurio.tests.seed",
+		"fields":    fields,
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,96 @@
+package seeder
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	apicurioclient "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/client"
+)
+
+var testOptions = Options{Seed: 42, Prefix: "unit", Groups: 3, Artifacts: 40, Workers: 8}
+
+func TestGeneratesTheSameDatasetForTheSameSeed(t *testing.T) {
+	g := NewWithT(t)
+	first, err := Generate(testOptions)
+	g.Expect(err).ToNot(HaveOccurred())
+	second, err := Generate(testOptions)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(second).To(Equal(first))
+
+	other := testOptions
+	other.Seed = 43
+	third, err := Generate(other)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(third).ToNot(Equal(first))
+}
+
+func TestSpreadsArtifactsAcrossGroupsTypesAndVersions(t *testing.T) {
+	g := NewWithT(t)
+	manifest, err := Generate(testOptions)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(manifest.Artifacts).To(HaveLen(40))
+	g.Expect(manifest.Groups()).To(Equal([]string{"unit-group-0", "unit-group-1", "unit-group-2"}))
+
+	types := map[apicurioclient.ArtifactType]bool{}
+	for _, a := range manifest.Artifacts {
+		types[a.Type] = true
+		g.Expect(len(a.Versions)).To(BeNumerically("<=", defaultMaxVersions))
+		g.Expect(a.Latest().State).To(Equal(apicurioclient.Enabled))
+		contents := map[string]bool{}
+		for _, v := range a.Versions {
+			contents[v.Content] = true
+			if a.Type != apicurioclient.Protobuf && a.Type != apicurioclient.GraphQL && a.Type != apicurioclient.WSDL && a.Type != apicurioclient.XSD && a.Type != apicurioclient.XML {
+				g.Expect(json.Valid([]byte(v.Content))).To(BeTrue(), "invalid content for %v", a.Type)
+			}
+		}
+		g.Expect(contents).To(HaveLen(len(a.Versions)))
+	}
+	g.Expect(len(types)).To(BeNumerically(">", 1))
+	g.Expect(manifest.VersionsCount()).To(BeNumerically(">", len(manifest.Artifacts)))
+}
+
+func TestWritesTheDatasetConcurrentlyAndVerifiesIt(t *testing.T) {
+	g := NewWithT(t)
+	registry := apicurioclient.NewFakeRegistry()
+	defer registry.Close()
+
+	manifest, err := Seed(registry.Client(), testOptions)
+	g.Expect(err).ToNot(HaveOccurred())
+	for _, a := range manifest.Artifacts {
+		for _, v := range a.Versions {
+			g.Expect(v.GlobalId).ToNot(BeZero())
+		}
+	}
+	g.Expect(manifest.Verify(registry.Client())).To(Succeed())
+}
+
+func TestReportsDifferencesWithTheManifest(t *testing.T) {
+	g := NewWithT(t)
+	registry := apicurioclient.NewFakeRegistry()
+	defer registry.Close()
+	manifest, err := Seed(registry.Client(), testOptions)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	tampered := manifest.Artifacts[5]
+	err = registry.Client().UpdateArtifactMetaData(tampered.GroupId, tampered.ArtifactId, &apicurioclient.EditableMetaData{Name: "tampered"})
+	g.Expect(err).ToNot(HaveOccurred())
+	err = manifest.Verify(registry.Client())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring(tampered.ArtifactId))
+
+	g.Expect(registry.Client().DeleteArtifactInGroup(tampered.GroupId, tampered.ArtifactId)).To(Succeed())
+	g.Expect(manifest.Verify(registry.Client())).To(MatchError(ContainSubstring("expected")))
+}
+
+func TestReportsEveryFailedArtifact(t *testing.T) {
+	g := NewWithT(t)
+	registry := apicurioclient.NewFakeRegistry()
+	defer registry.Close()
+	_, err := Seed(registry.Client(), testOptions)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = Seed(registry.Client(), testOptions)
+	g.Expect(err).To(MatchError(ContainSubstring("40 artifacts failed")))
+}
@@ -0,0 +1,100 @@
+package seeder
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	apicurioclient "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/client"
+)
+
+//Verify checks the registry holds every artifact of the manifest, with the same metadata, rules, versions, states, ids and content
+func (m *Manifest) Verify(client apicurioclient.ApicurioRegistryApiClient) error {
+	for _, groupId := range m.Groups() {
+		artifacts, err := client.ListArtifactsInGroup(groupId)
+		if err != nil {
+			return err
+		}
+		expected := len(m.ArtifactsInGroup(groupId))
+		if len(artifacts) != expected {
+			return fmt.Errorf("group %v: expected %v artifacts but found %v", groupId, expected, len(artifacts))
+		}
+	}
+
+	return forEachArtifact(m, defaultWorkers, func(a *ArtifactManifest) error {
+		return verifyArtifact(client, a)
+	})
+}
+
+func verifyArtifact(client apicurioclient.ApicurioRegistryApiClient, a *ArtifactManifest) error {
+	metadata, err := client.GetArtifactMetaData(a.GroupId, a.ArtifactId)
+	if err != nil {
+		return err
+	}
+	if metadata.Type != a.Type {
+		return fmt.Errorf("expected type %v but found %v", a.Type, metadata.Type)
+	}
+	if metadata.Name != a.Name || metadata.Description != a.Description {
+		return fmt.Errorf("expected name %q and description %q but found %q and %q", a.Name, a.Description, metadata.Name, metadata.Description)
+	}
+	if !sameStrings(metadata.Labels, a.Labels) {
+		return fmt.Errorf("expected labels %v but found %v", a.Labels, metadata.Labels)
+	}
+	if !reflect.DeepEqual(metadata.Properties, a.Properties) {
+		return fmt.Errorf("expected properties %v but found %v", a.Properties, metadata.Properties)
+	}
+
+	ruleTypes, err := client.ListArtifactRules(a.GroupId, a.ArtifactId)
+	if err != nil {
+		return err
+	}
+	if len(ruleTypes) != len(a.Rules) {
+		return fmt.Errorf("expected rules %v but found %v", a.ruleTypes(), ruleTypes)
+	}
+	for _, ruleType := range ruleTypes {
+		rule, err := client.GetArtifactRule(a.GroupId, a.ArtifactId, ruleType)
+		if err != nil {
+			return err
+		}
+		if config, exists := a.Rules[ruleType]; !exists || config != rule.Config {
+			return fmt.Errorf("expected rule %v with config %q but found %q", ruleType, a.Rules[ruleType], rule.Config)
+		}
+	}
+
+	versions, err := client.ListArtifactVersions(a.GroupId, a.ArtifactId)
+	if err != nil {
+		return err
+	}
+	if len(versions) != len(a.Versions) {
+		return fmt.Errorf("expected %v versions but found %v", len(a.Versions), len(versions))
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].GlobalId < versions[j].GlobalId })
+	for i, expected := range a.Versions {
+		found := versions[i]
+		if found.Version != expected.Version || found.State != expected.State {
+			return fmt.Errorf("expected version %v in state %v but found version %v in state %v", expected.Version, expected.State, found.Version, found.State)
+		}
+		if expected.GlobalId != 0 && (found.GlobalId != expected.GlobalId || found.ContentId != expected.ContentId) {
+			return fmt.Errorf("version %v: expected globalId %v and contentId %v but found %v and %v", expected.Version, expected.GlobalId, expected.ContentId, found.GlobalId, found.ContentId)
+		}
+		content, err := client.ReadContentByGlobalId(found.GlobalId)
+		if err != nil {
+			return err
+		}
+		if content != expected.Content {
+			return fmt.Errorf("version %v: content differs from the seeded one", expected.Version)
+		}
+	}
+	return nil
+}
+
+func sameStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA := append([]string{}, a...)
+	sortedB := append([]string{}, b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	return reflect.DeepEqual(sortedA, sortedB)
+}
@@ -0,0 +1,137 @@
+package snapshot
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	apicurioclient "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/client"
+)
+
+//Diff lists every difference between the expected and the actual snapshot, one entry per missing, unexpected or changed item, sorted.
+//An empty result means both registries hold the same data
+func Diff(expected *Snapshot, actual *Snapshot) []string {
+	d := &differences{}
+
+	expectedGroups := toSet(expected.Groups)
+	actualGroups := toSet(actual.Groups)
+	for _, g := range expected.Groups {
+		if !actualGroups[g] {
+			d.add("group %v: missing", g)
+		}
+	}
+	for _, g := range actual.Groups {
+		if !expectedGroups[g] {
+			d.add("group %v: unexpected", g)
+		}
+	}
+
+	diffRules(d, "global rules", expected.GlobalRules, actual.GlobalRules)
+
+	for k, e := range expected.Artifacts {
+		a, exists := actual.Artifacts[k]
+		if !exists {
+			d.add("artifact %v: missing", k)
+			continue
+		}
+		diffArtifact(d, "artifact "+k, e, a)
+	}
+	for k := range actual.Artifacts {
+		if _, exists := expected.Artifacts[k]; !exists {
+			d.add("artifact %v: unexpected", k)
+		}
+	}
+
+	sort.Strings(d.list)
+	return d.list
+}
+
+type differences struct {
+	list []string
+}
+
+func (d *differences) add(format string, args ...interface{}) {
+	d.list = append(d.list, fmt.Sprintf(format, args...))
+}
+
+//changed records a difference if the values are not equal
+func (d *differences) changed(path string, field string, expected interface{}, actual interface{}) {
+	if !reflect.DeepEqual(expected, actual) {
+		d.add("%v: %v expected %v but found %v", path, field, expected, actual)
+	}
+}
+
+func diffArtifact(d *differences, path string, expected *ArtifactSnapshot, actual *ArtifactSnapshot) {
+	d.changed(path, "type", expected.Type, actual.Type)
+	d.changed(path, "name", expected.Name, actual.Name)
+	d.changed(path, "description", expected.Description, actual.Description)
+	d.changed(path, "labels", expected.Labels, actual.Labels)
+	d.changed(path, "properties", expected.Properties, actual.Properties)
+	diffRules(d, path+" rules", expected.Rules, actual.Rules)
+
+	sameVersions := true
+	for _, e := range expected.Versions {
+		a := actual.Version(e.Version)
+		if a == nil {
+			d.add("%v version %v: missing", path, e.Version)
+			sameVersions = false
+			continue
+		}
+		diffVersion(d, fmt.Sprintf("%v version %v", path, e.Version), e, a)
+	}
+	for _, a := range actual.Versions {
+		if expected.Version(a.Version) == nil {
+			d.add("%v version %v: unexpected", path, a.Version)
+			sameVersions = false
+		}
+	}
+	//versions are sorted by globalId, a different order means versions were recreated
+	if sameVersions {
+		d.changed(path, "versions order", versionIds(expected), versionIds(actual))
+	}
+}
+
+func diffVersion(d *differences, path string, expected *VersionSnapshot, actual *VersionSnapshot) {
+	d.changed(path, "state", expected.State, actual.State)
+	d.changed(path, "name", expected.Name, actual.Name)
+	d.changed(path, "description", expected.Description, actual.Description)
+	d.changed(path, "labels", expected.Labels, actual.Labels)
+	d.changed(path, "properties", expected.Properties, actual.Properties)
+	d.changed(path, "globalId", expected.GlobalId, actual.GlobalId)
+	d.changed(path, "contentId", expected.ContentId, actual.ContentId)
+	if expected.ContentHash != actual.ContentHash {
+		d.add("%v: content changed", path)
+	}
+}
+
+func diffRules(d *differences, path string, expected map[apicurioclient.RuleType]string, actual map[apicurioclient.RuleType]string) {
+	for ruleType, config := range expected {
+		found, exists := actual[ruleType]
+		if !exists {
+			d.add("%v: rule %v missing", path, ruleType)
+		} else if found != config {
+			d.add("%v: rule %v expected %v but found %v", path, ruleType, config, found)
+		}
+	}
+	for ruleType := range actual {
+		if _, exists := expected[ruleType]; !exists {
+			d.add("%v: rule %v unexpected", path, ruleType)
+		}
+	}
+}
+
+func versionIds(a *ArtifactSnapshot) []string {
+	ids := make([]string, 0, len(a.Versions))
+	for _, v := range a.Versions {
+		ids = append(ids, v.Version)
+	}
+	return ids
+}
+
+func toSet(values []string) map[string]bool {
+	set := map[string]bool{}
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
@@ -0,0 +1,204 @@
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	apicurioclient "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/client"
+)
+
+var log = logf.Log.WithName("snapshot")
+
+//Snapshot every piece of data stored in a registry that is expected to survive upgrades, restores and migrations.
+//Server generated timestamps and authors are left out, so snapshots of different registries can be compared
+type Snapshot struct {
+	Groups      []string                           `json:"groups"`
+	GlobalRules map[apicurioclient.RuleType]string `json:"globalRules,omitempty"`
+	//Artifacts indexed by groupId/artifactId
+	Artifacts map[string]*ArtifactSnapshot `json:"artifacts"`
+}
+
+//ArtifactSnapshot one artifact, with the metadata of its latest version
+type ArtifactSnapshot struct {
+	GroupId     string                             `json:"groupId"`
+	ArtifactId  string                             `json:"artifactId"`
+	Type        apicurioclient.ArtifactType        `json:"type"`
+	Name        string                             `json:"name,omitempty"`
+	Description string                             `json:"description,omitempty"`
+	Labels      []string                           `json:"labels,omitempty"`
+	Properties  map[string]string                  `json:"properties,omitempty"`
+	Rules       map[apicurioclient.RuleType]string `json:"rules,omitempty"`
+	//Versions in creation order
+	Versions []*VersionSnapshot `json:"versions"`
+}
+
+//VersionSnapshot one version of an artifact, content is kept as a sha256 hash
+type VersionSnapshot struct {
+	Version     string                       `json:"version"`
+	State       apicurioclient.ArtifactState `json:"state"`
+	Name        string                       `json:"name,omitempty"`
+	Description string                       `json:"description,omitempty"`
+	Labels      []string                     `json:"labels,omitempty"`
+	Properties  map[string]string            `json:"properties,omitempty"`
+	GlobalId    int64                        `json:"globalId"`
+	ContentId   int64                        `json:"contentId"`
+	ContentHash string                       `json:"contentHash"`
+}
+
+//Take walks the registry through its api and records every group, artifact, version, content, metadata, state and rule
+func Take(client apicurioclient.ApicurioRegistryApiClient) (*Snapshot, error) {
+	globalRules, err := readRules(client.ListGlobalRules, client.GetGlobalRule)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := client.ListGroups()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(groups)
+
+	snapshot := &Snapshot{
+		Groups:      groups,
+		GlobalRules: globalRules,
+		Artifacts:   map[string]*ArtifactSnapshot{},
+	}
+	for _, groupId := range groups {
+		artifacts, err := client.ListArtifactsInGroup(groupId)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range artifacts {
+			artifact, err := takeArtifact(client, groupId, a.Id)
+			if err != nil {
+				return nil, err
+			}
+			snapshot.Artifacts[key(groupId, a.Id)] = artifact
+		}
+	}
+	log.Info("Registry snapshot taken", "groups", len(snapshot.Groups), "artifacts", len(snapshot.Artifacts), "versions", snapshot.VersionsCount())
+	return snapshot, nil
+}
+
+func takeArtifact(client apicurioclient.ApicurioRegistryApiClient, groupId string, artifactId string) (*ArtifactSnapshot, error) {
+	metadata, err := client.GetArtifactMetaData(groupId, artifactId)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := readRules(
+		func() ([]apicurioclient.RuleType, error) { return client.ListArtifactRules(groupId, artifactId) },
+		func(ruleType apicurioclient.RuleType) (*apicurioclient.Rule, error) {
+			return client.GetArtifactRule(groupId, artifactId, ruleType)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	artifact := &ArtifactSnapshot{
+		GroupId:     groupId,
+		ArtifactId:  artifactId,
+		Type:        metadata.Type,
+		Name:        metadata.Name,
+		Description: metadata.Description,
+		Labels:      sortedLabels(metadata.Labels),
+		Properties:  nonEmpty(metadata.Properties),
+		Rules:       rules,
+	}
+
+	versions, err := client.ListArtifactVersions(groupId, artifactId)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].GlobalId < versions[j].GlobalId })
+	for _, v := range versions {
+		content, err := client.ReadContentByGlobalId(v.GlobalId)
+		if err != nil {
+			return nil, err
+		}
+		artifact.Versions = append(artifact.Versions, &VersionSnapshot{
+			Version:     v.Version,
+			State:       v.State,
+			Name:        v.Name,
+			Description: v.Description,
+			Labels:      sortedLabels(v.Labels),
+			Properties:  nonEmpty(v.Properties),
+			GlobalId:    v.GlobalId,
+			ContentId:   v.ContentId,
+			ContentHash: ContentHash(content),
+		})
+	}
+	return artifact, nil
+}
+
+func readRules(list func() ([]apicurioclient.RuleType, error), get func(apicurioclient.RuleType) (*apicurioclient.Rule, error)) (map[apicurioclient.RuleType]string, error) {
+	ruleTypes, err := list()
+	if err != nil {
+		return nil, err
+	}
+	if len(ruleTypes) == 0 {
+		return nil, nil
+	}
+	rules := map[apicurioclient.RuleType]string{}
+	for _, ruleType := range ruleTypes {
+		rule, err := get(ruleType)
+		if err != nil {
+			return nil, err
+		}
+		rules[ruleType] = rule.Config
+	}
+	return rules, nil
+}
+
+//ContentHash hash used to compare artifact contents
+func ContentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+//Artifact the snapshot of one artifact, nil if the registry didn't hold it
+func (s *Snapshot) Artifact(groupId string, artifactId string) *ArtifactSnapshot {
+	return s.Artifacts[key(groupId, artifactId)]
+}
+
+//VersionsCount total number of versions in the snapshot
+func (s *Snapshot) VersionsCount() int {
+	count := 0
+	for _, a := range s.Artifacts {
+		count += len(a.Versions)
+	}
+	return count
+}
+
+//Version the snapshot of one version of the artifact, nil if the artifact doesn't have it
+func (a *ArtifactSnapshot) Version(version string) *VersionSnapshot {
+	for _, v := range a.Versions {
+		if v.Version == version {
+			return v
+		}
+	}
+	return nil
+}
+
+func key(groupId string, artifactId string) string {
+	return groupId + "/" + artifactId
+}
+
+//sortedLabels labels are a set, the order the registry returns them in is not meaningful
+func sortedLabels(labels []string) []string {
+	if len(labels) == 0 {
+		return nil
+	}
+	sorted := append([]string{}, labels...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+//nonEmpty the registry may return either no properties or an empty object, both mean the same
+func nonEmpty(properties map[string]string) map[string]string {
+	if len(properties) == 0 {
+		return nil
+	}
+	return properties
+}
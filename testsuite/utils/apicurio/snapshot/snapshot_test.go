@@ -0,0 +1,123 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	apicurioclient "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/client"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/seeder"
+)
+
+//newSeededClient starts a fake registry, closed when the test ends, seeded with artifacts and a global rule
+func newSeededClient(t *testing.T) (apicurioclient.ApicurioRegistryApiClient, *seeder.Manifest) {
+	g := NewWithT(t)
+	registry := apicurioclient.NewFakeRegistry()
+	t.Cleanup(registry.Close)
+	client := registry.Client()
+	manifest, err := seeder.Seed(client, seeder.Options{Seed: 7, Prefix: "snap", Groups: 2, Artifacts: 10})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(client.CreateGlobalRule(&apicurioclient.Rule{Type: apicurioclient.ValidityRule, Config: "FULL"})).To(Succeed())
+	return client, manifest
+}
+
+func take(g *WithT, client apicurioclient.ApicurioRegistryApiClient) *Snapshot {
+	s, err := Take(client)
+	g.Expect(err).ToNot(HaveOccurred())
+	return s
+}
+
+func TestRecordsEveryGroupArtifactVersionAndRule(t *testing.T) {
+	g := NewWithT(t)
+	client, manifest := newSeededClient(t)
+	s := take(g, client)
+	g.Expect(s.Groups).To(Equal(manifest.Groups()))
+	g.Expect(s.GlobalRules).To(Equal(map[apicurioclient.RuleType]string{apicurioclient.ValidityRule: "FULL"}))
+	g.Expect(s.Artifacts).To(HaveLen(len(manifest.Artifacts)))
+	g.Expect(s.VersionsCount()).To(Equal(manifest.VersionsCount()))
+
+	for _, a := range manifest.Artifacts {
+		artifact := s.Artifact(a.GroupId, a.ArtifactId)
+		g.Expect(artifact).ToNot(BeNil())
+		g.Expect(artifact.Type).To(Equal(a.Type))
+		g.Expect(artifact.Name).To(Equal(a.Name))
+		g.Expect(artifact.Properties).To(Equal(a.Properties))
+		g.Expect(artifact.Rules).To(HaveLen(len(a.Rules)))
+		g.Expect(artifact.Versions).To(HaveLen(len(a.Versions)))
+		for i, v := range a.Versions {
+			g.Expect(artifact.Versions[i].Version).To(Equal(v.Version))
+			g.Expect(artifact.Versions[i].State).To(Equal(v.State))
+			g.Expect(artifact.Versions[i].GlobalId).To(Equal(v.GlobalId))
+			g.Expect(artifact.Versions[i].ContentHash).To(Equal(ContentHash(v.Content)))
+		}
+	}
+}
+
+func TestIsAComparableDocument(t *testing.T) {
+	g := NewWithT(t)
+	client, _ := newSeededClient(t)
+	data, err := json.Marshal(take(g, client))
+	g.Expect(err).ToNot(HaveOccurred())
+	decoded := &Snapshot{}
+	g.Expect(json.Unmarshal(data, decoded)).To(Succeed())
+	g.Expect(Diff(take(g, client), decoded)).To(BeEmpty())
+}
+
+func TestFindsNoDifferencesInAnUnchangedRegistry(t *testing.T) {
+	g := NewWithT(t)
+	client, _ := newSeededClient(t)
+	g.Expect(Diff(take(g, client), take(g, client))).To(BeEmpty())
+}
+
+func TestListsExactlyWhatIsMissingOrChanged(t *testing.T) {
+	g := NewWithT(t)
+	client, manifest := newSeededClient(t)
+	before := take(g, client)
+
+	deleted := manifest.Artifacts[0]
+	g.Expect(client.DeleteArtifactInGroup(deleted.GroupId, deleted.ArtifactId)).To(Succeed())
+
+	changed := manifest.Artifacts[1]
+	latest := changed.Latest()
+	err := client.UpdateArtifactVersionState(changed.GroupId, changed.ArtifactId, latest.Version, apicurioclient.Disabled)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(client.DeleteGlobalRule(apicurioclient.ValidityRule)).To(Succeed())
+	g.Expect(client.CreateGlobalRule(&apicurioclient.Rule{Type: apicurioclient.CompatibilityRule, Config: "BACKWARD"})).To(Succeed())
+
+	g.Expect(Diff(before, take(g, client))).To(ConsistOf(
+		"artifact "+deleted.GroupId+"/"+deleted.ArtifactId+": missing",
+		"artifact "+changed.GroupId+"/"+changed.ArtifactId+" version "+latest.Version+": state expected ENABLED but found DISABLED",
+		"global rules: rule COMPATIBILITY unexpected",
+		"global rules: rule VALIDITY missing",
+	))
+}
+
+func TestReportsUnexpectedArtifactsAndChangedMetadata(t *testing.T) {
+	g := NewWithT(t)
+	client, manifest := newSeededClient(t)
+	before := take(g, client)
+
+	_, err := client.CreateArtifactInGroup(&apicurioclient.CreateArtifactRequest{
+		GroupId:      "snap-group-0",
+		ArtifactId:   "extra",
+		ArtifactType: apicurioclient.Avro,
+		Content:      `{"type":"string"}`,
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	a := manifest.Artifacts[2]
+	err = client.UpdateArtifactMetaData(a.GroupId, a.ArtifactId, &apicurioclient.EditableMetaData{
+		Name:        "renamed",
+		Description: a.Description,
+		Labels:      a.Labels,
+		Properties:  a.Properties,
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(Diff(before, take(g, client))).To(ConsistOf(
+		"artifact snap-group-0/extra: unexpected",
+		"artifact "+a.GroupId+"/"+a.ArtifactId+": name expected "+a.Name+" but found renamed",
+	))
+}
@@ -0,0 +1,250 @@
+package apicurio
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"sort"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	routev1 "github.com/openshift/api/route/v1"
+
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetescli"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/timeouts"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/waiter"
+	apicurio "github.com/Apicurio/apicurio-registry-operator/api/v1"
+)
+
+//ocpIngressCANamespace and ocpIngressCAConfigMap hold the CA of the default certificate of the openshift router
+const (
+	ocpIngressCANamespace string = "openshift-config-managed"
+	ocpIngressCAConfigMap string = "default-ingress-cert"
+	ocpIngressCAKey       string = "ca-bundle.crt"
+)
+
+//configureKubernetesTLS exposes the registry with https, the ingress of the registry gets a certificate for its host
+//signed by a CA created for the test, which is added to the CA bundle of the test context
+func configureKubernetesTLS(suiteCtx *types.SuiteContext, ctx *types.TestContext, registry *apicurio.ApicurioRegistry) {
+	log.Info("Configuring registry TLS", "host", ctx.RegistryHost)
+
+	caPEM, certPEM, keyPEM, err := selfSignedCertificate(ctx.RegistryHost)
+	Expect(err).ToNot(HaveOccurred())
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      registry.Name + "-tls",
+			Namespace: registry.Namespace,
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+			"ca.crt":                caPEM,
+		},
+	}
+	err = suiteCtx.K8sClient.Create(context.TODO(), secret)
+	Expect(err).ToNot(HaveOccurred())
+	ctx.RegisterCleanup(func() {
+		log.Info("Removing registry TLS secret", "name", secret.Name)
+		err := suiteCtx.K8sClient.Delete(context.TODO(), secret)
+		Expect(client.IgnoreNotFound(err)).ToNot(HaveOccurred())
+	})
+
+	setIngressTLS(suiteCtx, registry, ctx.RegistryHost, secret.Name)
+
+	ctx.RegistryTLS.CABundle = append(ctx.RegistryTLS.CABundle, caPEM...)
+
+	waitForTLSEndpoint(ctx)
+}
+
+//configureOpenshiftTLS exposes the registry with an edge terminated route serving the default certificate of the router,
+//the host is the one openshift assigned to the route of the registry
+func configureOpenshiftTLS(suiteCtx *types.SuiteContext, ctx *types.TestContext, registry *apicurio.ApicurioRegistry, host string) {
+	log.Info("Configuring registry TLS", "host", host)
+
+	setIngressTLS(suiteCtx, registry, host, "")
+
+	timeout := timeouts.Get(timeouts.RegistryRoute)
+	log.Info("Waiting for registry route to terminate TLS", "timeout", timeout)
+	record, err := suiteCtx.Waiter.Timeout(timeout).Describe("registry route to terminate TLS").
+		ForList(&routev1.RouteList{}, func(list client.ObjectList) (bool, error) {
+			routes := list.(*routev1.RouteList)
+			return len(routes.Items) != 0 && routes.Items[0].Spec.TLS != nil, nil
+		}, client.InNamespace(registry.Namespace), client.MatchingLabels(labels.Set{"app": registry.Name}))
+	waiter.LogOnError(record, err)
+	kubernetescli.Execute("get", "route", "-n", registry.Namespace)
+	Expect(err).ToNot(HaveOccurred())
+}
+
+//setIngressTLS adds the host to the TLS hosts of the ingress of the registry. An empty secret means the default certificate of the ingress controller.
+//The same patch removes a label the operator sets on the ingress, once the operator restores it the TLS configuration has to be kept through that reconcile
+func setIngressTLS(suiteCtx *types.SuiteContext, registry *apicurio.ApicurioRegistry, host string, secretName string) {
+	ingresses := &networking.IngressList{}
+	err := suiteCtx.K8sClient.List(context.TODO(), ingresses, client.InNamespace(registry.Namespace), client.MatchingLabels{"app": registry.Name})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(len(ingresses.Items)).To(BeIdenticalTo(1))
+
+	ingress := &ingresses.Items[0]
+	label := reconciledLabel(ingress)
+	Expect(label).ToNot(BeEmpty(), "ingress %v has no labels but the app one", ingress.Name)
+	tls := []networking.IngressTLS{{Hosts: []string{host}, SecretName: secretName}}
+	original := ingress.ResourceVersion
+	patch := client.MergeFrom(ingress.DeepCopy())
+	ingress.Spec.TLS = tls
+	delete(ingress.Labels, label)
+	err = suiteCtx.K8sClient.Patch(context.TODO(), ingress, patch)
+	Expect(err).ToNot(HaveOccurred())
+
+	//the cache may still hold the ingress as it was before or right after the patch, only later versions count
+	patched := ingress.ResourceVersion
+	current := &networking.Ingress{}
+	record, err := suiteCtx.Waiter.Timeout(timeouts.Get(timeouts.OperatorConvergence)).Describe("operator to restore label "+label+" of ingress "+ingress.Name).
+		ForObject(client.ObjectKeyFromObject(ingress), current, func(obj client.Object, exists bool) (bool, error) {
+			if !exists || obj.GetResourceVersion() == original || obj.GetResourceVersion() == patched {
+				return false, nil
+			}
+			_, restored := obj.GetLabels()[label]
+			return restored, nil
+		})
+	waiter.LogOnError(record, err)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = suiteCtx.K8sClient.Get(context.TODO(), client.ObjectKeyFromObject(ingress), current)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(current.Spec.TLS).To(Equal(tls), "operator didn't keep the TLS configuration of ingress %v", ingress.Name)
+}
+
+//reconciledLabel a label of the ingress the operator restores if it's removed, other than the app label the ingress is found with
+func reconciledLabel(ingress *networking.Ingress) string {
+	keys := []string{}
+	for k := range ingress.Labels {
+		if k != "app" {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Strings(keys)
+	return keys[0]
+}
+
+//routeCABundle CA the route certificate is signed by, the CA of the route itself or the CA of the router default certificate
+func routeCABundle(suiteCtx *types.SuiteContext, route *routev1.Route) []byte {
+	if route.Spec.TLS.CACertificate != "" {
+		return []byte(route.Spec.TLS.CACertificate)
+	}
+	log.Info("Registry route has no CA certificate, trusting the cluster ingress CA")
+	cm, err := suiteCtx.Clientset.CoreV1().ConfigMaps(ocpIngressCANamespace).Get(context.TODO(), ocpIngressCAConfigMap, metav1.GetOptions{})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(cm.Data[ocpIngressCAKey]).ToNot(BeEmpty())
+	return []byte(cm.Data[ocpIngressCAKey])
+}
+
+//waitForTLSEndpoint waits for the ingress controller to serve the registry certificate,
+//until it reloads its configuration the handshake fails with its fake certificate
+func waitForTLSEndpoint(ctx *types.TestContext) {
+	endpoint := ctx.RegistryEndpoint()
+	httpClient, err := endpoint.HTTPClient()
+	Expect(err).ToNot(HaveOccurred())
+
+	timeout := timeouts.Get(timeouts.RegistryTLS)
+	log.Info("Waiting for registry TLS endpoint", "url", endpoint.URL(), "timeout", timeout)
+	var lastErr error
+	err = wait.Poll(timeouts.Interval(timeouts.APIPollInterval), timeout, func() (bool, error) {
+		res, err := httpClient.Get(endpoint.URL())
+		if err != nil {
+			lastErr = err
+			return false, nil
+		}
+		res.Body.Close()
+		return true, nil
+	})
+	if err != nil {
+		log.Info("Registry TLS endpoint not ready", "error", lastErr)
+	}
+	Expect(err).ToNot(HaveOccurred())
+}
+
+//selfSignedCertificate creates a CA and a certificate for the host signed by it, all of them PEM encoded
+func selfSignedCertificate(host string) ([]byte, []byte, []byte, error) {
+	if host == "" {
+		return nil, nil, nil, errors.New("no host to create a certificate for")
+	}
+	notBefore := time.Now().Add(-time.Hour)
+	notAfter := notBefore.Add(24 * time.Hour)
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "apicurio-registry-e2e-ca"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: host},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	} else {
+		template.DNSNames = []string{host}
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		return nil, nil, nil, err
+	}
+	return caPEM, certPEM, keyPEM, nil
+}
@@ -0,0 +1,49 @@
+package apicurio
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSelfSignedCertificateSignsTheCertificateOfTheHostWithTheReturnedCA(t *testing.T) {
+	g := NewWithT(t)
+	caPEM, certPEM, keyPEM, err := selfSignedCertificate("registry.127.0.0.1.nip.io")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = tls.X509KeyPair(certPEM, keyPEM)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	pool := x509.NewCertPool()
+	g.Expect(pool.AppendCertsFromPEM(caPEM)).To(BeTrue())
+	block, _ := pem.Decode(certPEM)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = cert.Verify(x509.VerifyOptions{DNSName: "registry.127.0.0.1.nip.io", Roots: pool})
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = cert.Verify(x509.VerifyOptions{DNSName: "other.127.0.0.1.nip.io", Roots: pool})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestSelfSignedCertificateNeedsAHost(t *testing.T) {
+	g := NewWithT(t)
+	_, _, _, err := selfSignedCertificate("")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestReconciledLabelIsNotTheAppOne(t *testing.T) {
+	g := NewWithT(t)
+	ingress := &networking.Ingress{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+		"app": "registry", "apicur.io/type": "apicurio-registry", "apicur.io/name": "registry",
+	}}}
+	g.Expect(reconciledLabel(ingress)).To(Equal("apicur.io/name"))
+	ingress.Labels = map[string]string{"app": "registry"}
+	g.Expect(reconciledLabel(ingress)).To(BeEmpty())
+}
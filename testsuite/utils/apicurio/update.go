@@ -0,0 +1,319 @@
+package apicurio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetescli"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/timeouts"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/waiter"
+	apicurio "github.com/Apicurio/apicurio-registry-operator/api/v1"
+)
+
+//DeploymentDiff registry deployment before and after an update, along with the changes between them
+type DeploymentDiff struct {
+	Before  *appsv1.Deployment
+	After   *appsv1.Deployment
+	Changes []string
+}
+
+//UpdateRegistryAndWait patches the spec of the registry under test with the changes made by mutate and waits for the operator to apply them.
+//When expectRollout is set the registry deployment has to roll out new pods, changes like the host don't touch the deployment.
+//Returns the diff of the registry deployment
+func UpdateRegistryAndWait(suiteCtx *types.SuiteContext, ctx *types.TestContext, expectRollout bool, mutate func(spec *apicurio.ApicurioRegistrySpec)) *DeploymentDiff {
+	key := kubetypes.NamespacedName{Name: ctx.RegistryName, Namespace: ctx.RegistryNamespace}
+	registry := &apicurio.ApicurioRegistry{}
+	err := suiteCtx.K8sClient.Get(context.TODO(), key, registry)
+	Expect(err).ToNot(HaveOccurred())
+
+	before, err := findRegistryDeployment(suiteCtx, registry)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(before).ToNot(BeNil(), "registry %v has no deployment", ctx.RegistryName)
+
+	updated := registry.DeepCopy()
+	mutate(&updated.Spec)
+	log.Info("Updating registry", "name", ctx.RegistryName)
+	err = suiteCtx.K8sClient.Patch(context.TODO(), updated, client.MergeFrom(registry))
+	Expect(err).ToNot(HaveOccurred())
+	Expect(updated.Generation).To(BeNumerically(">", registry.Generation), "registry spec not changed by the update")
+
+	replicas := updated.Spec.Deployment.Replicas
+	if replicas == 0 {
+		replicas = 1
+	}
+
+	if expectRollout {
+		waitForRollout(suiteCtx, before, replicas)
+	}
+
+	WaitForRegistryReady(suiteCtx, ctx.RegistryNamespace, ctx.RegistryName, replicas)
+
+	after, err := findRegistryDeployment(suiteCtx, updated)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(after).ToNot(BeNil())
+
+	current := &apicurio.ApicurioRegistry{}
+	err = suiteCtx.K8sClient.Get(context.TODO(), key, current)
+	Expect(err).ToNot(HaveOccurred())
+	ctx.RegistryResource = current
+
+	diff := DiffDeployments(before, after)
+	log.Info("Registry updated", "name", ctx.RegistryName, "deploymentChanges", diff.Changes)
+	return diff
+}
+
+//SetRegistryEnvAndWait sets an env var of the registry container the registry spec has no field for and waits for the rollout.
+//Newer operators take it from spec.configuration.env, older ones don't know the field, the api server prunes it,
+//and the env var is set in the deployment instead, those operators keep the env vars of the deployment they don't manage
+func SetRegistryEnvAndWait(suiteCtx *types.SuiteContext, ctx *types.TestContext, name string, value string) {
+	key := kubetypes.NamespacedName{Name: ctx.RegistryName, Namespace: ctx.RegistryNamespace}
+	registry := &apicurio.ApicurioRegistry{}
+	err := suiteCtx.K8sClient.Get(context.TODO(), key, registry)
+	Expect(err).ToNot(HaveOccurred())
+
+	before, err := findRegistryDeployment(suiteCtx, registry)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(before).ToNot(BeNil(), "registry %v has no deployment", ctx.RegistryName)
+
+	inSpec, err := setRegistrySpecEnv(suiteCtx, key, name, value)
+	Expect(err).ToNot(HaveOccurred())
+	patchedGeneration := int64(0)
+	if !inSpec {
+		log.Info("Operator doesn't support env vars in the registry spec, setting it in the deployment", "name", ctx.RegistryName, "env", name)
+		updated := before.DeepCopy()
+		container := RegistryContainer(&updated.Spec.Template.Spec, registry.Name)
+		Expect(container).ToNot(BeNil(), "deployment %v has no registry container", before.Name)
+		container.Env = setEnv(container.Env, name, value)
+		err = suiteCtx.K8sClient.Patch(context.TODO(), updated, client.MergeFrom(before))
+		Expect(err).ToNot(HaveOccurred())
+		patchedGeneration = updated.Generation
+	}
+
+	replicas := ctx.RegistryReplicas()
+	waitForRollout(suiteCtx, before, replicas)
+	WaitForRegistryReady(suiteCtx, ctx.RegistryNamespace, ctx.RegistryName, replicas)
+
+	//the operator reconciles on every change of the deployment it owns, the rollout ones included, so by now it had the chance to revert the env var
+	after, err := findRegistryDeployment(suiteCtx, registry)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(after).ToNot(BeNil(), "registry %v has no deployment", ctx.RegistryName)
+	container := RegistryContainer(&after.Spec.Template.Spec, registry.Name)
+	Expect(container).ToNot(BeNil(), "deployment %v has no registry container", after.Name)
+	Expect(envMap(container.Env)).To(HaveKeyWithValue(name, value), "operator reverted env var %v of deployment %v", name, after.Name)
+	if patchedGeneration != 0 {
+		Expect(after.Generation).To(Equal(patchedGeneration), "operator changed deployment %v after the env var was set", after.Name)
+	}
+}
+
+//setRegistrySpecEnv sets the env var in spec.configuration.env of the registry, false if the api server pruned the field
+func setRegistrySpecEnv(suiteCtx *types.SuiteContext, key kubetypes.NamespacedName, name string, value string) (bool, error) {
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(apicurio.GroupVersion.WithKind("ApicurioRegistry"))
+	if err := suiteCtx.K8sClient.Get(context.TODO(), key, current); err != nil {
+		return false, err
+	}
+	env, _, err := unstructured.NestedSlice(current.Object, EnvField...)
+	if err != nil {
+		return false, err
+	}
+	env, err = setUnstructuredEnv(env, name, value)
+	if err != nil {
+		return false, err
+	}
+	requested := current.DeepCopy()
+	if err := unstructured.SetNestedSlice(requested.Object, env, EnvField...); err != nil {
+		return false, err
+	}
+	updated := requested.DeepCopy()
+	log.Info("Setting registry env var", "name", key.Name, "env", name, "value", value)
+	if err := suiteCtx.K8sClient.Patch(context.TODO(), updated, client.MergeFrom(current)); err != nil {
+		return false, err
+	}
+	return len(prunedFields(requested, updated, EnvField)) == 0, nil
+}
+
+//setUnstructuredEnv setEnv for the env vars of unstructured content
+func setUnstructuredEnv(env []interface{}, name string, value string) ([]interface{}, error) {
+	vars := []corev1.EnvVar{}
+	for _, e := range env {
+		v := corev1.EnvVar{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(e.(map[string]interface{}), &v); err != nil {
+			return nil, err
+		}
+		vars = append(vars, v)
+	}
+	return toUnstructuredList(setEnv(vars, name, value))
+}
+
+//setEnv replaces the value of the env var, or adds it if it's not set
+func setEnv(env []corev1.EnvVar, name string, value string) []corev1.EnvVar {
+	for i := range env {
+		if env[i].Name == name {
+			env[i] = corev1.EnvVar{Name: name, Value: value}
+			return env
+		}
+	}
+	return append(env, corev1.EnvVar{Name: name, Value: value})
+}
+
+//waitForRollout waits for the deployment to roll out the pod template changed after before was read
+func waitForRollout(suiteCtx *types.SuiteContext, before *appsv1.Deployment, replicas int32) {
+	deploymentKey := client.ObjectKey{Namespace: before.Namespace, Name: before.Name}
+	record, err := suiteCtx.Waiter.Timeout(timeouts.Get(timeouts.RegistryRollout)).Describe("rollout of deployment "+before.Name).Summarize(rolloutSummary).
+		ForObject(deploymentKey, &appsv1.Deployment{}, func(obj client.Object, exists bool) (bool, error) {
+			if !exists {
+				return false, nil
+			}
+			return rolledOut(obj.(*appsv1.Deployment), before.Generation, replicas), nil
+		})
+	waiter.LogOnError(record, err)
+	kubernetescli.GetPods(before.Namespace)
+	Expect(err).ToNot(HaveOccurred())
+}
+
+//rolledOut true once the deployment controller observed a generation newer than the one before the update and every replica runs the latest pod template
+func rolledOut(deployment *appsv1.Deployment, previousGeneration int64, replicas int32) bool {
+	status := deployment.Status
+	return deployment.Generation > previousGeneration &&
+		status.ObservedGeneration >= deployment.Generation &&
+		status.UpdatedReplicas == replicas &&
+		status.Replicas == replicas &&
+		status.AvailableReplicas == replicas
+}
+
+func rolloutSummary(obj runtime.Object) string {
+	d := obj.(*appsv1.Deployment)
+	return fmt.Sprintf("generation=%v observed=%v replicas=%v updated=%v available=%v", d.Generation, d.Status.ObservedGeneration, d.Status.Replicas, d.Status.UpdatedReplicas, d.Status.AvailableReplicas)
+}
+
+//DiffDeployments changes of the replicas and the pod template of two versions of a deployment, sorted
+func DiffDeployments(before *appsv1.Deployment, after *appsv1.Deployment) *DeploymentDiff {
+	changes := []string{}
+	change := func(field string, was string, is string) {
+		if was != is {
+			changes = append(changes, fmt.Sprintf("%v: %v -> %v", field, orNone(was), orNone(is)))
+		}
+	}
+
+	change("replicas", replicasString(before.Spec.Replicas), replicasString(after.Spec.Replicas))
+	beforePod := before.Spec.Template
+	afterPod := after.Spec.Template
+	diffMaps("pod label", beforePod.Labels, afterPod.Labels, change)
+	diffMaps("pod annotation", beforePod.Annotations, afterPod.Annotations, change)
+	change("affinity", toJSON(beforePod.Spec.Affinity), toJSON(afterPod.Spec.Affinity))
+	change("tolerations", toJSON(beforePod.Spec.Tolerations), toJSON(afterPod.Spec.Tolerations))
+	change("volumes", toJSON(beforePod.Spec.Volumes), toJSON(afterPod.Spec.Volumes))
+
+	beforeContainers := containersByName(beforePod.Spec.Containers)
+	afterContainers := containersByName(afterPod.Spec.Containers)
+	for _, name := range unionKeys(beforeContainers, afterContainers) {
+		b, inBefore := beforeContainers[name]
+		a, inAfter := afterContainers[name]
+		prefix := "container " + name
+		if !inBefore || !inAfter {
+			change(prefix, fmt.Sprint(inBefore), fmt.Sprint(inAfter))
+			continue
+		}
+		change(prefix+" image", b.Image, a.Image)
+		diffMaps(prefix+" env", envMap(b.Env), envMap(a.Env), change)
+		change(prefix+" resources", toJSON(b.Resources), toJSON(a.Resources))
+		change(prefix+" volume mounts", toJSON(b.VolumeMounts), toJSON(a.VolumeMounts))
+	}
+	sort.Strings(changes)
+	return &DeploymentDiff{Before: before, After: after, Changes: changes}
+}
+
+func diffMaps(field string, before map[string]string, after map[string]string, change func(field string, was string, is string)) {
+	keys := map[string]bool{}
+	for k := range before {
+		keys[k] = true
+	}
+	for k := range after {
+		keys[k] = true
+	}
+	for k := range keys {
+		change(field+" "+k, before[k], after[k])
+	}
+}
+
+//EnvValue value of the env var of the first container of the deployment, valueFrom references are returned as json
+func EnvValue(deployment *appsv1.Deployment, name string) (string, bool) {
+	if len(deployment.Spec.Template.Spec.Containers) == 0 {
+		return "", false
+	}
+	value, exists := envMap(deployment.Spec.Template.Spec.Containers[0].Env)[name]
+	return value, exists
+}
+
+func envMap(env []corev1.EnvVar) map[string]string {
+	values := map[string]string{}
+	for _, e := range env {
+		if e.ValueFrom != nil {
+			values[e.Name] = "valueFrom:" + toJSON(e.ValueFrom)
+		} else {
+			values[e.Name] = e.Value
+		}
+	}
+	return values
+}
+
+func containersByName(containers []corev1.Container) map[string]corev1.Container {
+	byName := map[string]corev1.Container{}
+	for _, c := range containers {
+		byName[c.Name] = c
+	}
+	return byName
+}
+
+func unionKeys(a map[string]corev1.Container, b map[string]corev1.Container) []string {
+	keys := []string{}
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, exists := a[k]; !exists {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func replicasString(replicas *int32) string {
+	if replicas == nil {
+		return ""
+	}
+	return fmt.Sprint(*replicas)
+}
+
+//toJSON empty for nil and empty values, so they compare as equal
+func toJSON(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	switch string(data) {
+	case "null", "{}", "[]":
+		return ""
+	}
+	return string(data)
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
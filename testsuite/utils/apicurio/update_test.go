@@ -0,0 +1,108 @@
+package apicurio
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testDeployment(replicas int32) *appsv1.Deployment {
+	d := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "registry-deployment", Generation: 3}}
+	d.Spec.Replicas = &replicas
+	d.Spec.Template.Labels = map[string]string{"app": "registry"}
+	d.Spec.Template.Spec.Containers = []corev1.Container{{
+		Name:  "registry",
+		Image: "quay.io/apicurio/apicurio-registry-sql:2.0.1.Final",
+		Env: []corev1.EnvVar{
+			{Name: "LOG_LEVEL", Value: "INFO"},
+			{Name: "REGISTRY_DATASOURCE_PASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}, Key: "password"}}},
+		},
+	}}
+	return d
+}
+
+func TestDiffDeploymentsIsEmptyForIdenticalDeployments(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(DiffDeployments(testDeployment(1), testDeployment(1)).Changes).To(BeEmpty())
+}
+
+func TestDiffDeploymentsReportsReplicasEnvImageResourcesAndPodTemplateChanges(t *testing.T) {
+	g := NewWithT(t)
+	before := testDeployment(1)
+	after := testDeployment(2)
+	container := &after.Spec.Template.Spec.Containers[0]
+	container.Image = "quay.io/apicurio/apicurio-registry-sql:2.0.2.Final"
+	container.Env[0].Value = "DEBUG"
+	container.Env = append(container.Env, corev1.EnvVar{Name: "REGISTRY_UI_FEATURES_READONLY", Value: "true"})
+	container.Resources.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")}
+	after.Spec.Template.Annotations = map[string]string{"team": "registry"}
+	after.Spec.Template.Spec.Tolerations = []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}}
+
+	g.Expect(DiffDeployments(before, after).Changes).To(Equal([]string{
+		"container registry env LOG_LEVEL: INFO -> DEBUG",
+		"container registry env REGISTRY_UI_FEATURES_READONLY: (none) -> true",
+		"container registry image: quay.io/apicurio/apicurio-registry-sql:2.0.1.Final -> quay.io/apicurio/apicurio-registry-sql:2.0.2.Final",
+		`container registry resources: (none) -> {"limits":{"memory":"1Gi"}}`,
+		"pod annotation team: (none) -> registry",
+		"replicas: 1 -> 2",
+		`tolerations: (none) -> [{"key":"dedicated","operator":"Exists"}]`,
+	}))
+}
+
+func TestDiffDeploymentsReportsAddedAndRemovedContainersAndEnvVarsFromReferences(t *testing.T) {
+	g := NewWithT(t)
+	before := testDeployment(1)
+	after := testDeployment(1)
+	after.Spec.Template.Spec.Containers[0].Env = after.Spec.Template.Spec.Containers[0].Env[:1]
+	after.Spec.Template.Spec.Containers = append(after.Spec.Template.Spec.Containers, corev1.Container{Name: "sidecar"})
+
+	g.Expect(DiffDeployments(before, after).Changes).To(Equal([]string{
+		`container registry env REGISTRY_DATASOURCE_PASSWORD: valueFrom:{"secretKeyRef":{"name":"db","key":"password"}} -> (none)`,
+		"container sidecar: false -> true",
+	}))
+	value, exists := EnvValue(after, "LOG_LEVEL")
+	g.Expect(exists).To(BeTrue())
+	g.Expect(value).To(Equal("INFO"))
+}
+
+func TestRolledOutWaitsForEveryReplicaToRunTheNewTemplate(t *testing.T) {
+	g := NewWithT(t)
+	d := testDeployment(2)
+	d.Generation = 4
+	d.Status = appsv1.DeploymentStatus{ObservedGeneration: 4, Replicas: 3, UpdatedReplicas: 2, AvailableReplicas: 2}
+	g.Expect(rolledOut(d, 3, 2)).To(BeFalse())
+
+	d.Status.Replicas = 2
+	g.Expect(rolledOut(d, 3, 2)).To(BeTrue())
+	g.Expect(rolledOut(d, 4, 2)).To(BeFalse())
+
+	d.Status.ObservedGeneration = 3
+	g.Expect(rolledOut(d, 3, 2)).To(BeFalse())
+}
+
+func TestSetEnvReplacesValuesOrReferences(t *testing.T) {
+	g := NewWithT(t)
+	env := []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "INFO"}, {Name: "PASSWORD", ValueFrom: &corev1.EnvVarSource{}}}
+	env = setEnv(env, "PASSWORD", "secret")
+	env = setEnv(env, "ROLE_BASED_AUTHZ_ENABLED", "true")
+	g.Expect(env).To(Equal([]corev1.EnvVar{
+		{Name: "LOG_LEVEL", Value: "INFO"},
+		{Name: "PASSWORD", Value: "secret"},
+		{Name: "ROLE_BASED_AUTHZ_ENABLED", Value: "true"},
+	}))
+}
+
+func TestSetUnstructuredEnvKeepsTheOtherEnvVarsOfTheRegistrySpec(t *testing.T) {
+	g := NewWithT(t)
+	env, err := setUnstructuredEnv([]interface{}{map[string]interface{}{"name": "LOG_LEVEL", "value": "INFO"}}, "ROLE_BASED_AUTHZ_ENABLED", "true")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(env).To(Equal([]interface{}{
+		map[string]interface{}{"name": "LOG_LEVEL", "value": "INFO"},
+		map[string]interface{}{"name": "ROLE_BASED_AUTHZ_ENABLED", "value": "true"},
+	}))
+}
@@ -2,7 +2,6 @@ package utils
 
 import (
 	"os"
-	"time"
 )
 
 //constants to be used in testsuite
@@ -38,9 +37,6 @@ const (
 
 	OperatorNamespace     = "apicurio-registry-e2e"
 	OperatorVersionEnvVar = "E2E_OPERATOR_VERSION"
-	APIPollInterval       = 2 * time.Second
-	MediumPollInterval    = 5 * time.Second
-	LongPollInterval      = 10 * time.Second
 
 	StorageSql      = "sql"
 	StorageKafkaSql = "kafkasql"
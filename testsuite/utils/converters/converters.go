@@ -24,12 +24,13 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils"
-	apicurioclient "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/client"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/functional"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kafkasql"
 	kubernetesutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetes"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetescli"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/openshift"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/sql"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/timeouts"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
 )
 
@@ -65,18 +66,18 @@ func ConvertersTestCase(suiteCtx *types.SuiteContext, testContext *types.TestCon
 	var kafkaClusterInfo *types.KafkaClusterInfo = kafkasql.DeployKafkaClusterV2(suiteCtx, testContext.RegistryNamespace, 1, true, kafkaClusterName, []string{})
 	if kafkaClusterInfo.StrimziDeployed {
 		strimziCleanup := func() {
-			kafkasql.RemoveStrimziOperator(suiteCtx.Clientset, testContext.RegistryNamespace)
+			kafkasql.RemoveStrimziOperator(suiteCtx, testContext.RegistryNamespace)
 		}
 		testContext.RegisterCleanup(strimziCleanup)
 	}
 	kafkaCleanup := func() {
-		kafkasql.RemoveKafkaCluster(suiteCtx.Clientset, testContext.RegistryNamespace, kafkaClusterInfo)
+		kafkasql.RemoveKafkaCluster(suiteCtx, testContext.RegistryNamespace, kafkaClusterInfo)
 	}
 	testContext.RegisterCleanup(kafkaCleanup)
 
 	sql.DeployDebeziumPostgresqlDatabase(suiteCtx, testContext.RegistryNamespace, databaseName, databaseName, databaseUser, databasePassword)
 	postgresCleanup := func() {
-		sql.RemovePostgresqlDatabase(suiteCtx.K8sClient, suiteCtx.Clientset, testContext.RegistryNamespace, databaseName)
+		sql.RemovePostgresqlDatabase(suiteCtx, testContext.RegistryNamespace, databaseName)
 	}
 	testContext.RegisterCleanup(postgresCleanup)
 
@@ -154,7 +155,7 @@ func ConvertersTestCase(suiteCtx *types.SuiteContext, testContext *types.TestCon
 	Expect(records[0].Key[0]).To(Equal(byte(0)))
 	Expect(records[0].Value[0]).To(Equal(byte(0)))
 
-	apicurio := apicurioclient.NewApicurioRegistryApiClient(testContext.RegistryHost, testContext.RegistryPort, http.DefaultClient)
+	apicurio := functional.RegistryClient(testContext, nil)
 	artifacts, err := apicurio.ListArtifacts()
 	Expect(err).ToNot(HaveOccurred())
 	log.Info("Artifacts after debezium are " + strings.Join(artifacts, ", "))
@@ -179,9 +180,9 @@ func readKafkaMessages(minimumExpectedRecords int, bootstrapServers string, topi
 	})
 
 	var records []*kafka.Message = make([]*kafka.Message, 0)
-	timeout := 60 * time.Second
+	timeout := timeouts.Get(timeouts.KafkaConsumer)
 	log.Info("Waiting for kafka consumer to receive at least "+strconv.Itoa(minimumExpectedRecords)+" records", "timeout", timeout)
-	err := wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
+	err := wait.Poll(timeouts.Interval(timeouts.APIPollInterval), timeout, func() (bool, error) {
 		timeout, cf := context.WithTimeout(context.Background(), 10*time.Second)
 		m, err := r.ReadMessage(timeout)
 		cf()
@@ -256,8 +257,8 @@ func createDebeziumJdbcConnector(debeziumURL string, connectorName string, conve
 	Expect(res.StatusCode >= 200 && res.StatusCode <= 299).To(BeTrue())
 
 	log.Info("Waiting for debezium connector to be configured")
-	timeout := 45 * time.Second
-	err = wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
+	timeout := timeouts.Get(timeouts.DebeziumConnector)
+	err = wait.Poll(timeouts.Interval(timeouts.APIPollInterval), timeout, func() (bool, error) {
 		res, err := http.Get(debeziumURL + "/connectors/" + connectorName)
 		if err != nil {
 			return false, err
@@ -301,16 +302,16 @@ func deployDebezium(suiteCtx *types.SuiteContext, testContext *types.TestContext
 	}
 	testContext.RegisterCleanup(debeziumCleanup)
 
-	kubernetesutils.WaitForDeploymentReady(suiteCtx.Clientset, 120*time.Second, testContext.RegistryNamespace, debeziumName, 1)
+	kubernetesutils.WaitForDeploymentReady(suiteCtx, timeouts.Get(timeouts.DebeziumReady), testContext.RegistryNamespace, debeziumName, 1)
 }
 
 func verifyDebeziumIngress(debeziumURL string) {
 
 	log.Info("Testing debezium ingress")
-	timeout := 60 * time.Second
+	timeout := timeouts.Get(timeouts.DebeziumIngress)
 	statusCode := ""
 	body := ""
-	err := wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
+	err := wait.Poll(timeouts.Interval(timeouts.APIPollInterval), timeout, func() (bool, error) {
 		res, err := http.Get(debeziumURL + "/connectors")
 		if err != nil {
 			return false, err
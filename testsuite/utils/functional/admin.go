@@ -0,0 +1,79 @@
+package functional
+
+import (
+	"net/http"
+	"strconv"
+
+	. "github.com/onsi/gomega"
+
+	apicurioclient "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/client"
+	types "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+)
+
+const adminTestLogger string = "io.apicurio"
+
+//adminTestProperty boolean config property available in every registry version with dynamic configuration support
+const adminTestProperty string = "registry.ccompat.legacy-id-mode.enabled"
+
+//AdminTestCase verifies loggers and dynamic config properties can be managed at runtime through the admin API
+func AdminTestCase(ctx *types.TestContext) {
+
+	client := RegistryClient(ctx, nil)
+
+	log.Info("Testing runtime logger configuration")
+	defer client.RemoveLogger(adminTestLogger)
+	logger, err := client.SetLogLevel(adminTestLogger, apicurioclient.LogLevelDebug)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(logger.Name).To(Equal(adminTestLogger))
+	Expect(logger.Level).To(Equal(apicurioclient.LogLevelDebug))
+
+	logger, err = client.GetLogger(adminTestLogger)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(logger.Level).To(Equal(apicurioclient.LogLevelDebug))
+	loggers, err := client.ListLoggers()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(loggers).To(ContainElement(apicurioclient.NamedLogConfiguration{Name: adminTestLogger, Level: apicurioclient.LogLevelDebug}))
+
+	_, err = client.RemoveLogger(adminTestLogger)
+	Expect(err).ToNot(HaveOccurred())
+	loggers, err = client.ListLoggers()
+	Expect(err).ToNot(HaveOccurred())
+	for _, l := range loggers {
+		Expect(l.Name).ToNot(Equal(adminTestLogger))
+	}
+
+	log.Info("Testing dynamic config properties")
+	properties, err := client.ListConfigProperties()
+	if apicurioclient.IsNotFound(err) {
+		log.Info("Registry does not support dynamic config properties, skipping")
+		return
+	}
+	Expect(err).ToNot(HaveOccurred())
+	var property *apicurioclient.ConfigProperty
+	for i := range properties {
+		if properties[i].Name == adminTestProperty {
+			property = &properties[i]
+		}
+	}
+	Expect(property).ToNot(BeNil(), "property %v not found", adminTestProperty)
+
+	original, err := strconv.ParseBool(property.Value)
+	Expect(err).ToNot(HaveOccurred())
+	defer client.ResetConfigProperty(adminTestProperty)
+	err = client.UpdateConfigProperty(adminTestProperty, strconv.FormatBool(!original))
+	Expect(err).ToNot(HaveOccurred())
+	property, err = client.GetConfigProperty(adminTestProperty)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(property.Value).To(Equal(strconv.FormatBool(!original)))
+
+	err = client.ResetConfigProperty(adminTestProperty)
+	Expect(err).ToNot(HaveOccurred())
+	property, err = client.GetConfigProperty(adminTestProperty)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(property.Value).To(Equal(strconv.FormatBool(original)))
+
+	_, err = client.GetConfigProperty("registry.unknown.property")
+	expectRegistryError(err, http.StatusNotFound, "ConfigPropertyNotFoundException")
+
+	log.Info("Successful registry admin API verification")
+}
@@ -0,0 +1,59 @@
+package functional
+
+import (
+	"strings"
+
+	. "github.com/onsi/gomega"
+
+	apicurioclient "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/client"
+	types "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+)
+
+const artifactTypesTestGroup string = "artifact-types-tests"
+
+//ArtifactTypesTestCase creates one artifact of every supported type and verifies the registry stores it as it was sent
+func ArtifactTypesTestCase(ctx *types.TestContext) {
+
+	client := RegistryClient(ctx, nil)
+
+	defer func() {
+		log.Info("Cleaning artifact types test data")
+		client.DeleteArtifactsInGroup(artifactTypesTestGroup)
+	}()
+
+	for _, artifactType := range apicurioclient.ArtifactTypes {
+		log.Info("Testing artifact type", "type", artifactType)
+		createSampleArtifact(client, artifactTypesTestGroup, "sample-"+strings.ToLower(string(artifactType)), artifactType)
+	}
+
+	artifacts, err := client.ListArtifactsInGroup(artifactTypesTestGroup)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(len(artifacts)).To(BeIdenticalTo(len(apicurioclient.ArtifactTypes)))
+
+	log.Info("Successful artifact types verification")
+}
+
+//createSampleArtifact creates an artifact with the sample content of the type and verifies the registry stores that content,
+//by global id and as the latest version of the artifact
+func createSampleArtifact(client apicurioclient.ApicurioRegistryApiClient, groupId string, artifactId string, artifactType apicurioclient.ArtifactType) (*apicurioclient.ArtifactMetaData, string) {
+	content, err := apicurioclient.SampleContent(artifactType)
+	Expect(err).ToNot(HaveOccurred())
+
+	metadata, err := client.CreateArtifactInGroup(&apicurioclient.CreateArtifactRequest{
+		GroupId:      groupId,
+		ArtifactId:   artifactId,
+		ArtifactType: artifactType,
+		Content:      content,
+	})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(metadata.Type).To(Equal(artifactType))
+
+	stored, err := client.ReadContentByGlobalId(metadata.GlobalId)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(stored).To(Equal(content))
+	latest, err := client.ReadLatestArtifact(groupId, artifactId)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(latest).To(Equal(content))
+
+	return metadata, content
+}
@@ -0,0 +1,119 @@
+package functional
+
+import (
+	"net/http"
+	"strconv"
+
+	. "github.com/onsi/gomega"
+
+	apicurioclient "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/client"
+	types "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+)
+
+const ccompatTestSubject string = "ccompat-price-value"
+
+//CCompatClient returns a confluent compatible api client for the registry under test, auth can be nil for unsecured registries
+func CCompatClient(ctx *types.TestContext, auth apicurioclient.Authenticator) apicurioclient.CCompatApiClient {
+	client, err := apicurioclient.NewCCompatApiClientForEndpoint(ctx.RegistryEndpoint(), auth)
+	Expect(err).NotTo(HaveOccurred())
+	return client
+}
+
+//CCompatTestCase verifies the confluent schema registry compatible api, as used by confluent serializers
+func CCompatTestCase(ctx *types.TestContext) {
+
+	client := CCompatClient(ctx, nil)
+
+	defer func() {
+		log.Info("Cleaning ccompat test data")
+		client.DeleteSubjectCompatibility(ccompatTestSubject)
+		client.DeleteSubject(ccompatTestSubject)
+	}()
+
+	log.Info("Testing ccompat schema registration")
+	id, err := client.RegisterSchema(ccompatTestSubject, &apicurioclient.SchemaRequest{Schema: priceSchema})
+	Expect(err).ToNot(HaveOccurred())
+	sameId, err := client.RegisterSchema(ccompatTestSubject, &apicurioclient.SchemaRequest{Schema: priceSchema})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(sameId).To(Equal(id))
+
+	subjects, err := client.ListSubjects()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(subjects).To(ContainElement(ccompatTestSubject))
+
+	schema, err := client.GetSchemaById(id)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(schema.Schema).To(MatchJSON(priceSchema))
+
+	found, err := client.LookupSchema(ccompatTestSubject, &apicurioclient.SchemaRequest{Schema: priceSchema})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(found.Subject).To(Equal(ccompatTestSubject))
+	Expect(found.Id).To(Equal(id))
+	Expect(found.Version).To(Equal(1))
+
+	//subjects are artifacts of the default group in the registry api
+	metadata, err := RegistryClient(ctx, nil).GetArtifactMetaData(apicurioclient.DefaultGroup, ccompatTestSubject)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(metadata.Type).To(Equal(apicurioclient.Avro))
+
+	log.Info("Testing ccompat compatibility checks")
+	err = client.UpdateSubjectCompatibility(ccompatTestSubject, apicurioclient.CompatibilityBackward)
+	Expect(err).ToNot(HaveOccurred())
+	level, err := client.GetSubjectCompatibility(ccompatTestSubject)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(level).To(Equal(apicurioclient.CompatibilityBackward))
+
+	compatible, err := client.TestCompatibility(ccompatTestSubject, apicurioclient.LatestVersion, &apicurioclient.SchemaRequest{Schema: priceSchemaIncompatible})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(compatible).To(BeFalse())
+	compatible, err = client.TestCompatibility(ccompatTestSubject, apicurioclient.LatestVersion, &apicurioclient.SchemaRequest{Schema: priceSchemaCompatible})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(compatible).To(BeTrue())
+
+	_, err = client.RegisterSchema(ccompatTestSubject, &apicurioclient.SchemaRequest{Schema: priceSchemaIncompatible})
+	regErr, ok := apicurioclient.AsRegistryError(err)
+	Expect(ok).To(BeTrue(), "unexpected error %v", err)
+	Expect(regErr.StatusCode).To(Equal(http.StatusConflict))
+
+	newId, err := client.RegisterSchema(ccompatTestSubject, &apicurioclient.SchemaRequest{Schema: priceSchemaCompatible})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(newId).ToNot(Equal(id))
+
+	log.Info("Testing ccompat subject versions")
+	versions, err := client.ListSubjectVersions(ccompatTestSubject)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(versions).To(Equal([]int{1, 2}))
+	latest, err := client.GetSubjectVersion(ccompatTestSubject, apicurioclient.LatestVersion)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(latest.Version).To(Equal(2))
+	Expect(latest.Id).To(Equal(newId))
+	Expect(latest.Schema).To(MatchJSON(priceSchemaCompatible))
+	first, err := client.GetSubjectVersion(ccompatTestSubject, strconv.Itoa(1))
+	Expect(err).ToNot(HaveOccurred())
+	Expect(first.Id).To(Equal(id))
+
+	_, err = client.GetSubjectVersion(ccompatTestSubject, strconv.Itoa(3))
+	expectCCompatError(err, http.StatusNotFound, 40402)
+	_, err = client.GetSubjectVersion("ccompat-missing-subject", apicurioclient.LatestVersion)
+	expectCCompatError(err, http.StatusNotFound, 40401)
+
+	log.Info("Testing ccompat subject removal")
+	err = client.DeleteSubjectCompatibility(ccompatTestSubject)
+	Expect(err).ToNot(HaveOccurred())
+	deleted, err := client.DeleteSubject(ccompatTestSubject)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(deleted).To(ConsistOf(1, 2))
+	subjects, err = client.ListSubjects()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(subjects).ToNot(ContainElement(ccompatTestSubject))
+
+	log.Info("Successful ccompat API verification")
+}
+
+//expectCCompatError asserts err is the error response the ccompat api sends for the given status code and confluent error code
+func expectCCompatError(err error, statusCode int, errorCode int) {
+	regErr, ok := apicurioclient.AsRegistryError(err)
+	Expect(ok).To(BeTrue(), "unexpected error %v", err)
+	Expect(regErr.StatusCode).To(Equal(statusCode))
+	Expect(regErr.ErrorCode).To(Equal(errorCode))
+}
@@ -1,14 +1,10 @@
 package functional
 
 import (
-	"encoding/json"
 	"errors"
 	"net/http"
-	"net/url"
 	"os"
-	"strconv"
 	"strings"
-	"time"
 
 	. "github.com/onsi/gomega"
 
@@ -17,6 +13,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 
 	utils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils"
+	apicurioclient "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/client"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/timeouts"
 	types "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
 )
 
@@ -87,11 +85,14 @@ func ExecuteRegistryFunctionalTests(suiteCtx *types.SuiteContext, ctx *types.Tes
 func BasicRegistryAPITest(ctx *types.TestContext) {
 
 	log.Info("Testing registry API")
-	timeout := 60 * time.Second
+	endpoint := ctx.RegistryEndpoint()
+	httpClient, err := endpoint.HTTPClient()
+	Expect(err).NotTo(HaveOccurred())
+	timeout := timeouts.Get(timeouts.RegistryAPI)
 	statusCode := ""
 	body := ""
-	err := wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		res, err := http.Get("http://" + ctx.RegistryHost + ":" + ctx.RegistryPort + "/api/artifacts")
+	err = wait.Poll(timeouts.Interval(timeouts.APIPollInterval), timeout, func() (bool, error) {
+		res, err := httpClient.Get(endpoint.URL() + "/api/artifacts")
 		if err != nil {
 			return false, err
 		}
@@ -115,34 +116,17 @@ func BasicRegistryAPITest(ctx *types.TestContext) {
 
 func BasicRegistryAPITestWithAuthentication(ctx *types.TestContext, user string, pwd string) {
 
-	accessToken := issueAccessToken(ctx, user, pwd)
+	client := RegistryClient(ctx, KeycloakUserAuthenticator(ctx, user, pwd))
 
 	log.Info("Testing secured registry API")
-	timeout := 60 * time.Second
-	statusCode := ""
-	body := ""
-	err := wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-
-		req, err := http.NewRequest("GET", "http://"+ctx.RegistryHost+":"+ctx.RegistryPort+"/api/artifacts", nil)
-		Expect(err).NotTo(HaveOccurred())
-		req.Header.Add("Authorization", "Bearer "+accessToken)
-		res, err := http.DefaultClient.Do(req)
-
-		if err != nil {
-			return false, err
-		}
-		statusCode = res.Status
-		body = utils.ReaderToString(res.Body)
-		if res.StatusCode != 200 {
-			return false, nil
-		}
-		log.Info("Status code is " + res.Status)
-		return true, nil
+	timeout := timeouts.Get(timeouts.RegistryAPI)
+	var lastErr error
+	err := wait.Poll(timeouts.Interval(timeouts.APIPollInterval), timeout, func() (bool, error) {
+		_, lastErr = client.ListArtifacts()
+		return lastErr == nil, nil
 	})
 	if err != nil {
-		log.Info("Registry API verification failed with error")
-		log.Info("Status " + statusCode)
-		log.Info("Response " + body)
+		log.Info("Registry API verification failed with error", "error", lastErr)
 	}
 	Expect(err).NotTo(HaveOccurred())
 	log.Info("Successful registry API verification")
@@ -151,61 +135,123 @@ func BasicRegistryAPITestWithAuthentication(ctx *types.TestContext, user string,
 
 }
 
-func issueAccessToken(ctx *types.TestContext, user string, pwd string) string {
-	keycloakUrl := ctx.RegistryResource.Spec.Configuration.Security.Keycloak.Url
-	realm := ctx.RegistryResource.Spec.Configuration.Security.Keycloak.Realm
-	realmUrl := keycloakUrl + "/realms/" + realm + "/protocol/openid-connect/token"
-	clientId := ctx.RegistryResource.Spec.Configuration.Security.Keycloak.ApiClientId
+//RegistryClient returns an api client for the registry under test, honoring the scheme and certificates of its endpoint, auth can be nil for unsecured registries
+func RegistryClient(ctx *types.TestContext, auth apicurioclient.Authenticator) apicurioclient.ApicurioRegistryApiClient {
+	client, err := apicurioclient.NewApicurioRegistryApiClientForEndpoint(ctx.RegistryEndpoint(), auth)
+	Expect(err).NotTo(HaveOccurred())
+	return client
+}
+
+//KeycloakUserAuthenticator returns credentials of a user of the keycloak realm the registry is secured with, using the OIDC password grant
+func KeycloakUserAuthenticator(ctx *types.TestContext, user string, pwd string) apicurioclient.Authenticator {
+	keycloak := ctx.RegistryResource.Spec.Configuration.Security.Keycloak
+	return apicurioclient.NewOIDCPasswordGrant(apicurioclient.KeycloakTokenURL(keycloak.Url, keycloak.Realm), keycloak.ApiClientId, user, pwd, http.DefaultClient)
+}
+
+//KeycloakClientAuthenticator returns credentials of a confidential client of the keycloak realm the registry is secured with, using the OIDC client credentials grant
+func KeycloakClientAuthenticator(ctx *types.TestContext, clientId string, clientSecret string) apicurioclient.Authenticator {
+	keycloak := ctx.RegistryResource.Spec.Configuration.Security.Keycloak
+	return apicurioclient.NewOIDCClientCredentials(apicurioclient.KeycloakTokenURL(keycloak.Url, keycloak.Realm), clientId, clientSecret, http.DefaultClient)
+}
+
+//AuthenticatedCRUDTest creates, reads, updates and deletes one artifact on a secured registry using the given credentials
+func AuthenticatedCRUDTest(ctx *types.TestContext, principal string, auth apicurioclient.Authenticator) {
 
-	values := url.Values{}
-	values.Set("grant_type", "password")
-	values.Set("client_id", clientId)
-	values.Set("username", user)
-	values.Set("password", pwd)
+	client := RegistryClient(ctx, auth)
 
-	log.Info("Requesting access token")
+	groupId := "auth-tests"
+	artifactId := "crud-" + principal
 
-	res, err := http.PostForm(realmUrl, values)
+	log.Info("Testing registry CRUD operations", "principal", principal)
+	metadata, _ := createSampleArtifact(client, groupId, artifactId, apicurioclient.Avro)
+	Expect(metadata.CreatedBy).NotTo(BeEmpty())
+
+	err := client.UpdateArtifactMetaData(groupId, artifactId, &apicurioclient.EditableMetaData{
+		Name:   artifactId,
+		Labels: []string{principal},
+	})
 	Expect(err).NotTo(HaveOccurred())
-	if res.StatusCode > 299 {
-		b := utils.ReaderToString(res.Body)
-		Expect(errors.New("Keycloak request status code is " + strconv.Itoa(res.StatusCode) + " body is " + b)).NotTo(HaveOccurred())
-	}
+	updated, err := client.GetArtifactMetaData(groupId, artifactId)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(updated.Labels).To(ConsistOf(principal))
 
-	jsonMap := make(map[string]interface{})
-	err = json.Unmarshal(utils.ReaderToBytes(res.Body), &jsonMap)
+	err = client.DeleteArtifactInGroup(groupId, artifactId)
 	Expect(err).NotTo(HaveOccurred())
+	_, err = client.GetArtifactMetaData(groupId, artifactId)
+	expectRegistryError(err, http.StatusNotFound, "ArtifactNotFoundException")
 
-	return jsonMap["access_token"].(string)
+	log.Info("Successful registry CRUD verification", "principal", principal)
 }
 
-func verifyUnauthorized(ctx *types.TestContext) {
-	log.Info("Testing secured registry API rejects unauthorized access")
-	timeout := 20 * time.Second
-	statusCode := ""
-	body := ""
-	err := wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
+//ReadOnlyAccessTest verifies a principal with the read-only role of a registry with role based authorization can read artifacts
+//but every write is forbidden, the artifact read is created by the owner principal
+func ReadOnlyAccessTest(ctx *types.TestContext, principal string, auth apicurioclient.Authenticator, ownerAuth apicurioclient.Authenticator) {
+
+	owner := RegistryClient(ctx, ownerAuth)
+	client := RegistryClient(ctx, auth)
+
+	groupId := "auth-tests"
+	artifactId := "read-only-" + principal
 
-		req, err := http.NewRequest("GET", "http://"+ctx.RegistryHost+":"+ctx.RegistryPort+"/api/artifacts", nil)
+	log.Info("Testing registry read-only access", "principal", principal)
+	_, content := createSampleArtifact(owner, groupId, artifactId, apicurioclient.Avro)
+	defer func() {
+		err := owner.DeleteArtifactInGroup(groupId, artifactId)
 		Expect(err).NotTo(HaveOccurred())
-		req.Header.Add("Authorization", "Bearer foo")
-		res, err := http.DefaultClient.Do(req)
+	}()
 
-		if err != nil {
-			return false, err
-		}
-		statusCode = res.Status
-		body = utils.ReaderToString(res.Body)
-		if res.StatusCode != 401 {
-			return false, nil
-		}
-		log.Info("Status code is " + res.Status)
-		return true, nil
+	_, err := client.ListArtifacts()
+	Expect(err).NotTo(HaveOccurred())
+	stored, err := client.ReadLatestArtifact(groupId, artifactId)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(stored).To(Equal(content))
+	_, err = client.GetArtifactMetaData(groupId, artifactId)
+	Expect(err).NotTo(HaveOccurred())
+
+	_, err = client.CreateArtifactInGroup(&apicurioclient.CreateArtifactRequest{
+		GroupId:      groupId,
+		ArtifactId:   artifactId + "-forbidden",
+		ArtifactType: apicurioclient.Avro,
+		Content:      content,
+	})
+	expectForbidden(err)
+	err = client.UpdateArtifactMetaData(groupId, artifactId, &apicurioclient.EditableMetaData{
+		Name:   artifactId,
+		Labels: []string{principal},
+	})
+	expectForbidden(err)
+	err = client.DeleteArtifactInGroup(groupId, artifactId)
+	expectForbidden(err)
+
+	log.Info("Successful registry read-only access verification", "principal", principal)
+}
+
+//expectForbidden asserts err is the error response the registry sends when the principal lacks the role for the operation
+func expectForbidden(err error) {
+	Expect(apicurioclient.IsForbidden(err)).To(BeTrue(), "expected a forbidden registry error, got %v", err)
+}
+
+func verifyUnauthorized(ctx *types.TestContext) {
+	log.Info("Testing secured registry API rejects unauthorized access")
+	client := RegistryClient(ctx, apicurioclient.NewBearerToken("foo"))
+	timeout := timeouts.Get(timeouts.RegistryUnauthorized)
+	var lastErr error
+	err := wait.Poll(timeouts.Interval(timeouts.APIPollInterval), timeout, func() (bool, error) {
+		_, lastErr = client.ListArtifacts()
+		return apicurioclient.IsUnauthorized(lastErr), nil
 	})
 	if err != nil {
-		log.Info("Registry API verification failed with error")
-		log.Info("Status " + statusCode)
-		log.Info("Response " + body)
+		log.Info("Registry API verification failed with error", "error", lastErr)
 	}
 	Expect(err).NotTo(HaveOccurred())
 }
+
+//expectRegistryError asserts err is the error response the registry sends for the given status code and exception name
+func expectRegistryError(err error, statusCode int, name string) {
+	regErr, ok := apicurioclient.AsRegistryError(err)
+	Expect(ok).To(BeTrue(), "unexpected error %v", err)
+	Expect(regErr.StatusCode).To(Equal(statusCode))
+	Expect(regErr.ErrorCode).To(Equal(statusCode))
+	Expect(regErr.Name).To(Equal(name))
+	Expect(regErr.Message).NotTo(BeEmpty())
+}
@@ -0,0 +1,120 @@
+package functional
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	apicurioclient "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/client"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/probe"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/seeder"
+	types "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+)
+
+//newFakeRegistryContext starts a fake registry, closed when the test ends, and a test context pointing to it
+func newFakeRegistryContext(t *testing.T) (*apicurioclient.FakeRegistry, *types.TestContext) {
+	registry := apicurioclient.NewFakeRegistry()
+	t.Cleanup(registry.Close)
+	return registry, &types.TestContext{RegistryHost: registry.Host(), RegistryPort: registry.Port()}
+}
+
+func TestVerifiesTheRegistryApiIsUp(t *testing.T) {
+	RegisterTestingT(t)
+	_, ctx := newFakeRegistryContext(t)
+	BasicRegistryAPITest(ctx)
+}
+
+func TestCreatesEveryArtifactTypeAndCleansThemUp(t *testing.T) {
+	RegisterTestingT(t)
+	registry, ctx := newFakeRegistryContext(t)
+	ArtifactTypesTestCase(ctx)
+
+	groups, err := registry.Client().ListGroups()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(groups).To(BeEmpty())
+}
+
+func TestRunsCrudOperationsWithCredentials(t *testing.T) {
+	RegisterTestingT(t)
+	_, ctx := newFakeRegistryContext(t)
+	AuthenticatedCRUDTest(ctx, "alice", apicurioclient.NewBasicAuth("alice", "secret"))
+}
+
+func TestReadsButIsForbiddenToWriteAsAReadOnlyPrincipal(t *testing.T) {
+	RegisterTestingT(t)
+	registry, ctx := newFakeRegistryContext(t)
+	registry.SetReadOnly("bob")
+	ReadOnlyAccessTest(ctx, "bob", apicurioclient.NewBasicAuth("bob", "secret"), apicurioclient.NewBasicAuth("alice", "secret"))
+
+	artifacts, err := registry.Client().ListArtifacts()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(artifacts).To(BeEmpty())
+}
+
+func TestComputesTheExpectedResultsOfEverySearchQuery(t *testing.T) {
+	RegisterTestingT(t)
+	registry, _ := newFakeRegistryContext(t)
+	manifest, err := seeder.Seed(registry.Client(), seeder.Options{Seed: searchSeed, Prefix: "search", Groups: 3, Artifacts: 30})
+	Expect(err).ToNot(HaveOccurred())
+
+	queries := searchQueries(manifest)
+	Expect(queries).ToNot(BeEmpty())
+	for _, q := range queries {
+		found, err := searchAllPages(registry.Client(), q.request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(Equal(q.expected), "search %+v", q.request)
+	}
+	verifyVersionSearch("fake", registry.Client(), manifest)
+}
+
+func TestCreatesAndVerifiesReferencingArtifacts(t *testing.T) {
+	RegisterTestingT(t)
+	registry, _ := newFakeRegistryContext(t)
+	artifacts := CreateReferencingArtifacts(registry.Client())
+	Expect(artifacts).To(HaveLen(len(referencesExamples)))
+	VerifyReferences(registry.Client(), artifacts)
+}
+
+func TestVerifiesHealthSystemInfoAndMetrics(t *testing.T) {
+	RegisterTestingT(t)
+	registry, _ := newFakeRegistryContext(t)
+	_, err := registry.Client().ListGroups()
+	Expect(err).ToNot(HaveOccurred())
+
+	info := VerifyProbes(probe.NewProberForURL(registry.URL(), http.DefaultClient))
+	Expect(info.Version).To(Equal(apicurioclient.FakeRegistryVersion))
+}
+
+func TestReportsTheStorageHealthChecksThatAreDown(t *testing.T) {
+	RegisterTestingT(t)
+	registry, _ := newFakeRegistryContext(t)
+	registry.SetHealthCheckDown("PersistenceTimeoutReadinessCheck", true)
+	prober := probe.NewProberForURL(registry.URL(), http.DefaultClient)
+
+	ready, err := prober.Ready()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(ready.Down()).To(Equal([]string{"PersistenceTimeoutReadinessCheck"}))
+}
+
+func TestExtractsReleaseTagsOfRegistryImages(t *testing.T) {
+	RegisterTestingT(t)
+	Expect(imageTag("quay.io/apicurio/apicurio-registry-sql:2.0.1.Final")).To(Equal("2.0.1.Final"))
+	Expect(imageTag("localhost:5000/apicurio/apicurio-registry-sql")).To(Equal(""))
+	Expect(imageTag("quay.io/apicurio/apicurio-registry-sql@sha256:abc")).To(Equal(""))
+	Expect(releaseTag.MatchString(imageTag("quay.io/apicurio/apicurio-registry-sql:latest-snapshot"))).To(BeFalse())
+	Expect(releaseTag.MatchString("2.1.0.CR1")).To(BeTrue())
+}
+
+func TestMatchesRegistryErrors(t *testing.T) {
+	RegisterTestingT(t)
+	_, ctx := newFakeRegistryContext(t)
+	client := RegistryClient(ctx, nil)
+	_, err := client.GetArtifactMetaData("missing", "missing")
+	expectRegistryError(err, 404, "ArtifactNotFoundException")
+
+	failures := InterceptGomegaFailures(func() {
+		expectRegistryError(err, 409, "ArtifactNotFoundException")
+	})
+	Expect(failures).ToNot(BeEmpty())
+}
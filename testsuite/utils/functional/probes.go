@@ -0,0 +1,127 @@
+package functional
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	. "github.com/onsi/gomega"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+
+	utils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/probe"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/timeouts"
+	types "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+)
+
+//persistenceCheck text the names of the storage health checks contain, for every storage variant
+const persistenceCheck string = "Persistence"
+
+//releaseTag matches image tags of registry releases, i.e. 2.0.1.Final, other tags like latest or snapshots can't be compared with the reported version
+var releaseTag = regexp.MustCompile(`^\d+\.\d+\.\d+\.(Final|CR\d+)$`)
+
+//ProbesTestCase verifies the health, system info and metrics endpoints of the registry under test,
+//the storage health checks have to be UP and the reported version has to match the image the operator deployed
+func ProbesTestCase(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
+	prober, err := probe.NewProber(ctx.RegistryEndpoint())
+	Expect(err).ToNot(HaveOccurred())
+
+	info := VerifyProbes(prober)
+
+	image := registryImage(suiteCtx.Clientset, ctx)
+	log.Info("Registry image", "image", image, "version", info.Version)
+	if expected := operatorRegistryImage(suiteCtx.Clientset, ctx.Storage); expected != "" {
+		Expect(image).To(Equal(expected), "registry image differs from the one the operator is configured with")
+	}
+	if tag := imageTag(image); releaseTag.MatchString(tag) {
+		Expect(info.Version).To(Equal(tag), "registry reports a version other than the one of its image %v", image)
+	}
+	log.Info("Successful registry probes verification")
+}
+
+//VerifyProbes waits for the registry to be ready, verifies it's live, its storage is healthy and it exposes jvm metrics, returning its system info
+func VerifyProbes(prober *probe.Prober) *probe.SystemInfo {
+	log.Info("Verifying registry health")
+	var ready *probe.Health
+	err := wait.Poll(timeouts.Interval(timeouts.APIPollInterval), timeouts.Get(timeouts.RegistryAPI), func() (bool, error) {
+		var err error
+		ready, err = prober.Ready()
+		if err != nil {
+			log.Info("Readiness probe failed", "error", err)
+			return false, nil
+		}
+		return ready.IsUp(), nil
+	})
+	if err == wait.ErrWaitTimeout && ready != nil {
+		err = fmt.Errorf("registry not ready, checks down %v", ready.Down())
+	}
+	Expect(err).ToNot(HaveOccurred())
+
+	persistence := ready.Find(persistenceCheck)
+	Expect(persistence).ToNot(BeEmpty(), "no storage health checks in %+v", ready.Checks)
+	for _, c := range persistence {
+		Expect(c.Status).To(Equal(probe.StatusUp), "storage health check %v", c.Name)
+	}
+
+	live, err := prober.Live()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(live.IsUp()).To(BeTrue(), "registry not live, checks down %v", live.Down())
+
+	info, err := prober.SystemInfo()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(info.Version).ToNot(BeEmpty())
+
+	metrics, err := prober.Metrics()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(metrics).ToNot(BeEmpty())
+	Expect(append(metrics.WithPrefix("jvm_"), metrics.WithPrefix("base_")...)).ToNot(BeEmpty(), "no jvm metrics in %v", metrics.Names())
+	return info
+}
+
+//registryImage image of the running registry pods
+func registryImage(clientset *kubernetes.Clientset, ctx *types.TestContext) string {
+	labelsSet := labels.Set(map[string]string{"app": ctx.RegistryName})
+	pods, err := clientset.CoreV1().Pods(ctx.RegistryNamespace).List(context.TODO(), metav1.ListOptions{LabelSelector: labelsSet.AsSelector().String()})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(pods.Items).ToNot(BeEmpty())
+	Expect(pods.Items[0].Spec.Containers).ToNot(BeEmpty())
+	return pods.Items[0].Spec.Containers[0].Image
+}
+
+//operatorRegistryImage image the operator deploys for the given storage, empty if the operator deployment doesn't configure one
+func operatorRegistryImage(clientset *kubernetes.Clientset, storage string) string {
+	var env string = "REGISTRY_IMAGE_" + strings.ToUpper(storage)
+	for _, name := range []string{utils.OperatorDeploymentNameOlm, utils.OperatorDeploymentName} {
+		deployment, err := clientset.AppsV1().Deployments(utils.OperatorNamespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if kubeerrors.IsNotFound(err) {
+			continue
+		}
+		Expect(err).ToNot(HaveOccurred())
+		for _, c := range deployment.Spec.Template.Spec.Containers {
+			for _, e := range c.Env {
+				if e.Name == env {
+					return e.Value
+				}
+			}
+		}
+		return ""
+	}
+	return ""
+}
+
+//imageTag tag of the image reference, empty for digests or untagged images
+func imageTag(image string) string {
+	if strings.Contains(image, "@") {
+		return ""
+	}
+	colon := strings.LastIndex(image, ":")
+	if colon < 0 || strings.Contains(image[colon:], "/") {
+		return ""
+	}
+	return image[colon+1:]
+}
@@ -0,0 +1,209 @@
+package functional
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/gomega"
+
+	apicurioutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio"
+	apicurioclient "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/client"
+	types "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+)
+
+const referencesGroup string = "references"
+
+//ReferencingArtifact artifact created with references to other artifacts
+type ReferencingArtifact struct {
+	GroupId    string
+	ArtifactId string
+	Version    string
+	Type       apicurioclient.ArtifactType
+	Content    string
+	References []apicurioclient.ArtifactReference
+	GlobalId   int64
+
+	//inlined checks the referenced definitions are part of the dereferenced document, nil if the registry doesn't dereference the artifact type
+	inlined func(document interface{}) bool
+}
+
+//referencedArtifact artifact other artifacts refer to
+type referencedArtifact struct {
+	artifactId   string
+	artifactType apicurioclient.ArtifactType
+	content      string
+}
+
+type referencesExample struct {
+	referenced  referencedArtifact
+	referencing referencedArtifact
+	//name how the referencing content refers to the referenced artifact
+	name    string
+	inlined func(document interface{}) bool
+}
+
+var referencesExamples []referencesExample = []referencesExample{
+	{
+		referenced: referencedArtifact{
+			artifactId:   "refs-currency",
+			artifactType: apicurioclient.Avro,
+			content:      `{"type":"record","name":"Currency","namespace":"com.example.refs","fields":[{"name":"code","type":"string"}]}`,
+		},
+		referencing: referencedArtifact{
+			artifactId:   "refs-price",
+			artifactType: apicurioclient.Avro,
+			content:      `{"type":"record","name":"Price","namespace":"com.example.refs","fields":[{"name":"amount","type":"double"},{"name":"currency","type":"com.example.refs.Currency"}]}`,
+		},
+		name: "com.example.refs.Currency",
+		inlined: func(document interface{}) bool {
+			return findObject(document, func(o map[string]interface{}) bool {
+				_, hasFields := o["fields"]
+				return hasFields && (o["name"] == "Currency" || o["name"] == "com.example.refs.Currency")
+			})
+		},
+	},
+	{
+		referenced: referencedArtifact{
+			artifactId:   "refs-address",
+			artifactType: apicurioclient.JsonSchema,
+			content:      `{"$id":"address.json","type":"object","properties":{"street":{"type":"string"},"city":{"type":"string"}}}`,
+		},
+		referencing: referencedArtifact{
+			artifactId:   "refs-customer",
+			artifactType: apicurioclient.JsonSchema,
+			content:      `{"$schema":"http://json-schema.org/draft-07/schema#","type":"object","properties":{"name":{"type":"string"},"address":{"$ref":"address.json"}}}`,
+		},
+		name: "address.json",
+		inlined: func(document interface{}) bool {
+			return findObject(document, func(o map[string]interface{}) bool {
+				properties, isObject := o["properties"].(map[string]interface{})
+				_, hasStreet := properties["street"]
+				return isObject && hasStreet
+			})
+		},
+	},
+	{
+		referenced: referencedArtifact{
+			artifactId:   "refs-money",
+			artifactType: apicurioclient.Protobuf,
+			content:      "syntax = \"proto3\";\npackage refs;\n\nmessage Money {\n  string currency = 1;\n  int64 units = 2;\n}\n",
+		},
+		referencing: referencedArtifact{
+			artifactId:   "refs-order",
+			artifactType: apicurioclient.Protobuf,
+			content:      "syntax = \"proto3\";\npackage refs;\n\nimport \"money.proto\";\n\nmessage Order {\n  string id = 1;\n  refs.Money total = 2;\n}\n",
+		},
+		name: "money.proto",
+		//protobuf imports are resolved by consumers, the registry doesn't dereference them
+		inlined: nil,
+	},
+}
+
+//ReferencesTestCase creates artifacts with references of every kind and verifies references and dereferenced content survive a registry restart
+func ReferencesTestCase(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
+	client := RegistryClient(ctx, nil)
+
+	artifacts := CreateReferencingArtifacts(client)
+	VerifyReferences(client, artifacts)
+
+	apicurioutils.RestartRegistry(suiteCtx, ctx.RegistryNamespace, ctx.RegistryName)
+	BasicRegistryAPITest(ctx)
+
+	log.Info("Verifying references after registry restart")
+	VerifyReferences(client, artifacts)
+	log.Info("Successful artifact references verification")
+}
+
+//CreateReferencingArtifacts creates avro, json schema and protobuf artifacts referring to other artifacts, returning the referencing ones
+func CreateReferencingArtifacts(client apicurioclient.ApicurioRegistryApiClient) []*ReferencingArtifact {
+	log.Info("Creating artifacts with references")
+	artifacts := []*ReferencingArtifact{}
+	for _, example := range referencesExamples {
+		referenced, err := client.CreateArtifactInGroup(&apicurioclient.CreateArtifactRequest{
+			GroupId:      referencesGroup,
+			ArtifactId:   example.referenced.artifactId,
+			ArtifactType: example.referenced.artifactType,
+			Content:      example.referenced.content,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		references := []apicurioclient.ArtifactReference{
+			{GroupId: referencesGroup, ArtifactId: example.referenced.artifactId, Version: referenced.Version, Name: example.name},
+		}
+		referencing, err := client.CreateArtifactInGroup(&apicurioclient.CreateArtifactRequest{
+			GroupId:      referencesGroup,
+			ArtifactId:   example.referencing.artifactId,
+			ArtifactType: example.referencing.artifactType,
+			Content:      example.referencing.content,
+			References:   references,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		artifacts = append(artifacts, &ReferencingArtifact{
+			GroupId:    referencesGroup,
+			ArtifactId: example.referencing.artifactId,
+			Version:    referencing.Version,
+			Type:       example.referencing.artifactType,
+			Content:    example.referencing.content,
+			References: references,
+			GlobalId:   referencing.GlobalId,
+			inlined:    example.inlined,
+		})
+	}
+	return artifacts
+}
+
+//VerifyReferences verifies the references, the content and the dereferenced content of every artifact
+func VerifyReferences(client apicurioclient.ApicurioRegistryApiClient, artifacts []*ReferencingArtifact) {
+	for _, a := range artifacts {
+		references, err := client.ListArtifactVersionReferences(a.GroupId, a.ArtifactId, a.Version)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(references).To(ConsistOf(a.References), "references of %v", a.ArtifactId)
+
+		references, err = client.ListReferencesByGlobalId(a.GlobalId)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(references).To(ConsistOf(a.References), "references of global id %v", a.GlobalId)
+
+		content, err := client.ReadArtifactVersion(a.GroupId, a.ArtifactId, a.Version)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal(a.Content))
+
+		if a.inlined == nil {
+			continue
+		}
+		dereferenced, err := client.ReadDereferencedArtifactVersion(a.GroupId, a.ArtifactId, a.Version)
+		Expect(err).ToNot(HaveOccurred())
+		if dereferenced == a.Content {
+			log.Info("Registry does not support dereferencing, skipping dereferenced content verification", "artifact", a.ArtifactId)
+			continue
+		}
+		var document interface{}
+		Expect(json.Unmarshal([]byte(dereferenced), &document)).To(Succeed())
+		Expect(a.inlined(document)).To(BeTrue(), "references of %v not inlined in %v", a.ArtifactId, dereferenced)
+
+		byGlobalId, err := client.ReadDereferencedContentByGlobalId(a.GlobalId)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(byGlobalId).To(MatchJSON(dereferenced))
+	}
+}
+
+//findObject walks the json document looking for an object matching the predicate
+func findObject(node interface{}, match func(o map[string]interface{}) bool) bool {
+	switch n := node.(type) {
+	case []interface{}:
+		for _, child := range n {
+			if findObject(child, match) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		if match(n) {
+			return true
+		}
+		for _, child := range n {
+			if findObject(child, match) {
+				return true
+			}
+		}
+	}
+	return false
+}
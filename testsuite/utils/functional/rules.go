@@ -0,0 +1,119 @@
+package functional
+
+import (
+	"net/http"
+
+	. "github.com/onsi/gomega"
+
+	apicurioclient "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/client"
+	types "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+)
+
+const rulesTestGroup string = "rules-tests"
+
+var priceSchema string = "{\"type\":\"record\",\"name\":\"price\",\"namespace\":\"com.example\",\"fields\":[{\"name\":\"symbol\",\"type\":\"string\"},{\"name\":\"price\",\"type\":\"string\"}]}"
+
+//backward compatible with priceSchema, new field has a default value
+var priceSchemaCompatible string = "{\"type\":\"record\",\"name\":\"price\",\"namespace\":\"com.example\",\"fields\":[{\"name\":\"symbol\",\"type\":\"string\"},{\"name\":\"price\",\"type\":\"string\"},{\"name\":\"currency\",\"type\":\"string\",\"default\":\"EUR\"}]}"
+
+//not backward compatible with the other price schemas, new field without default value
+var priceSchemaIncompatible string = "{\"type\":\"record\",\"name\":\"price\",\"namespace\":\"com.example\",\"fields\":[{\"name\":\"symbol\",\"type\":\"string\"},{\"name\":\"price\",\"type\":\"string\"},{\"name\":\"exchange\",\"type\":\"string\"}]}"
+
+var invalidSchema string = "{\"type\":\"record\",\"name\":\"broken\",\"fields\":["
+
+//RulesTestCase verifies global and artifact level rules are stored and enforced by the registry
+func RulesTestCase(ctx *types.TestContext) {
+
+	client := RegistryClient(ctx, nil)
+
+	defer func() {
+		log.Info("Cleaning rules test data")
+		client.DeleteAllGlobalRules()
+		client.DeleteArtifactsInGroup(rulesTestGroup)
+	}()
+
+	log.Info("Testing global validity rule")
+	err := client.CreateGlobalRule(&apicurioclient.Rule{Type: apicurioclient.ValidityRule, Config: "FULL"})
+	Expect(err).ToNot(HaveOccurred())
+	globalRules, err := client.ListGlobalRules()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(globalRules).To(ConsistOf(apicurioclient.ValidityRule))
+
+	_, err = client.CreateArtifactInGroup(&apicurioclient.CreateArtifactRequest{
+		GroupId:      rulesTestGroup,
+		ArtifactId:   "invalid",
+		ArtifactType: apicurioclient.Avro,
+		Content:      invalidSchema,
+	})
+	expectRegistryError(err, http.StatusConflict, "RuleViolationException")
+
+	_, err = client.CreateArtifactInGroup(&apicurioclient.CreateArtifactRequest{
+		GroupId:      rulesTestGroup,
+		ArtifactId:   "price",
+		ArtifactType: apicurioclient.Avro,
+		Content:      priceSchema,
+	})
+	Expect(err).ToNot(HaveOccurred())
+
+	_, err = client.CreateArtifactInGroup(&apicurioclient.CreateArtifactRequest{
+		GroupId:      rulesTestGroup,
+		ArtifactId:   "price",
+		ArtifactType: apicurioclient.Avro,
+		Content:      priceSchema,
+	})
+	expectRegistryError(err, http.StatusConflict, "ArtifactAlreadyExistsException")
+
+	log.Info("Testing global compatibility rule")
+	err = client.CreateGlobalRule(&apicurioclient.Rule{Type: apicurioclient.CompatibilityRule, Config: "BACKWARD"})
+	Expect(err).ToNot(HaveOccurred())
+
+	_, err = client.CreateArtifactVersion(&apicurioclient.CreateVersionRequest{GroupId: rulesTestGroup, ArtifactId: "price", Content: priceSchemaIncompatible})
+	expectRegistryError(err, http.StatusConflict, "RuleViolationException")
+	_, err = client.CreateArtifactVersion(&apicurioclient.CreateVersionRequest{GroupId: rulesTestGroup, ArtifactId: "price", Content: priceSchemaCompatible})
+	Expect(err).ToNot(HaveOccurred())
+
+	log.Info("Testing artifact rule overrides global rule")
+	err = client.CreateArtifactRule(rulesTestGroup, "price", &apicurioclient.Rule{Type: apicurioclient.CompatibilityRule, Config: "NONE"})
+	Expect(err).ToNot(HaveOccurred())
+	artifactRule, err := client.GetArtifactRule(rulesTestGroup, "price", apicurioclient.CompatibilityRule)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(artifactRule.Config).To(Equal("NONE"))
+
+	_, err = client.CreateArtifactVersion(&apicurioclient.CreateVersionRequest{GroupId: rulesTestGroup, ArtifactId: "price", Content: priceSchemaIncompatible})
+	Expect(err).ToNot(HaveOccurred())
+
+	versions, err := client.ListArtifactVersions(rulesTestGroup, "price")
+	Expect(err).ToNot(HaveOccurred())
+	Expect(len(versions)).To(BeIdenticalTo(3))
+
+	log.Info("Testing rules update and removal")
+	err = client.UpdateArtifactRule(rulesTestGroup, "price", &apicurioclient.Rule{Type: apicurioclient.CompatibilityRule, Config: "FULL"})
+	Expect(err).ToNot(HaveOccurred())
+	artifactRule, err = client.GetArtifactRule(rulesTestGroup, "price", apicurioclient.CompatibilityRule)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(artifactRule.Config).To(Equal("FULL"))
+
+	err = client.DeleteArtifactRule(rulesTestGroup, "price", apicurioclient.CompatibilityRule)
+	Expect(err).ToNot(HaveOccurred())
+	artifactRules, err := client.ListArtifactRules(rulesTestGroup, "price")
+	Expect(err).ToNot(HaveOccurred())
+	Expect(artifactRules).To(BeEmpty())
+	_, err = client.GetArtifactRule(rulesTestGroup, "price", apicurioclient.CompatibilityRule)
+	expectRegistryError(err, http.StatusNotFound, "RuleNotFoundException")
+
+	err = client.UpdateGlobalRule(&apicurioclient.Rule{Type: apicurioclient.ValidityRule, Config: "SYNTAX_ONLY"})
+	Expect(err).ToNot(HaveOccurred())
+	globalRule, err := client.GetGlobalRule(apicurioclient.ValidityRule)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(globalRule.Config).To(Equal("SYNTAX_ONLY"))
+
+	err = client.DeleteGlobalRule(apicurioclient.ValidityRule)
+	Expect(err).ToNot(HaveOccurred())
+	err = client.DeleteAllGlobalRules()
+	Expect(err).ToNot(HaveOccurred())
+	globalRules, err = client.ListGlobalRules()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(globalRules).To(BeEmpty())
+
+	log.Info("Successful registry rules verification")
+}
@@ -0,0 +1,186 @@
+package functional
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+
+	apicurioclient "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/client"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/seeder"
+	kubernetesutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetes"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/timeouts"
+	types "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+)
+
+//searchSeed seed of the dataset searched, fixed so failures can be reproduced
+const searchSeed int64 = 20210603
+
+const searchPageSize int = 7
+
+//searchQuery one search and the artifacts it has to return, as groupId/artifactId
+type searchQuery struct {
+	request  apicurioclient.ArtifactSearchRequest
+	expected []string
+}
+
+//SearchReplicasTestCase seeds the registry through its service and verifies every replica returns the same search results,
+//replicas of a clustered registry index data independently so stale search results show up here
+func SearchReplicasTestCase(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
+
+	manifest, err := seeder.Seed(RegistryClient(ctx, nil), seeder.Options{Seed: searchSeed, Prefix: "search", Groups: 3, Artifacts: 30})
+	Expect(err).ToNot(HaveOccurred())
+
+	queries := searchQueries(manifest)
+	replicas := ReplicaClients(suiteCtx, ctx)
+	Expect(replicas).To(HaveLen(int(ctx.RegistryReplicas())))
+
+	for pod, client := range replicas {
+		log.Info("Verifying search results", "replica", pod, "queries", len(queries))
+		var mismatch string
+		err := wait.Poll(timeouts.Interval(timeouts.APIPollInterval), timeouts.Get(timeouts.SearchConsistency), func() (bool, error) {
+			mismatch = ""
+			for _, q := range queries {
+				found, err := searchAllPages(client, q.request)
+				if err != nil {
+					return false, err
+				}
+				if strings.Join(found, ",") != strings.Join(q.expected, ",") {
+					mismatch = fmt.Sprintf("search %+v expected %v but found %v", q.request, q.expected, found)
+					return false, nil
+				}
+			}
+			return true, nil
+		})
+		if err == wait.ErrWaitTimeout {
+			err = fmt.Errorf("replica %v returned stale search results: %v", pod, mismatch)
+		}
+		Expect(err).ToNot(HaveOccurred())
+
+		verifyVersionSearch(pod, client, manifest)
+	}
+	log.Info("Successful search verification on every replica")
+}
+
+//searchQueries queries covering every filter, with the results computed from the seeded dataset
+func searchQueries(manifest *seeder.Manifest) []searchQuery {
+	queries := []searchQuery{}
+	add := func(req apicurioclient.ArtifactSearchRequest, match func(a *seeder.ArtifactManifest) bool) {
+		expected := []string{}
+		for _, a := range manifest.Artifacts {
+			if match(a) {
+				expected = append(expected, a.GroupId+"/"+a.ArtifactId)
+			}
+		}
+		sort.Strings(expected)
+		queries = append(queries, searchQuery{request: req, expected: expected})
+	}
+
+	for _, group := range manifest.Groups() {
+		g := group
+		add(apicurioclient.ArtifactSearchRequest{Group: g}, func(a *seeder.ArtifactManifest) bool { return a.GroupId == g })
+	}
+	for _, artifactType := range apicurioclient.ArtifactTypes {
+		label := strings.ToLower(string(artifactType))
+		add(apicurioclient.ArtifactSearchRequest{Labels: []string{label}}, func(a *seeder.ArtifactManifest) bool { return containsString(a.Labels, label) })
+	}
+	for _, a := range manifest.Artifacts[:3] {
+		target := a
+		add(apicurioclient.ArtifactSearchRequest{Name: target.Name}, func(a *seeder.ArtifactManifest) bool {
+			return strings.Contains(a.Name, target.Name) || strings.Contains(a.ArtifactId, target.Name)
+		})
+		add(apicurioclient.ArtifactSearchRequest{Properties: map[string]string{"index": target.Properties["index"]}}, func(a *seeder.ArtifactManifest) bool {
+			return a.Properties["index"] == target.Properties["index"]
+		})
+	}
+	description := string(apicurioclient.Avro) + " artifact"
+	add(apicurioclient.ArtifactSearchRequest{Description: description}, func(a *seeder.ArtifactManifest) bool {
+		return strings.Contains(a.Description, description)
+	})
+	return queries
+}
+
+//searchAllPages walks every page of the search, returning the sorted groupId/artifactId of the results
+func searchAllPages(client apicurioclient.ApicurioRegistryApiClient, req apicurioclient.ArtifactSearchRequest) ([]string, error) {
+	found := []string{}
+	req.Limit = searchPageSize
+	for req.Offset = 0; ; req.Offset += searchPageSize {
+		page, err := client.SearchArtifacts(&req)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range page.Artifacts {
+			found = append(found, a.GroupId+"/"+a.Id)
+		}
+		if len(page.Artifacts) == 0 || len(found) >= page.Count {
+			break
+		}
+	}
+	sort.Strings(found)
+	return found, nil
+}
+
+//verifyVersionSearch checks the versions of one artifact per group, registries not serving the versions search are skipped
+func verifyVersionSearch(pod string, client apicurioclient.ApicurioRegistryApiClient, manifest *seeder.Manifest) {
+	for _, groupId := range manifest.Groups() {
+		a := manifest.ArtifactsInGroup(groupId)[0]
+		results, err := client.SearchVersions(&apicurioclient.VersionSearchRequest{GroupId: a.GroupId, ArtifactId: a.ArtifactId})
+		if apicurioclient.IsNotFound(err) {
+			log.Info("Versions search not supported, skipping", "replica", pod)
+			return
+		}
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results.Count).To(Equal(len(a.Versions)), "replica %v artifact %v/%v", pod, a.GroupId, a.ArtifactId)
+	}
+}
+
+//ReplicaClients returns one api client per ready pod of the registry, keyed by pod name.
+//Requests go through the kubernetes api server pod proxy, so every replica is reached directly, bypassing the service
+func ReplicaClients(suiteCtx *types.SuiteContext, ctx *types.TestContext) map[string]apicurioclient.ApicurioRegistryApiClient {
+	labelsSet := labels.Set(map[string]string{"app": ctx.RegistryName})
+	pods, err := suiteCtx.Clientset.CoreV1().Pods(ctx.RegistryNamespace).List(context.TODO(), metav1.ListOptions{LabelSelector: labelsSet.AsSelector().String()})
+	Expect(err).ToNot(HaveOccurred())
+
+	transport, err := rest.TransportFor(suiteCtx.Cfg)
+	Expect(err).ToNot(HaveOccurred())
+	httpClient := &http.Client{Transport: transport}
+
+	clients := map[string]apicurioclient.ApicurioRegistryApiClient{}
+	for _, pod := range pods.Items {
+		if !kubernetesutils.IsPodReady(&pod) {
+			continue
+		}
+		proxyURL := fmt.Sprintf("%v/api/v1/namespaces/%v/pods/%v:%v/proxy", strings.TrimSuffix(suiteCtx.Cfg.Host, "/"), pod.Namespace, pod.Name, containerPort(&pod))
+		clients[pod.Name] = apicurioclient.NewApicurioRegistryApiClientForURL(proxyURL, httpClient, nil)
+	}
+	return clients
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+//containerPort http port of the registry container, 8080 unless the pod declares another one
+func containerPort(pod *corev1.Pod) int32 {
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Protocol == "" || p.Protocol == corev1.ProtocolTCP {
+				return p.ContainerPort
+			}
+		}
+	}
+	return 8080
+}
@@ -0,0 +1,167 @@
+package golden
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/apimachinery/pkg/runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+)
+
+var log = logf.Log.WithName("golden")
+
+//volatileFields fields set by the api server or the cluster, they change on every deployment
+var volatileFields [][]string = [][]string{
+	{"status"},
+	{"metadata", "uid"},
+	{"metadata", "resourceVersion"},
+	{"metadata", "generation"},
+	{"metadata", "creationTimestamp"},
+	{"metadata", "managedFields"},
+	{"metadata", "selfLink"},
+	{"metadata", "annotations", "deployment.kubernetes.io/revision"},
+	{"metadata", "annotations", "kubectl.kubernetes.io/last-applied-configuration"},
+	{"spec", "template", "metadata", "creationTimestamp"},
+	{"spec", "clusterIP"},
+	{"spec", "clusterIPs"},
+}
+
+//Replacement value depending on the cluster or the testsuite run, replaced in every string by a placeholder like ${NAMESPACE}
+type Replacement struct {
+	Placeholder string
+	Value       string
+}
+
+//Normalize converts the object to a plain document without volatile fields, replacing the values that change between runs by their placeholders.
+//The document keeps apiVersion and kind, obj must have them set
+func Normalize(obj runtime.Object, replacements []Replacement) (map[string]interface{}, error) {
+	document, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range volatileFields {
+		removeField(document, path)
+	}
+	if metadata, isMap := document["metadata"].(map[string]interface{}); isMap {
+		if owners, isList := metadata["ownerReferences"].([]interface{}); isList {
+			for _, owner := range owners {
+				if o, isMap := owner.(map[string]interface{}); isMap {
+					delete(o, "uid")
+				}
+			}
+		}
+	}
+
+	sorted := make([]Replacement, 0, len(replacements))
+	for _, r := range replacements {
+		if r.Value != "" {
+			sorted = append(sorted, r)
+		}
+	}
+	//longer values first, so values containing other values, like a host containing the namespace, are replaced as a whole
+	sort.SliceStable(sorted, func(i, j int) bool { return len(sorted[i].Value) > len(sorted[j].Value) })
+	return prune(replaceStrings(document, sorted)).(map[string]interface{}), nil
+}
+
+//removeField removes the field and the maps it leaves empty, like annotations only holding the deployment revision
+func removeField(document map[string]interface{}, path []string) {
+	if len(path) == 1 {
+		delete(document, path[0])
+		return
+	}
+	next, isMap := document[path[0]].(map[string]interface{})
+	if !isMap {
+		return
+	}
+	removeField(next, path[1:])
+	if len(next) == 0 {
+		delete(document, path[0])
+	}
+}
+
+func replaceStrings(node interface{}, replacements []Replacement) interface{} {
+	switch n := node.(type) {
+	case string:
+		for _, r := range replacements {
+			n = strings.ReplaceAll(n, r.Value, "${"+r.Placeholder+"}")
+		}
+		return n
+	case []interface{}:
+		for i := range n {
+			n[i] = replaceStrings(n[i], replacements)
+		}
+	case map[string]interface{}:
+		for k, v := range n {
+			n[k] = replaceStrings(v, replacements)
+		}
+	}
+	return node
+}
+
+//prune removes null values, they only depend on how the object was serialized.
+//Empty maps are kept, some carry meaning, like the emptyDir of a volume
+func prune(node interface{}) interface{} {
+	switch n := node.(type) {
+	case []interface{}:
+		for i := range n {
+			n[i] = prune(n[i])
+		}
+	case map[string]interface{}:
+		for k, v := range n {
+			if v == nil {
+				delete(n, k)
+				continue
+			}
+			n[k] = prune(v)
+		}
+	}
+	return node
+}
+
+//Render the documents as a multi document yaml, keys are sorted so the output is stable
+func Render(documents []map[string]interface{}) ([]byte, error) {
+	parts := make([]string, 0, len(documents))
+	for _, d := range documents {
+		data, err := yaml.Marshal(d)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, string(data))
+	}
+	return []byte(strings.Join(parts, "---\n")), nil
+}
+
+//Compare diffs the actual manifests against the golden file, returning a unified diff, empty if they are equal.
+//With update the golden file is written with the actual manifests instead
+func Compare(goldenFile string, actual []byte, update bool) (string, error) {
+	if update {
+		log.Info("Updating golden file", "file", goldenFile)
+		if err := os.MkdirAll(filepath.Dir(goldenFile), os.ModePerm); err != nil {
+			return "", err
+		}
+		return "", ioutil.WriteFile(goldenFile, actual, 0644)
+	}
+	expected, err := ioutil.ReadFile(goldenFile)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("golden file %v not found, run the testsuite with the update flag to create it", goldenFile)
+	}
+	if err != nil {
+		return "", err
+	}
+	if string(expected) == string(actual) {
+		return "", nil
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(expected)),
+		B:        difflib.SplitLines(string(actual)),
+		FromFile: goldenFile,
+		ToFile:   "generated",
+		Context:  3,
+	})
+}
@@ -0,0 +1,179 @@
+package golden
+
+import (
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubetypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+)
+
+func registryService(namespace string, uid string, clusterIP string) *corev1.Service {
+	controller := true
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "apicurio-registry-sql-service",
+			Namespace:         namespace,
+			UID:               kubetypes.UID(uid),
+			ResourceVersion:   "1234",
+			CreationTimestamp: metav1.Now(),
+			Labels:            map[string]string{"app": "apicurio-registry-sql", "apicur.io/version": "2.0.1.Final"},
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "registry.apicur.io/v1", Kind: "ApicurioRegistry", Name: "apicurio-registry-sql", UID: kubetypes.UID(uid + "-owner"), Controller: &controller},
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: clusterIP,
+			Ports:     []corev1.ServicePort{{Protocol: corev1.ProtocolTCP, Port: 8080, TargetPort: intstr.FromInt(8080)}},
+			Selector:  map[string]string{"app": "apicurio-registry-sql"},
+			Type:      corev1.ServiceTypeClusterIP,
+		},
+		Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}}},
+	}
+}
+
+func renderServices(g *WithT, namespace string, uid string, clusterIP string) string {
+	document, err := Normalize(registryService(namespace, uid, clusterIP), []Replacement{
+		{Placeholder: "NAMESPACE", Value: namespace},
+		{Placeholder: "REGISTRY_VERSION", Value: "2.0.1.Final"},
+		{Placeholder: "REGISTRY_IMAGE", Value: ""},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	data, err := Render([]map[string]interface{}{document, document})
+	g.Expect(err).ToNot(HaveOccurred())
+	return string(data)
+}
+
+func TestRemovesVolatileFieldsAndReplacesValuesChangingBetweenRuns(t *testing.T) {
+	g := NewWithT(t)
+	manifests := renderServices(g, "apicurio-registry-e2e", "a1b2", "10.96.0.12")
+	g.Expect(manifests).To(Equal(`apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    apicur.io/version: ${REGISTRY_VERSION}
+    app: apicurio-registry-sql
+  name: apicurio-registry-sql-service
+  namespace: ${NAMESPACE}
+  ownerReferences:
+  - apiVersion: registry.apicur.io/v1
+    controller: true
+    kind: ApicurioRegistry
+    name: apicurio-registry-sql
+spec:
+  ports:
+  - port: 8080
+    protocol: TCP
+    targetPort: 8080
+  selector:
+    app: apicurio-registry-sql
+  type: ClusterIP
+---
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    apicur.io/version: ${REGISTRY_VERSION}
+    app: apicurio-registry-sql
+  name: apicurio-registry-sql-service
+  namespace: ${NAMESPACE}
+  ownerReferences:
+  - apiVersion: registry.apicur.io/v1
+    controller: true
+    kind: ApicurioRegistry
+    name: apicurio-registry-sql
+spec:
+  ports:
+  - port: 8080
+    protocol: TCP
+    targetPort: 8080
+  selector:
+    app: apicurio-registry-sql
+  type: ClusterIP
+`))
+	g.Expect(renderServices(g, "other-namespace", "c3d4", "10.96.0.99")).To(Equal(manifests))
+}
+
+func TestReplacesLongerValuesFirst(t *testing.T) {
+	g := NewWithT(t)
+	obj := registryService("registry", "a1b2", "")
+	obj.Annotations = map[string]string{"host": "registry.registry.example.com"}
+	document, err := Normalize(obj, []Replacement{
+		{Placeholder: "NAMESPACE", Value: "registry"},
+		{Placeholder: "REGISTRY_HOST", Value: "registry.registry.example.com"},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	metadata := document["metadata"].(map[string]interface{})
+	g.Expect(metadata["annotations"]).To(HaveKeyWithValue("host", "${REGISTRY_HOST}"))
+	g.Expect(metadata["namespace"]).To(Equal("${NAMESPACE}"))
+}
+
+func TestCompareFailsWhenTheGoldenFileDoesntExist(t *testing.T) {
+	g := NewWithT(t)
+	dir := t.TempDir()
+	_, err := Compare(filepath.Join(dir, "kubernetes", "storage-sql.yaml"), []byte("kind: Service\n"), false)
+	g.Expect(err).To(MatchError(ContainSubstring("run the testsuite with the update flag")))
+}
+
+func TestCompareUpdatesTheGoldenFileAndReportsReadableDiffs(t *testing.T) {
+	g := NewWithT(t)
+	dir := t.TempDir()
+	goldenFile := filepath.Join(dir, "kubernetes", "storage-sql.yaml")
+	manifests := renderServices(g, "apicurio-registry-e2e", "a1b2", "10.96.0.12")
+
+	diff, err := Compare(goldenFile, []byte(manifests), true)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(diff).To(BeEmpty())
+
+	diff, err = Compare(goldenFile, []byte(manifests), false)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(diff).To(BeEmpty())
+
+	obj := registryService("apicurio-registry-e2e", "a1b2", "")
+	obj.Spec.Ports[0].Port = 8081
+	document, err := Normalize(obj, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	changed, err := Render([]map[string]interface{}{document})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	diff, err = Compare(goldenFile, changed, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(diff).To(ContainSubstring("--- " + goldenFile))
+	g.Expect(diff).To(ContainSubstring("+++ generated"))
+	g.Expect(diff).To(ContainSubstring("-  - port: 8080"))
+	g.Expect(diff).To(ContainSubstring("+  - port: 8081"))
+}
+
+func TestManifestsFileNamesTheSizeOfTheDeployment(t *testing.T) {
+	g := NewWithT(t)
+	kubernetes := &types.SuiteContext{}
+	g.Expect(ManifestsFile(kubernetes, &types.TestContext{Storage: "sql", Size: types.SmallSize})).To(HaveSuffix("/golden/kubernetes/storage-sql-small.yaml"))
+	g.Expect(ManifestsFile(kubernetes, &types.TestContext{Storage: "sql"})).To(HaveSuffix("/golden/kubernetes/storage-sql-normal.yaml"))
+	g.Expect(ManifestsFile(&types.SuiteContext{IsOpenshift: true}, &types.TestContext{Storage: "kafkasql", Size: types.NormalSize, Replicas: 3, KafkaSecurity: types.Tls})).
+		To(HaveSuffix("/golden/openshift/storage-kafkasql-normal-replicas-3-tls.yaml"))
+}
+
+func TestNormalizeKeepsMeaningfulEmptyMaps(t *testing.T) {
+	g := NewWithT(t)
+	obj := registryService("registry", "a1b2", "")
+	obj.Annotations = map[string]string{"deployment.kubernetes.io/revision": "2"}
+	document, err := Normalize(obj, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(document["metadata"]).ToNot(HaveKey("annotations"))
+
+	deployment := &appsv1.Deployment{TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}}
+	deployment.Spec.Template.Spec.Volumes = []corev1.Volume{{Name: "tmp", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}
+	document, err = Normalize(deployment, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	data, err := Render([]map[string]interface{}{document})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(data)).To(ContainSubstring("      volumes:\n      - emptyDir: {}\n        name: tmp\n"))
+}
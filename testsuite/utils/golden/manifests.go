@@ -0,0 +1,147 @@
+package golden
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+
+	. "github.com/onsi/gomega"
+
+	routev1 "github.com/openshift/api/route/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils"
+	apicurioutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+	apicurio "github.com/Apicurio/apicurio-registry-operator/api/v1"
+)
+
+//registryVersionLabel label the operator sets to the registry version on every object it generates
+const registryVersionLabel string = "apicur.io/version"
+
+//ManifestsDir directory golden manifests are stored in, one subdirectory per platform
+func ManifestsDir() string {
+	return utils.SuiteProjectDir + "/testsuite/golden"
+}
+
+//ManifestsFile golden file of the manifests generated for the test context, the deployment size is part of the name
+//because it sets the resources of the registry deployment
+func ManifestsFile(suiteCtx *types.SuiteContext, ctx *types.TestContext) string {
+	platform := "kubernetes"
+	if suiteCtx.IsOpenshift {
+		platform = "openshift"
+	}
+	size := ctx.Size
+	if size == "" {
+		size = types.NormalSize
+	}
+	name := "storage-" + ctx.Storage + "-" + string(size)
+	if replicas := ctx.RegistryReplicas(); replicas > 1 {
+		name += "-replicas-" + strconv.Itoa(int(replicas))
+	}
+	if ctx.Auth {
+		name += "-auth"
+	}
+	if ctx.KafkaSecurity != "" {
+		name += "-" + string(ctx.KafkaSecurity)
+	}
+	return ManifestsDir() + "/" + platform + "/" + name + ".yaml"
+}
+
+//ManifestsTestCase compares the deployment, service, ingress or route, pod disruption budget and network policy the operator generated for the registry
+//against the golden file of the test context, or regenerates the golden file in update mode. Does nothing unless the golden manifests mode is enabled
+func ManifestsTestCase(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
+	if !suiteCtx.GoldenManifests && !suiteCtx.UpdateGoldenManifests {
+		return
+	}
+	manifests, err := CaptureManifests(suiteCtx, ctx)
+	Expect(err).ToNot(HaveOccurred())
+
+	goldenFile := ManifestsFile(suiteCtx, ctx)
+	diff, err := Compare(goldenFile, manifests, suiteCtx.UpdateGoldenManifests)
+	Expect(err).ToNot(HaveOccurred())
+	if diff == "" {
+		log.Info("Generated manifests match the golden file", "file", goldenFile)
+		return
+	}
+
+	actualFile := utils.SuiteProjectDir + "/tests-logs/" + suiteCtx.SuiteID + "/golden/" + ctx.RegistryNamespace + "-" + ctx.RegistryName + ".yaml"
+	os.MkdirAll(utils.SuiteProjectDir+"/tests-logs/"+suiteCtx.SuiteID+"/golden/", os.ModePerm)
+	if err := ioutil.WriteFile(actualFile, manifests, 0644); err != nil {
+		log.Error(err, "Error saving generated manifests")
+	}
+	Expect(fmt.Errorf("manifests generated by the operator differ from the golden file, generated ones saved in %v, "+
+		"run with -update-golden-manifests if the change is intended\n%v", actualFile, diff)).ToNot(HaveOccurred())
+}
+
+//CaptureManifests reads the objects the operator generated for the registry of the test context and renders them normalized, as yaml
+func CaptureManifests(suiteCtx *types.SuiteContext, ctx *types.TestContext) ([]byte, error) {
+	registry := &apicurio.ApicurioRegistry{}
+	err := suiteCtx.K8sClient.Get(context.TODO(), client.ObjectKey{Namespace: ctx.RegistryNamespace, Name: ctx.RegistryName}, registry)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := apicurioutils.GetManagedObjects(suiteCtx, registry)
+	if err != nil {
+		return nil, err
+	}
+
+	captured := []client.Object{objects.Deployment, objects.Service}
+	if objects.Ingress != nil {
+		captured = append(captured, objects.Ingress)
+	}
+	routeName := ""
+	if suiteCtx.IsOpenshift {
+		route := &routev1.Route{}
+		found, err := apicurioutils.GetManagedObject(suiteCtx, registry, route, &routev1.RouteList{})
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			captured = append(captured, route)
+			//the route openshift creates for the ingress has a generated name
+			if route.GenerateName != "" {
+				routeName = route.Name
+			}
+		}
+	}
+	if objects.PodDisruptionBudget != nil {
+		captured = append(captured, objects.PodDisruptionBudget)
+	}
+	if objects.NetworkPolicy != nil {
+		captured = append(captured, objects.NetworkPolicy)
+	}
+
+	replacements := []Replacement{
+		{Placeholder: "NAMESPACE", Value: ctx.RegistryNamespace},
+		{Placeholder: "REGISTRY_HOST", Value: ctx.RegistryHost},
+		{Placeholder: "REGISTRY_VERSION", Value: objects.Deployment.Labels[registryVersionLabel]},
+		{Placeholder: "ROUTE_NAME", Value: routeName},
+	}
+	if containers := objects.Deployment.Spec.Template.Spec.Containers; len(containers) != 0 {
+		replacements = append(replacements, Replacement{Placeholder: "REGISTRY_IMAGE", Value: containers[0].Image})
+	}
+
+	documents := []map[string]interface{}{}
+	for _, obj := range captured {
+		//typed objects read through the client have no apiVersion and kind, the pod disruption budget keeps the version the cluster serves
+		obj = obj.DeepCopyObject().(client.Object)
+		if obj.GetObjectKind().GroupVersionKind().Empty() {
+			gvk, err := apiutil.GVKForObject(obj, scheme.Scheme)
+			if err != nil {
+				return nil, err
+			}
+			obj.GetObjectKind().SetGroupVersionKind(gvk)
+		}
+		document, err := Normalize(obj, replacements)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+	return Render(documents)
+}
@@ -7,150 +7,29 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
-	"time"
 
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/apimachinery/pkg/util/wait"
-	"k8s.io/client-go/kubernetes"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils"
-	apicurioutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio"
+	kubernetesutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetes"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetescli"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/timeouts"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
-
-	apicurio "github.com/Apicurio/apicurio-registry-operator/api/v1"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/waiter"
 )
 
 var log = logf.Log.WithName("kafkasql")
 
 var bundlePath string = utils.StrimziOperatorBundlePath
 
-func KafkaSqlDeployResource(suiteCtx *types.SuiteContext, ctx *types.TestContext) *apicurio.ApicurioRegistry {
-	name := ctx.RegistryName
-	if name == "" {
-		name = "apicurio-registry-" + ctx.Storage
-	}
-
-	kafkaNodes := 3
-	if ctx.Size == types.SmallSize {
-		kafkaNodes = 1
-	}
-
-	kafkaRequest := &CreateKafkaClusterRequest{
-		Name:           "kafka-" + name,
-		Namespace:      ctx.RegistryNamespace,
-		ExposeExternal: false,
-		Replicas:       kafkaNodes,
-		Topics:         []string{},
-		Security:       string(ctx.KafkaSecurity),
-	}
-
-	kafkaClusterInfo := DeployKafkaCluster(suiteCtx, kafkaRequest)
-
-	ctx.KafkaClusterInfo = kafkaClusterInfo
-
-	bootstrapServers := kafkaClusterInfo.BootstrapServers
-
-	log.Info("Deploying apicurio registry")
-
-	replicas := 1
-	if ctx.Replicas > 0 {
-		replicas = ctx.Replicas
-	}
-
-	registry := apicurio.ApicurioRegistry{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: name,
-		},
-		Spec: apicurio.ApicurioRegistrySpec{
-			Configuration: apicurio.ApicurioRegistrySpecConfiguration{
-				LogLevel:    "DEBUG",
-				Persistence: utils.StorageKafkaSql,
-				Kafkasql: apicurio.ApicurioRegistrySpecConfigurationKafkasql{
-					BootstrapServers: bootstrapServers,
-				},
-			},
-			Deployment: apicurio.ApicurioRegistrySpecDeployment{
-				Replicas: int32(replicas),
-			},
-		},
-	}
-
-	if ctx.KafkaSecurity == types.Tls {
-		truststoreSecret := kafkaRequest.Name + "-cluster-ca-truststore"
-		keystoreSecret := kafkaClusterInfo.Username + "-keystore"
-
-		scriptFile := utils.SuiteProjectDir + "/scripts/kafka/create_cert_stores.sh"
-		err := utils.ExecuteCmd(true, &utils.Command{
-			Env: []string{
-				"CLUSTER_CA_CERT_SECRET=" + kafkaRequest.Name + "-cluster-ca-cert",
-				"CLIENT_CERT_SECRET=" + kafkaClusterInfo.Username,
-				"TRUSTSTORE_SECRET=" + truststoreSecret,
-				"KEYSTORE_SECRET=" + keystoreSecret,
-				"HOSTNAME=" + kafkaRequest.Name + "-kafka-bootstrap",
-				"NAMESPACE=" + ctx.RegistryNamespace,
-				"K8S_CMD=" + string(kubernetescli.GetCLIKubernetesClient().Cmd),
-			},
-			Cmd: []string{scriptFile},
-		})
-		Expect(err).ToNot(HaveOccurred())
-		registry.Spec.Configuration.Kafkasql.Security.Tls.KeystoreSecretName = keystoreSecret
-		registry.Spec.Configuration.Kafkasql.Security.Tls.TruststoreSecretName = truststoreSecret
-
-		defer utils.ExecuteCmd(true, &utils.Command{Cmd: []string{utils.SuiteProjectDir + "/scripts/kafka/clean_certs.sh"}})
-
-	} else if ctx.KafkaSecurity == types.Scram {
-		truststoreSecret := kafkaRequest.Name + "-cluster-ca-truststore"
-
-		scriptFile := utils.SuiteProjectDir + "/scripts/kafka/create_cert_stores.sh"
-		err := utils.ExecuteCmd(true, &utils.Command{
-			Env: []string{
-				"CLUSTER_CA_CERT_SECRET=" + kafkaRequest.Name + "-cluster-ca-cert",
-				"TRUSTSTORE_SECRET=" + truststoreSecret,
-				"NAMESPACE=" + ctx.RegistryNamespace,
-				"K8S_CMD=" + string(kubernetescli.GetCLIKubernetesClient().Cmd),
-			},
-			Cmd: []string{scriptFile},
-		})
-		Expect(err).ToNot(HaveOccurred())
-		registry.Spec.Configuration.Kafkasql.Security.Scram.TruststoreSecretName = truststoreSecret
-		registry.Spec.Configuration.Kafkasql.Security.Scram.PasswordSecretName = kafkaClusterInfo.Username
-		registry.Spec.Configuration.Kafkasql.Security.Scram.User = kafkaClusterInfo.Username
-
-		defer utils.ExecuteCmd(true, &utils.Command{Cmd: []string{utils.SuiteProjectDir + "/scripts/kafka/clean_certs.sh"}})
-
-	}
-
-	return &registry
-}
-
-//RemoveKafkaSqlRegistry uninstalls registry CR, kafka cluster and strimzi operator
-func RemoveKafkaSqlRegistry(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
-
-	apicurioutils.DeleteRegistryAndWait(suiteCtx, ctx.RegistryNamespace, ctx.RegistryName)
-
-	if ctx.KafkaSecurity == types.Tls {
-		kubernetescli.Execute("delete", "secret", ctx.KafkaClusterInfo.Name+"-cluster-ca-truststore", "-n", ctx.RegistryNamespace)
-		kubernetescli.Execute("delete", "secret", ctx.KafkaClusterInfo.Username+"-keystore", "-n", ctx.RegistryNamespace)
-	} else if ctx.KafkaSecurity == types.Scram {
-		kubernetescli.Execute("delete", "secret", ctx.KafkaClusterInfo.Name+"-cluster-ca-truststore", "-n", ctx.RegistryNamespace)
-	}
-
-	RemoveKafkaCluster(suiteCtx.Clientset, ctx.RegistryNamespace, ctx.KafkaClusterInfo)
-
-	if ctx.SkipInfraRemoval {
-		log.Info("Skipping removal of strimzi operator")
-	} else {
-		defer os.Remove(bundlePath)
-		RemoveStrimziOperator(suiteCtx.Clientset, ctx.RegistryNamespace)
-	}
-
-}
-
 type CreateKafkaClusterRequest struct {
 	Namespace      string
 	Replicas       int
@@ -158,6 +37,8 @@ type CreateKafkaClusterRequest struct {
 	Name           string
 	Topics         []string
 	Security       string
+	//Size resources of the kafka brokers and zookeeper, the number of brokers is Replicas
+	Size types.DeploymentSize
 }
 
 //DeployKafkaCluster deploys a kafka cluster and some topics, returns a flag to indicate if strimzi operator has been deployed(useful to know if it was already installed)
@@ -174,7 +55,7 @@ func DeployKafkaClusterV2(suiteCtx *types.SuiteContext, namespace string, replic
 
 func DeployKafkaCluster(suiteCtx *types.SuiteContext, req *CreateKafkaClusterRequest) *types.KafkaClusterInfo {
 
-	strimziDeployed := deployStrimziOperator(suiteCtx.Clientset, req.Namespace)
+	strimziDeployed := deployStrimziOperator(suiteCtx, req.Namespace)
 
 	clusterInfo := &types.KafkaClusterInfo{StrimziDeployed: strimziDeployed}
 
@@ -195,6 +76,7 @@ func DeployKafkaCluster(suiteCtx *types.SuiteContext, req *CreateKafkaClusterReq
 	if req.Replicas > 1 {
 		minisr = "2"
 	}
+	resources := resourcesReplacements(req.Size.Profile())
 	var kafkaClusterManifest string = ""
 	kindBoostrapHost := "bootstrap.127.0.0.1.nip.io"
 	if req.ExposeExternal {
@@ -204,34 +86,43 @@ func DeployKafkaCluster(suiteCtx *types.SuiteContext, req *CreateKafkaClusterReq
 			template = "kafka-cluster-external-ocp-template.yaml"
 		}
 
-		kafkaClusterManifestFile := utils.Template("kafka-cluster",
-			utils.SuiteProjectDir+"/kubefiles/"+template,
+		replacings := append(resources,
 			utils.Replacement{Old: "{NAMESPACE}", New: req.Namespace},
 			utils.Replacement{Old: "{NAME}", New: req.Name},
 			utils.Replacement{Old: "{BOOTSTRAP_HOST}", New: kindBoostrapHost},
 			utils.Replacement{Old: "{BROKER_HOST}", New: brokerHost},
 		)
+		kafkaClusterManifestFile := utils.Template("kafka-cluster",
+			utils.SuiteProjectDir+"/kubefiles/"+template,
+			replacings...,
+		)
 		kafkaClusterManifest = kafkaClusterManifestFile.Name()
 	} else {
 
 		if req.Security == "" {
-			kafkaClusterManifestFile := utils.Template("kafka-cluster",
-				utils.SuiteProjectDir+"/kubefiles/kafka-cluster-template.yaml",
+			replacings := append(resources,
 				utils.Replacement{Old: "{NAMESPACE}", New: req.Namespace},
 				utils.Replacement{Old: "{NAME}", New: req.Name},
 				utils.Replacement{Old: "{REPLICAS}", New: replicasStr},
 				utils.Replacement{Old: "{MIN_ISR}", New: minisr},
 			)
+			kafkaClusterManifestFile := utils.Template("kafka-cluster",
+				utils.SuiteProjectDir+"/kubefiles/kafka-cluster-template.yaml",
+				replacings...,
+			)
 			kafkaClusterManifest = kafkaClusterManifestFile.Name()
 		} else if req.Security == "tls" || req.Security == "scram" {
-			kafkaClusterManifestFile := utils.Template("kafka-cluster",
-				utils.SuiteProjectDir+"/kubefiles/kafka-cluster-secured-template.yaml",
+			replacings := append(resources,
 				utils.Replacement{Old: "{NAMESPACE}", New: req.Namespace},
 				utils.Replacement{Old: "{NAME}", New: req.Name},
 				utils.Replacement{Old: "{REPLICAS}", New: replicasStr},
 				utils.Replacement{Old: "{MIN_ISR}", New: minisr},
 				utils.Replacement{Old: "{AUTH_TYPE}", New: clusterInfo.AuthType},
 			)
+			kafkaClusterManifestFile := utils.Template("kafka-cluster",
+				utils.SuiteProjectDir+"/kubefiles/kafka-cluster-secured-template.yaml",
+				replacings...,
+			)
 			kafkaClusterManifest = kafkaClusterManifestFile.Name()
 		} else {
 			Expect(errors.NewBadRequest("uknown security method")).NotTo(HaveOccurred())
@@ -271,22 +162,10 @@ func DeployKafkaCluster(suiteCtx *types.SuiteContext, req *CreateKafkaClusterReq
 		kubernetescli.Execute("apply", "-f", kafkaUserFile.Name(), "-n", req.Namespace)
 	}
 
-	//wait for kafka cluster
-	//TODO make this timeout configurable
-	timeout := 10 * time.Minute
-	log.Info("Waiting for kafka cluster to be ready ", "timeout", timeout)
-	err := wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		od, err := suiteCtx.Clientset.AppsV1().Deployments(req.Namespace).Get(context.TODO(), req.Name+"-entity-operator", metav1.GetOptions{})
-		if err != nil && !errors.IsNotFound(err) {
-			return false, err
-		}
-		if od != nil {
-			if od.Status.AvailableReplicas > int32(0) {
-				return true, nil
-			}
-		}
-		return false, nil
-	})
+	//wait for kafka cluster, the entity operator is deployed once the brokers are ready
+	record, err := suiteCtx.Waiter.Timeout(timeouts.Get(timeouts.KafkaClusterReady)).Describe("kafka cluster "+req.Name+" to be ready").
+		ForObject(client.ObjectKey{Namespace: req.Namespace, Name: req.Name + "-entity-operator"}, &appsv1.Deployment{}, kubernetesutils.DeploymentAvailable(0))
+	waiter.LogOnError(record, err)
 	kubernetescli.GetDeployments(req.Namespace)
 	kubernetescli.GetPods(req.Namespace)
 	kubernetescli.GetVolumes(req.Namespace)
@@ -294,18 +173,9 @@ func DeployKafkaCluster(suiteCtx *types.SuiteContext, req *CreateKafkaClusterReq
 
 	if req.Security == "tls" || req.Security == "scram" {
 		//wait for required cluster ca secret
-		timeout := 1 * time.Minute
-		log.Info("Waiting for kafka cluster CA secret to be created ", "timeout", timeout)
-		err := wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-			_, err := suiteCtx.Clientset.CoreV1().Secrets(req.Namespace).Get(context.TODO(), req.Name+"-cluster-ca-cert", metav1.GetOptions{})
-			if err != nil {
-				if errors.IsNotFound(err) {
-					return false, nil
-				}
-				return false, err
-			}
-			return true, nil
-		})
+		record, err := suiteCtx.Waiter.Timeout(timeouts.Get(timeouts.KafkaCASecret)).Describe("kafka cluster CA secret to be created").
+			ForObject(client.ObjectKey{Namespace: req.Namespace, Name: req.Name + "-cluster-ca-cert"}, &corev1.Secret{}, waiter.Exists)
+		waiter.LogOnError(record, err)
 		kubernetescli.Execute("get", "secret", "-n", req.Namespace)
 		Expect(err).ToNot(HaveOccurred())
 	}
@@ -331,6 +201,20 @@ func DeployKafkaCluster(suiteCtx *types.SuiteContext, req *CreateKafkaClusterReq
 	return clusterInfo
 }
 
+//resourcesReplacements replacements of the resources placeholders of the kafka cluster templates
+func resourcesReplacements(profile types.ResourceProfile) []utils.Replacement {
+	return []utils.Replacement{
+		{Old: "{KAFKA_CPU_REQUEST}", New: profile.Kafka.Requests.Cpu().String()},
+		{Old: "{KAFKA_MEMORY_REQUEST}", New: profile.Kafka.Requests.Memory().String()},
+		{Old: "{KAFKA_CPU_LIMIT}", New: profile.Kafka.Limits.Cpu().String()},
+		{Old: "{KAFKA_MEMORY_LIMIT}", New: profile.Kafka.Limits.Memory().String()},
+		{Old: "{ZOOKEEPER_CPU_REQUEST}", New: profile.Zookeeper.Requests.Cpu().String()},
+		{Old: "{ZOOKEEPER_MEMORY_REQUEST}", New: profile.Zookeeper.Requests.Memory().String()},
+		{Old: "{ZOOKEEPER_CPU_LIMIT}", New: profile.Zookeeper.Limits.Cpu().String()},
+		{Old: "{ZOOKEEPER_MEMORY_LIMIT}", New: profile.Zookeeper.Limits.Memory().String()},
+	}
+}
+
 func DeployKafkaConnect(suiteCtx *types.SuiteContext, kafkaClusterInfo *types.KafkaClusterInfo, image string, convertersPlugin types.KafkaConnectPlugin) {
 
 	var replicasStr string = strconv.Itoa(kafkaClusterInfo.Replicas)
@@ -354,29 +238,13 @@ func DeployKafkaConnect(suiteCtx *types.SuiteContext, kafkaClusterInfo *types.Ka
 	log.Info("Deploying kafka connect " + kafkaClusterInfo.Name)
 	kubernetescli.Execute("apply", "-f", kafkaClusterManifest, "-n", kafkaClusterInfo.Namespace)
 
-	//TODO finish this
-
-	//wait for kafka connect
-	//TODO make this timeout configurable
-	timeout := 10 * time.Minute
-	log.Info("Waiting for kafka connect to be ready ", "timeout", timeout)
-	// err := wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-	// 	od, err := suiteCtx.Clientset.AppsV1().Deployments(kafkaClusterInfo.Namespace).Get(context.TODO(), req.Name+"-entity-operator", metav1.GetOptions{})
-	// 	if err != nil && !errors.IsNotFound(err) {
-	// 		return false, err
-	// 	}
-	// 	if od != nil {
-	// 		if od.Status.AvailableReplicas > int32(0) {
-	// 			return true, nil
-	// 		}
-	// 	}
-	// 	return false, nil
-	// })
-	time.Sleep(180 * time.Second)
+	record, err := suiteCtx.Waiter.Timeout(timeouts.Get(timeouts.KafkaConnectReady)).Describe("kafka connect "+kafkaClusterInfo.Name+" to be ready").
+		ForObject(client.ObjectKey{Namespace: kafkaClusterInfo.Namespace, Name: kafkaClusterInfo.Name}, kafkaConnect(), isStrimziResourceReady)
+	waiter.LogOnError(record, err)
 	kubernetescli.GetDeployments(kafkaClusterInfo.Namespace)
 	kubernetescli.GetPods(kafkaClusterInfo.Namespace)
 	kubernetescli.Execute("get", "ingress", "-n", kafkaClusterInfo.Namespace)
-	// Expect(err).ToNot(HaveOccurred())
+	Expect(err).ToNot(HaveOccurred())
 
 }
 
@@ -385,29 +253,46 @@ func RemoveKafkaConnect(suiteCtx *types.SuiteContext, kafkaClusterInfo *types.Ka
 
 	kubernetescli.Execute("delete", "kafkaconnect", kafkaClusterInfo.Name, "-n", kafkaClusterInfo.Namespace)
 
-	timeout := 120 * time.Second
-	log.Info("Waiting for kafka connect to be removed ", "timeout", timeout)
-	// err := wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-	// 	labelsSet := labels.Set(map[string]string{"strimzi.io/cluster": kafkaClusterInfo.Name})
-	// 	l, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: labelsSet.AsSelector().String()})
-	// 	if err != nil {
-	// 		if errors.IsNotFound(err) {
-	// 			return true, nil
-	// 		}
-	// 		return false, err
-	// 	}
-	// 	return len(l.Items) == 0, nil
-	// })
+	//kafka connect is named after the kafka cluster, its pods share the cluster label with the brokers
+	record, err := suiteCtx.Waiter.Timeout(timeouts.Get(timeouts.KafkaConnectRemoval)).Describe("kafka connect "+kafkaClusterInfo.Name+" pods to be removed").
+		ForList(&corev1.PodList{}, waiter.Empty, client.InNamespace(kafkaClusterInfo.Namespace),
+			client.MatchingLabels{"strimzi.io/cluster": kafkaClusterInfo.Name, "strimzi.io/kind": "KafkaConnect"})
+	waiter.LogOnError(record, err)
 	kubernetescli.GetDeployments(kafkaClusterInfo.Namespace)
 	kubernetescli.GetStatefulSets(kafkaClusterInfo.Namespace)
 	kubernetescli.GetPods(kafkaClusterInfo.Namespace)
 	kubernetescli.GetVolumes(kafkaClusterInfo.Namespace)
-	// Expect(err).ToNot(HaveOccurred())
+	Expect(err).ToNot(HaveOccurred())
+}
+
+//kafkaConnect strimzi KafkaConnect, the testsuite is not built with the strimzi api so it's read as unstructured content
+func kafkaConnect() *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(schema.GroupVersionKind{Group: "kafka.strimzi.io", Version: "v1beta2", Kind: "KafkaConnect"})
+	return u
+}
+
+//isStrimziResourceReady holds once strimzi reports the Ready condition of the resource
+func isStrimziResourceReady(obj client.Object, exists bool) (bool, error) {
+	if !exists {
+		return false, nil
+	}
+	conditions, _, err := unstructured.NestedSlice(obj.(*unstructured.Unstructured).Object, "status", "conditions")
+	if err != nil {
+		return false, err
+	}
+	for _, c := range conditions {
+		condition, isMap := c.(map[string]interface{})
+		if isMap && condition["type"] == "Ready" {
+			return condition["status"] == "True", nil
+		}
+	}
+	return false, nil
 }
 
-func deployStrimziOperator(clientset *kubernetes.Clientset, namespace string) bool {
+func deployStrimziOperator(suiteCtx *types.SuiteContext, namespace string) bool {
 
-	_, err := clientset.AppsV1().Deployments(namespace).Get(context.TODO(), "strimzi-cluster-operator", metav1.GetOptions{})
+	_, err := suiteCtx.Clientset.AppsV1().Deployments(namespace).Get(context.TODO(), "strimzi-cluster-operator", metav1.GetOptions{})
 	if err != nil && !errors.IsNotFound(err) {
 		Expect(err).ToNot(HaveOccurred())
 	} else if err == nil {
@@ -440,27 +325,16 @@ func deployStrimziOperator(clientset *kubernetes.Clientset, namespace string) bo
 	kubernetescli.Execute("create", "-f", bundlePath, "-n", namespace)
 
 	// sh("oc wait deployment/strimzi-cluster-operator --for condition=available --timeout=180s")
-	timeout := 180 * time.Second
-	log.Info("Waiting for strimzi operator to be ready ", "timeout", timeout)
-	err = wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		od, err := clientset.AppsV1().Deployments(namespace).Get(context.TODO(), "strimzi-cluster-operator", metav1.GetOptions{})
-		if err != nil && !errors.IsNotFound(err) {
-			return false, err
-		}
-		if od != nil {
-			if od.Status.AvailableReplicas > int32(0) {
-				return true, nil
-			}
-		}
-		return false, nil
-	})
+	record, err := suiteCtx.Waiter.Timeout(timeouts.Get(timeouts.StrimziOperatorReady)).Describe("strimzi operator to be ready").
+		ForObject(client.ObjectKey{Namespace: namespace, Name: "strimzi-cluster-operator"}, &appsv1.Deployment{}, kubernetesutils.DeploymentAvailable(0))
+	waiter.LogOnError(record, err)
 	kubernetescli.GetPods(namespace)
 	Expect(err).ToNot(HaveOccurred())
 	return true
 }
 
 //RemoveKafkaCluster removes a kafka cluster
-func RemoveKafkaCluster(clientset *kubernetes.Clientset, namespace string, kafkaClusterInfo *types.KafkaClusterInfo) {
+func RemoveKafkaCluster(suiteCtx *types.SuiteContext, namespace string, kafkaClusterInfo *types.KafkaClusterInfo) {
 
 	log.Info("Removing kafka cluster")
 
@@ -473,19 +347,9 @@ func RemoveKafkaCluster(clientset *kubernetes.Clientset, namespace string, kafka
 		kubernetescli.Execute("delete", "kafkauser", kafkaClusterInfo.Username, "-n", namespace)
 	}
 
-	timeout := 120 * time.Second
-	log.Info("Waiting for kafka cluster to be removed ", "timeout", timeout)
-	err := wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		labelsSet := labels.Set(map[string]string{"strimzi.io/cluster": kafkaClusterInfo.Name})
-		l, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: labelsSet.AsSelector().String()})
-		if err != nil {
-			if errors.IsNotFound(err) {
-				return true, nil
-			}
-			return false, err
-		}
-		return len(l.Items) == 0, nil
-	})
+	record, err := suiteCtx.Waiter.Timeout(timeouts.Get(timeouts.KafkaClusterRemoval)).Describe("kafka cluster "+kafkaClusterInfo.Name+" pods to be removed").
+		ForList(&corev1.PodList{}, waiter.Empty, client.InNamespace(namespace), client.MatchingLabels{"strimzi.io/cluster": kafkaClusterInfo.Name})
+	waiter.LogOnError(record, err)
 	kubernetescli.GetDeployments(namespace)
 	kubernetescli.GetStatefulSets(namespace)
 	kubernetescli.GetPods(namespace)
@@ -495,22 +359,13 @@ func RemoveKafkaCluster(clientset *kubernetes.Clientset, namespace string, kafka
 }
 
 //RemoveStrimziOperator uninstalls strimzi operator
-func RemoveStrimziOperator(clientset *kubernetes.Clientset, namespace string) {
+func RemoveStrimziOperator(suiteCtx *types.SuiteContext, namespace string) {
 	log.Info("Removing strimzi operator")
 	kubernetescli.Execute("delete", "-f", bundlePath, "-n", namespace)
 
-	timeout := 120 * time.Second
-	log.Info("Waiting for strimzi cluster operator to be removed ", "timeout", timeout)
-	err := wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		_, err := clientset.AppsV1().Deployments(namespace).Get(context.TODO(), "strimzi-cluster-operator", metav1.GetOptions{})
-		if err != nil {
-			if errors.IsNotFound(err) {
-				return true, nil
-			}
-			return false, err
-		}
-		return false, nil
-	})
+	record, err := suiteCtx.Waiter.Timeout(timeouts.Get(timeouts.StrimziOperatorRemoval)).Describe("strimzi cluster operator to be removed").
+		ForObject(client.ObjectKey{Namespace: namespace, Name: "strimzi-cluster-operator"}, &appsv1.Deployment{}, waiter.Deleted)
+	waiter.LogOnError(record, err)
 	kubernetescli.GetDeployments(namespace)
 	kubernetescli.GetPods(namespace)
 	Expect(err).ToNot(HaveOccurred())
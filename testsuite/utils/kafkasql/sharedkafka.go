@@ -25,7 +25,7 @@ func DeploySharedKafkaIfNeeded(suiteCtx *types.SuiteContext, ctx *types.TestCont
 func RemoveSharedKafkaIfNeeded(suiteCtx *types.SuiteContext, ctx *types.TestContext, kafkaCluster *types.KafkaClusterInfo) {
 	if isSharedKafkaNeeded(suiteCtx) && kafkaCluster != nil {
 		log.Info("Removing Shared Kafka cluster for tests")
-		RemoveKafkaCluster(suiteCtx.Clientset, ctx.RegistryNamespace, kafkaCluster)
+		RemoveKafkaCluster(suiteCtx, ctx.RegistryNamespace, kafkaCluster)
 	}
 }
 
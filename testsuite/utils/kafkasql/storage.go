@@ -0,0 +1,156 @@
+package kafkasql
+
+import (
+	"os"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils"
+	kubernetesutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetes"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetescli"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+
+	apicurio "github.com/Apicurio/apicurio-registry-operator/api/v1"
+)
+
+//StorageProvider kafkasql storage, the registry is deployed with a kafka cluster managed by strimzi in its namespace,
+//secured with tls or scram if the test context says so
+type StorageProvider struct{}
+
+func (p *StorageProvider) Name() string {
+	return utils.StorageKafkaSql
+}
+
+func (p *StorageProvider) ResourceDemands(ctx *types.TestContext) []kubernetesutils.ResourceDemand {
+	profile := ctx.Size.Profile()
+	return []kubernetesutils.ResourceDemand{
+		{Name: "kafka", Pods: profile.KafkaNodes, Requests: profile.Kafka.Requests},
+		{Name: "zookeeper", Pods: 1, Requests: profile.Zookeeper.Requests},
+	}
+}
+
+//Deploy deploys the kafka cluster and, for secured clusters, the secrets with the certificate stores the registry uses to connect to it
+func (p *StorageProvider) Deploy(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
+	kafkaRequest := &CreateKafkaClusterRequest{
+		Name:           "kafka-" + ctx.RegistryName,
+		Namespace:      ctx.RegistryNamespace,
+		ExposeExternal: false,
+		Replicas:       ctx.Size.Profile().KafkaNodes,
+		Topics:         []string{},
+		Security:       string(ctx.KafkaSecurity),
+		Size:           ctx.Size,
+	}
+
+	kafkaClusterInfo := DeployKafkaCluster(suiteCtx, kafkaRequest)
+
+	ctx.KafkaClusterInfo = kafkaClusterInfo
+
+	if ctx.KafkaSecurity == types.Tls {
+		scriptFile := utils.SuiteProjectDir + "/scripts/kafka/create_cert_stores.sh"
+		err := utils.ExecuteCmd(true, &utils.Command{
+			Env: []string{
+				"CLUSTER_CA_CERT_SECRET=" + kafkaRequest.Name + "-cluster-ca-cert",
+				"CLIENT_CERT_SECRET=" + kafkaClusterInfo.Username,
+				"TRUSTSTORE_SECRET=" + truststoreSecret(kafkaClusterInfo),
+				"KEYSTORE_SECRET=" + keystoreSecret(kafkaClusterInfo),
+				"HOSTNAME=" + kafkaRequest.Name + "-kafka-bootstrap",
+				"NAMESPACE=" + ctx.RegistryNamespace,
+				"K8S_CMD=" + string(kubernetescli.GetCLIKubernetesClient().Cmd),
+			},
+			Cmd: []string{scriptFile},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		defer utils.ExecuteCmd(true, &utils.Command{Cmd: []string{utils.SuiteProjectDir + "/scripts/kafka/clean_certs.sh"}})
+
+	} else if ctx.KafkaSecurity == types.Scram {
+		scriptFile := utils.SuiteProjectDir + "/scripts/kafka/create_cert_stores.sh"
+		err := utils.ExecuteCmd(true, &utils.Command{
+			Env: []string{
+				"CLUSTER_CA_CERT_SECRET=" + kafkaRequest.Name + "-cluster-ca-cert",
+				"TRUSTSTORE_SECRET=" + truststoreSecret(kafkaClusterInfo),
+				"NAMESPACE=" + ctx.RegistryNamespace,
+				"K8S_CMD=" + string(kubernetescli.GetCLIKubernetesClient().Cmd),
+			},
+			Cmd: []string{scriptFile},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		defer utils.ExecuteCmd(true, &utils.Command{Cmd: []string{utils.SuiteProjectDir + "/scripts/kafka/clean_certs.sh"}})
+
+	}
+}
+
+func (p *StorageProvider) RegistryResource(suiteCtx *types.SuiteContext, ctx *types.TestContext) *apicurio.ApicurioRegistry {
+	Expect(ctx.KafkaClusterInfo).ToNot(BeNil(), "kafka cluster not deployed")
+
+	registry := apicurio.ApicurioRegistry{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ctx.RegistryName,
+		},
+		Spec: apicurio.ApicurioRegistrySpec{
+			Configuration: apicurio.ApicurioRegistrySpecConfiguration{
+				LogLevel:    "DEBUG",
+				Persistence: utils.StorageKafkaSql,
+				Kafkasql: apicurio.ApicurioRegistrySpecConfigurationKafkasql{
+					BootstrapServers: ctx.KafkaClusterInfo.BootstrapServers,
+				},
+			},
+			Deployment: apicurio.ApicurioRegistrySpecDeployment{
+				Replicas: ctx.RegistryReplicas(),
+			},
+		},
+	}
+
+	if ctx.KafkaSecurity == types.Tls {
+		registry.Spec.Configuration.Kafkasql.Security.Tls.KeystoreSecretName = keystoreSecret(ctx.KafkaClusterInfo)
+		registry.Spec.Configuration.Kafkasql.Security.Tls.TruststoreSecretName = truststoreSecret(ctx.KafkaClusterInfo)
+	} else if ctx.KafkaSecurity == types.Scram {
+		registry.Spec.Configuration.Kafkasql.Security.Scram.TruststoreSecretName = truststoreSecret(ctx.KafkaClusterInfo)
+		registry.Spec.Configuration.Kafkasql.Security.Scram.PasswordSecretName = ctx.KafkaClusterInfo.Username
+		registry.Spec.Configuration.Kafkasql.Security.Scram.User = ctx.KafkaClusterInfo.Username
+	}
+
+	return &registry
+}
+
+//Remove removes the certificate stores, the kafka cluster and the strimzi operator
+func (p *StorageProvider) Remove(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
+	if ctx.KafkaClusterInfo == nil {
+		log.Info("Kafka cluster not deployed, nothing to remove")
+		return
+	}
+
+	if ctx.KafkaSecurity == types.Tls {
+		kubernetescli.Execute("delete", "secret", truststoreSecret(ctx.KafkaClusterInfo), "-n", ctx.RegistryNamespace)
+		kubernetescli.Execute("delete", "secret", keystoreSecret(ctx.KafkaClusterInfo), "-n", ctx.RegistryNamespace)
+	} else if ctx.KafkaSecurity == types.Scram {
+		kubernetescli.Execute("delete", "secret", truststoreSecret(ctx.KafkaClusterInfo), "-n", ctx.RegistryNamespace)
+	}
+
+	RemoveKafkaCluster(suiteCtx, ctx.RegistryNamespace, ctx.KafkaClusterInfo)
+
+	if ctx.SkipInfraRemoval {
+		log.Info("Skipping removal of strimzi operator")
+	} else {
+		defer os.Remove(bundlePath)
+		RemoveStrimziOperator(suiteCtx, ctx.RegistryNamespace)
+	}
+}
+
+//Diagnostics saves the kafka cluster, topics and users as strimzi sees them, their status shows why a cluster isn't ready
+func (p *StorageProvider) Diagnostics(suiteCtx *types.SuiteContext, ctx *types.TestContext, dir string) {
+	file, err := os.Create(dir + "kafka.log")
+	Expect(err).ToNot(HaveOccurred())
+	defer file.Close()
+	kubernetescli.RedirectOutput(file, os.Stderr, "get", "kafkas,kafkatopics,kafkausers", "-n", ctx.RegistryNamespace, "-o", "yaml")
+}
+
+func truststoreSecret(kafkaClusterInfo *types.KafkaClusterInfo) string {
+	return kafkaClusterInfo.Name + "-cluster-ca-truststore"
+}
+
+func keystoreSecret(kafkaClusterInfo *types.KafkaClusterInfo) string {
+	return kafkaClusterInfo.Username + "-keystore"
+}
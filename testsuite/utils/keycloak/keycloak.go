@@ -3,14 +3,13 @@ package keycloak
 import (
 	"context"
 	"path/filepath"
-	"time"
 
 	. "github.com/onsi/gomega"
-	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	networking "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -18,9 +17,12 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils"
+	kubernetesutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetes"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetescli"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/olm"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/timeouts"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/waiter"
 
 	apicurio "github.com/Apicurio/apicurio-registry-operator/api/v1"
 	operatorsv1 "github.com/operator-framework/api/pkg/operators/v1"
@@ -49,23 +51,20 @@ func DeployKeycloak(suiteCtx *types.SuiteContext, ctx *types.TestContext) string
 	ctx.KeycloakSubscription = keycloakSub
 	ctx.KeycloakOperatorGroup = og
 
+	profile := ctx.Size.Profile()
+	kubernetesutils.ValidateClusterCapacity(suiteCtx, "keycloak",
+		kubernetesutils.ResourceDemand{Name: "keycloak", Pods: 1, Requests: profile.Keycloak.Requests})
+
 	log.Info("Deploying keycloak server")
-	kubernetescli.Execute("apply", "-f", filepath.Join(utils.SuiteProjectDir, "/kubefiles/keycloak/keycloak.yaml"), "-n", ctx.RegistryNamespace)
+	kubernetescli.Execute("apply", "-f", keycloakManifest(profile), "-n", ctx.RegistryNamespace)
 
-	timeout := 13 * time.Minute
+	timeout := timeouts.Get(timeouts.KeycloakReady)
 	log.Info("Waiting for keycloak server to be ready ", "timeout", timeout)
-	err := wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		od, err := suiteCtx.Clientset.AppsV1().StatefulSets(ctx.RegistryNamespace).Get(context.TODO(), "keycloak", metav1.GetOptions{})
-		if err != nil && !errors.IsNotFound(err) {
-			return false, err
-		}
-		if od != nil {
-			if od.Status.ReadyReplicas > int32(0) {
-				return true, nil
-			}
-		}
-		return false, nil
-	})
+	record, err := suiteCtx.Waiter.Timeout(timeout).Describe("keycloak server to be ready").
+		ForObject(client.ObjectKey{Namespace: ctx.RegistryNamespace, Name: "keycloak"}, &appsv1.StatefulSet{}, func(obj client.Object, exists bool) (bool, error) {
+			return exists && obj.(*appsv1.StatefulSet).Status.ReadyReplicas > 0, nil
+		})
+	waiter.LogOnError(record, err)
 	kubernetescli.GetPods(ctx.RegistryNamespace)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -83,18 +82,14 @@ func DeployKeycloak(suiteCtx *types.SuiteContext, ctx *types.TestContext) string
 	if suiteCtx.IsOpenshift {
 		kubernetescli.Execute("get", "route", "-n", ctx.RegistryNamespace)
 
-		timeout := 90 * time.Second
+		timeout := timeouts.Get(timeouts.KeycloakRoute)
 		log.Info("Waiting for keycloak route to be ready", "timeout", timeout)
-		err = wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-			route, err := suiteCtx.OcpRouteClient.Routes(ctx.RegistryNamespace).Get(context.TODO(), keycloakHttp, metav1.GetOptions{})
-			if err != nil {
-				if errors.IsNotFound(err) {
-					return false, nil
-				}
-				return false, err
-			}
-			return len(route.Status.Ingress) != 0, nil
-		})
+		record, err := suiteCtx.Waiter.Timeout(timeout).Describe("keycloak route to be ready").
+			ForObject(client.ObjectKey{Namespace: ctx.RegistryNamespace, Name: keycloakHttp}, &routev1.Route{}, func(obj client.Object, exists bool) (bool, error) {
+				return exists && len(obj.(*routev1.Route).Status.Ingress) != 0, nil
+			})
+		waiter.LogOnError(record, err)
+		Expect(err).NotTo(HaveOccurred())
 
 		httpRoute, err := suiteCtx.OcpRouteClient.Routes(ctx.RegistryNamespace).Get(context.TODO(), keycloakHttp, metav1.GetOptions{})
 		Expect(err).NotTo(HaveOccurred())
@@ -113,20 +108,13 @@ func RemoveKeycloak(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
 	kubernetescli.Execute("delete", "-f", filepath.Join(utils.SuiteProjectDir, "/kubefiles/keycloak/keycloak-realm.yaml"), "-n", ctx.RegistryNamespace)
 
 	log.Info("Removing keycloak server")
-	kubernetescli.Execute("delete", "-f", filepath.Join(utils.SuiteProjectDir, "/kubefiles/keycloak/keycloak.yaml"), "-n", ctx.RegistryNamespace)
+	kubernetescli.Execute("delete", "-f", keycloakManifest(ctx.Size.Profile()), "-n", ctx.RegistryNamespace)
 
-	timeout := 3 * time.Minute
+	timeout := timeouts.Get(timeouts.KeycloakRemoval)
 	log.Info("Waiting for keycloak server to be deleted ", "timeout", timeout)
-	err := wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		_, err := suiteCtx.Clientset.AppsV1().StatefulSets(ctx.RegistryNamespace).Get(context.TODO(), "keycloak", metav1.GetOptions{})
-		if err != nil {
-			if errors.IsNotFound(err) {
-				return true, nil
-			}
-			return false, err
-		}
-		return false, nil
-	})
+	record, err := suiteCtx.Waiter.Timeout(timeout).Describe("keycloak server to be deleted").
+		ForObject(client.ObjectKey{Namespace: ctx.RegistryNamespace, Name: "keycloak"}, &appsv1.StatefulSet{}, waiter.Deleted)
+	waiter.LogOnError(record, err)
 	kubernetescli.GetPods(ctx.RegistryNamespace)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -144,6 +132,17 @@ func RemoveKeycloak(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
 	removeKeycloakOperator(suiteCtx, ctx.RegistryNamespace, ctx.KeycloakSubscription, ctx.KeycloakOperatorGroup)
 }
 
+//keycloakManifest the keycloak server manifest with the resources of the profile
+func keycloakManifest(profile types.ResourceProfile) string {
+	return utils.Template("keycloak",
+		filepath.Join(utils.SuiteProjectDir, "/kubefiles/keycloak/keycloak-template.yaml"),
+		utils.Replacement{Old: "{CPU_REQUEST}", New: profile.Keycloak.Requests.Cpu().String()},
+		utils.Replacement{Old: "{MEMORY_REQUEST}", New: profile.Keycloak.Requests.Memory().String()},
+		utils.Replacement{Old: "{CPU_LIMIT}", New: profile.Keycloak.Limits.Cpu().String()},
+		utils.Replacement{Old: "{MEMORY_LIMIT}", New: profile.Keycloak.Limits.Memory().String()},
+	).Name()
+}
+
 func installKeycloakOperator(suiteCtx *types.SuiteContext, namespace string) (*operatorsv1alpha1.Subscription, *operatorsv1.OperatorGroup) {
 
 	var operatorGroupName string = namespace + "-operator-group"
@@ -205,20 +204,11 @@ func installKeycloakOperator(suiteCtx *types.SuiteContext, namespace string) (*o
 	// kubernetescli.Execute("apply", "-n", namespace, "-f", filepath.Join(operatorDir, "deploy/service_account.yaml"))
 	// kubernetescli.Execute("apply", "-n", namespace, "-f", filepath.Join(operatorDir, "deploy/operator.yaml"))
 
-	timeout := 180 * time.Second
+	timeout := timeouts.Get(timeouts.KeycloakOperatorReady)
 	log.Info("Waiting for keycloak operator to be ready ", "timeout", timeout)
-	err = wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		od, err := suiteCtx.Clientset.AppsV1().Deployments(namespace).Get(context.TODO(), "keycloak-operator", metav1.GetOptions{})
-		if err != nil && !errors.IsNotFound(err) {
-			return false, err
-		}
-		if od != nil {
-			if od.Status.AvailableReplicas > int32(0) {
-				return true, nil
-			}
-		}
-		return false, nil
-	})
+	record, err := suiteCtx.Waiter.Timeout(timeout).Describe("keycloak operator to be ready").
+		ForObject(client.ObjectKey{Namespace: namespace, Name: "keycloak-operator"}, &appsv1.Deployment{}, kubernetesutils.DeploymentAvailable(0))
+	waiter.LogOnError(record, err)
 	kubernetescli.GetPods(namespace)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -242,18 +232,11 @@ func removeKeycloakOperator(suiteCtx *types.SuiteContext, namespace string, sub
 	// kubernetescli.Execute("delete", "-n", namespace, "-f", filepath.Join(operatorDir, "deploy/role.yaml"))
 	// kubernetescli.Execute("delete", "-n", namespace, "-f", filepath.Join(operatorDir, "deploy/crds/"))
 
-	timeout := 120 * time.Second
+	timeout := timeouts.Get(timeouts.KeycloakOperatorRemoval)
 	log.Info("Waiting for keycloak operator to be removed ", "timeout", timeout)
-	err := wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		_, err := suiteCtx.Clientset.AppsV1().Deployments(namespace).Get(context.TODO(), "keycloak-operator", metav1.GetOptions{})
-		if err != nil {
-			if errors.IsNotFound(err) {
-				return true, nil
-			}
-			return false, err
-		}
-		return false, nil
-	})
+	record, err := suiteCtx.Waiter.Timeout(timeout).Describe("keycloak operator to be removed").
+		ForObject(client.ObjectKey{Namespace: namespace, Name: "keycloak-operator"}, &appsv1.Deployment{}, waiter.Deleted)
+	waiter.LogOnError(record, err)
 	kubernetescli.GetPods(namespace)
 	Expect(err).ToNot(HaveOccurred())
 }
@@ -0,0 +1,195 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+)
+
+//checkedResources resources compared against the allocatable capacity of the cluster
+var checkedResources []corev1.ResourceName = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+//ResourceDemand pods about to be deployed and the resources each one of them requests
+type ResourceDemand struct {
+	Name     string
+	Pods     int
+	Requests corev1.ResourceList
+}
+
+//NodeCapacity allocatable resources of a node not requested yet by the pods running in it
+type NodeCapacity struct {
+	Name string
+	Free corev1.ResourceList
+}
+
+//ClusterCapacity free capacity of the nodes pods can be scheduled to
+type ClusterCapacity struct {
+	Nodes []NodeCapacity
+}
+
+//ValidateClusterCapacity fails the test if the cluster doesn't have enough free allocatable cpu and memory for the demands,
+//so a deployment too big for the cluster fails right away instead of timing out waiting for pending pods
+func ValidateClusterCapacity(suiteCtx *types.SuiteContext, description string, demands ...ResourceDemand) {
+	if suiteCtx.DisableCapacityCheck {
+		return
+	}
+	capacity, err := GetClusterCapacity(suiteCtx.Clientset)
+	Expect(err).ToNot(HaveOccurred())
+	problems := capacity.Problems(demands)
+	if len(problems) != 0 {
+		Expect(fmt.Errorf("not enough cluster capacity to deploy %v: %v. Use a smaller deployment size, a bigger cluster or run with -disable-capacity-check",
+			description, strings.Join(problems, "; "))).ToNot(HaveOccurred())
+	}
+	log.Info("Cluster has enough capacity", "deployment", description)
+}
+
+//GetClusterCapacity reads the free capacity of every schedulable node
+func GetClusterCapacity(clientset kubernetes.Interface) (*ClusterCapacity, error) {
+	nodes, err := clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pods, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(),
+		metav1.ListOptions{FieldSelector: "status.phase!=" + string(corev1.PodSucceeded) + ",status.phase!=" + string(corev1.PodFailed)})
+	if err != nil {
+		return nil, err
+	}
+	return freeCapacity(nodes.Items, pods.Items), nil
+}
+
+//freeCapacity allocatable resources of the schedulable nodes minus the requests of the pods assigned to them
+func freeCapacity(nodes []corev1.Node, pods []corev1.Pod) *ClusterCapacity {
+	capacity := &ClusterCapacity{}
+	free := map[string]corev1.ResourceList{}
+	for _, node := range nodes {
+		if !isSchedulable(&node) {
+			continue
+		}
+		free[node.Name] = node.Status.Allocatable.DeepCopy()
+		capacity.Nodes = append(capacity.Nodes, NodeCapacity{Name: node.Name, Free: free[node.Name]})
+	}
+	for _, pod := range pods {
+		available, exists := free[pod.Spec.NodeName]
+		if !exists || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		requests := podRequests(&pod)
+		for _, name := range checkedResources {
+			quantity := available[name]
+			quantity.Sub(requests[name])
+			available[name] = quantity
+		}
+	}
+	return capacity
+}
+
+//isSchedulable false for cordoned nodes, nodes not ready and nodes tainted so the testsuite pods don't run on them, like control plane nodes
+func isSchedulable(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect == corev1.TaintEffectNoSchedule || taint.Effect == corev1.TaintEffectNoExecute {
+			return false
+		}
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+//podRequests resources the scheduler reserves for the pod, init containers run one at a time before the containers
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, c := range pod.Spec.Containers {
+		for name, quantity := range c.Resources.Requests {
+			total := requests[name]
+			total.Add(quantity)
+			requests[name] = total
+		}
+	}
+	for _, c := range pod.Spec.InitContainers {
+		for name, quantity := range c.Resources.Requests {
+			if current := requests[name]; quantity.Cmp(current) > 0 {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	for name, quantity := range pod.Spec.Overhead {
+		total := requests[name]
+		total.Add(quantity)
+		requests[name] = total
+	}
+	return requests
+}
+
+//Problems why the demands don't fit in the cluster, empty if they do.
+//Checks the total requested against the total free capacity and that every pod fits in at least one node on its own,
+//the scheduler may still not find a place for all of them but a missing capacity is found before deploying anything
+func (c *ClusterCapacity) Problems(demands []ResourceDemand) []string {
+	problems := []string{}
+	if len(c.Nodes) == 0 {
+		return append(problems, "no schedulable nodes")
+	}
+	for _, name := range checkedResources {
+		requested := resource.Quantity{}
+		for _, d := range demands {
+			for i := 0; i < d.Pods; i++ {
+				requested.Add(d.Requests[name])
+			}
+		}
+		free := resource.Quantity{}
+		for _, n := range c.Nodes {
+			if quantity := n.Free[name]; quantity.Sign() > 0 {
+				free.Add(quantity)
+			}
+		}
+		if requested.Cmp(free) > 0 {
+			problems = append(problems, fmt.Sprintf("%v requested %v, free %v in %v nodes", name, requested.String(), free.String(), len(c.Nodes)))
+		}
+	}
+	for _, d := range demands {
+		if d.Pods > 0 && !c.fitsInANode(d.Requests) {
+			problems = append(problems, fmt.Sprintf("no node has %v free for a %v pod", resourcesString(d.Requests), d.Name))
+		}
+	}
+	return problems
+}
+
+func (c *ClusterCapacity) fitsInANode(requests corev1.ResourceList) bool {
+	for _, n := range c.Nodes {
+		fits := true
+		for _, name := range checkedResources {
+			free := n.Free[name]
+			if quantity, exists := requests[name]; exists && quantity.Cmp(free) > 0 {
+				fits = false
+			}
+		}
+		if fits {
+			return true
+		}
+	}
+	return false
+}
+
+func resourcesString(resources corev1.ResourceList) string {
+	parts := []string{}
+	for _, name := range checkedResources {
+		if quantity, exists := resources[name]; exists {
+			parts = append(parts, string(name)+" "+quantity.String())
+		}
+	}
+	return strings.Join(parts, " and ")
+}
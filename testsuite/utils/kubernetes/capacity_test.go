@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+)
+
+func resourceList(cpu string, memory string) corev1.ResourceList {
+	return corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(memory)}
+}
+
+func readyNode(name string, cpu string, memory string) corev1.Node {
+	n := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	n.Status.Allocatable = resourceList(cpu, memory)
+	n.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}
+	return n
+}
+
+func podOn(nodeName string, phase corev1.PodPhase, requests ...corev1.ResourceList) corev1.Pod {
+	p := corev1.Pod{Spec: corev1.PodSpec{NodeName: nodeName}, Status: corev1.PodStatus{Phase: phase}}
+	for _, r := range requests {
+		p.Spec.Containers = append(p.Spec.Containers, corev1.Container{Resources: corev1.ResourceRequirements{Requests: r}})
+	}
+	return p
+}
+
+func nodeFree(capacity *ClusterCapacity, nodeName string) corev1.ResourceList {
+	for _, n := range capacity.Nodes {
+		if n.Name == nodeName {
+			return n.Free
+		}
+	}
+	return nil
+}
+
+func TestFreeCapacitySubtractsActivePodRequestsFromSchedulableNodes(t *testing.T) {
+	g := NewWithT(t)
+	cordoned := readyNode("cordoned", "4", "8Gi")
+	cordoned.Spec.Unschedulable = true
+	controlPlane := readyNode("control-plane", "4", "8Gi")
+	controlPlane.Spec.Taints = []corev1.Taint{{Key: "node-role.kubernetes.io/master", Effect: corev1.TaintEffectNoSchedule}}
+	notReady := readyNode("not-ready", "4", "8Gi")
+	notReady.Status.Conditions[0].Status = corev1.ConditionFalse
+
+	withInit := podOn("worker", corev1.PodRunning, resourceList("100m", "128Mi"))
+	withInit.Spec.InitContainers = []corev1.Container{{Resources: corev1.ResourceRequirements{Requests: resourceList("1", "64Mi")}}}
+
+	capacity := freeCapacity(
+		[]corev1.Node{readyNode("worker", "4", "8Gi"), cordoned, controlPlane, notReady},
+		[]corev1.Pod{
+			podOn("worker", corev1.PodRunning, resourceList("500m", "1Gi"), resourceList("250m", "512Mi")),
+			podOn("worker", corev1.PodPending, resourceList("250m", "512Mi")),
+			podOn("worker", corev1.PodSucceeded, resourceList("2", "4Gi")),
+			podOn("control-plane", corev1.PodRunning, resourceList("2", "4Gi")),
+			withInit,
+		})
+
+	g.Expect(capacity.Nodes).To(HaveLen(1))
+	worker := nodeFree(capacity, "worker")
+	g.Expect(worker.Cpu().String()).To(Equal("2"))
+	g.Expect(worker.Memory().String()).To(Equal("6016Mi"))
+}
+
+func TestReportsDemandsOverTheTotalFreeCapacity(t *testing.T) {
+	g := NewWithT(t)
+	capacity := &ClusterCapacity{Nodes: []NodeCapacity{
+		{Name: "a", Free: resourceList("1", "1536Mi")},
+		{Name: "b", Free: resourceList("1", "1536Mi")},
+	}}
+	profile := types.NormalSize.Profile()
+	kafka := ResourceDemand{Name: "kafka", Pods: profile.KafkaNodes, Requests: profile.Kafka.Requests}
+	registry := ResourceDemand{Name: "registry", Pods: 1, Requests: profile.Registry.Requests}
+
+	g.Expect(capacity.Problems([]ResourceDemand{registry})).To(BeEmpty())
+	g.Expect(capacity.Problems([]ResourceDemand{kafka, registry})).To(Equal([]string{"memory requested 3584Mi, free 3Gi in 2 nodes"}))
+	g.Expect(capacity.Problems([]ResourceDemand{kafka, registry, registry})).To(Equal([]string{
+		"cpu requested 2500m, free 2 in 2 nodes",
+		"memory requested 4Gi, free 3Gi in 2 nodes",
+	}))
+}
+
+func TestReportsPodsNoNodeHasRoomFor(t *testing.T) {
+	g := NewWithT(t)
+	capacity := &ClusterCapacity{Nodes: []NodeCapacity{
+		{Name: "a", Free: resourceList("1", "2Gi")},
+		{Name: "b", Free: resourceList("1", "2Gi")},
+	}}
+	big := ResourceDemand{Name: "big", Pods: 1, Requests: resourceList("1500m", "1Gi")}
+
+	g.Expect(capacity.Problems([]ResourceDemand{big})).To(Equal([]string{"no node has cpu 1500m and memory 1Gi free for a big pod"}))
+	g.Expect((&ClusterCapacity{}).Problems([]ResourceDemand{big})).To(Equal([]string{"no schedulable nodes"}))
+}
@@ -6,16 +6,20 @@ import (
 
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetescli"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/timeouts"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/waiter"
 	. "github.com/onsi/gomega"
 )
 
@@ -37,27 +41,27 @@ func IsOCP(config *rest.Config) (bool, error) {
 	return true, nil
 }
 
-func CreateNamespace(clientset *kubernetes.Clientset, namespace string) error {
+func CreateNamespace(suiteCtx *types.SuiteContext, namespace string) error {
 	log.Info("Creating namespace", "name", namespace)
-	_, err := clientset.CoreV1().Namespaces().Create(context.TODO(), &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}, metav1.CreateOptions{})
+	_, err := suiteCtx.Clientset.CoreV1().Namespaces().Create(context.TODO(), &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}, metav1.CreateOptions{})
 	if err == nil && utils.ImagePullSecretUser != "" {
 		//create pull secret
 		log.Info("Creating image pull secret", "name", utils.ImagePullSecretName)
 		kubernetescli.ExecuteCmd(false, "create", "secret", "-n", namespace, "docker-registry", utils.ImagePullSecretName, "--docker-username="+utils.ImagePullSecretUser, "--docker-password="+utils.ImagePullSecretPassword, "--docker-server="+utils.ImagePullSecretServer)
-		SetPullSecret(clientset, "default", namespace)
+		SetPullSecret(suiteCtx, "default", namespace)
 	}
 	return err
 }
 
 //CreateTestNamespace creates one namespace with the given name
-func CreateTestNamespace(clientset *kubernetes.Clientset, namespace string) {
-	err := CreateNamespace(clientset, namespace)
+func CreateTestNamespace(suiteCtx *types.SuiteContext, namespace string) {
+	err := CreateNamespace(suiteCtx, namespace)
 	Expect(err).ToNot(HaveOccurred())
 }
 
 //DeleteTestNamespace removes one namespace and waits until it's deleted
-func DeleteTestNamespace(clientset *kubernetes.Clientset, namespace string) {
-	ns, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+func DeleteTestNamespace(suiteCtx *types.SuiteContext, namespace string) {
+	ns, err := suiteCtx.Clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
 			log.Info("Namespace not found, doing nothing", "name", namespace)
@@ -67,62 +71,27 @@ func DeleteTestNamespace(clientset *kubernetes.Clientset, namespace string) {
 	}
 	if ns != nil {
 		log.Info("Removing namespace", "name", namespace)
-		err = clientset.CoreV1().Namespaces().Delete(context.TODO(), namespace, metav1.DeleteOptions{})
+		err = suiteCtx.Clientset.CoreV1().Namespaces().Delete(context.TODO(), namespace, metav1.DeleteOptions{})
 		Expect(err).ToNot(HaveOccurred())
-		timeout := 60 * time.Second
-		log.Info("Waiting for namespace to be removed", "timeout", timeout)
-		err := wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-			od, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
-			if err != nil {
-				if errors.IsNotFound(err) {
-					return true, nil
-				}
-				return false, err
-			}
-			if od != nil {
-				return false, nil
-			}
-			return true, nil
-		})
+		record, err := suiteCtx.Waiter.Timeout(timeouts.Get(timeouts.NamespaceRemoval)).Describe("namespace "+namespace+" to be removed").
+			ForObject(client.ObjectKey{Name: namespace}, &v1.Namespace{}, waiter.Deleted)
+		waiter.LogOnError(record, err)
 		Expect(err).ToNot(HaveOccurred())
 	}
 }
 
-func WaitForOperatorDeploymentReady(clientset *kubernetes.Clientset, namespace string, operatorDeploymentName string) {
-	timeout := 500 * time.Second
-	log.Info("Waiting for operator to be deployed", "timeout", timeout)
-	err := wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		od, err := clientset.AppsV1().Deployments(namespace).Get(context.TODO(), operatorDeploymentName, metav1.GetOptions{})
-		if err != nil && !errors.IsNotFound(err) {
-			return false, err
-		}
-		if od != nil {
-			if od.Status.AvailableReplicas > int32(0) {
-				return true, nil
-			}
-		}
-		return false, nil
-	})
+func WaitForOperatorDeploymentReady(suiteCtx *types.SuiteContext, namespace string, operatorDeploymentName string) {
+	record, err := suiteCtx.Waiter.Timeout(timeouts.Get(timeouts.OperatorReady)).Describe("operator to be deployed").
+		ForObject(client.ObjectKey{Namespace: namespace, Name: operatorDeploymentName}, &appsv1.Deployment{}, DeploymentAvailable(0))
+	waiter.LogOnError(record, err)
 	kubernetescli.GetPods(namespace)
 	Expect(err).ToNot(HaveOccurred())
 }
 
-func WaitForOperatorDeploymentRemoved(clientset *kubernetes.Clientset, namespace string, operatorDeploymentName string) {
-	timeout := 60 * time.Second
-	log.Info("Waiting for operator to be removed", "timeout", timeout)
-	err := wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		od, err := clientset.AppsV1().Deployments(namespace).Get(context.TODO(), operatorDeploymentName, metav1.GetOptions{})
-		if err != nil {
-			if errors.IsNotFound(err) {
-				return true, nil
-			}
-			return false, err
-		}
-		if od != nil {
-			return false, nil
-		}
-		return true, nil
-	})
+func WaitForOperatorDeploymentRemoved(suiteCtx *types.SuiteContext, namespace string, operatorDeploymentName string) {
+	record, err := suiteCtx.Waiter.Timeout(timeouts.Get(timeouts.OperatorRemoval)).Describe("operator to be removed").
+		ForObject(client.ObjectKey{Namespace: namespace, Name: operatorDeploymentName}, &appsv1.Deployment{}, waiter.Deleted)
+	waiter.LogOnError(record, err)
 	kubernetescli.GetPods(namespace)
 	Expect(err).ToNot(HaveOccurred())
 }
@@ -141,61 +110,60 @@ func isOperatorDeployed(clientset *kubernetes.Clientset, namespace string, opera
 	return false, nil
 }
 
-func WaitForDeploymentReady(clientset *kubernetes.Clientset, timeout time.Duration, namespace string, deploymentName string, expectedReplicas int) {
+func WaitForDeploymentReady(suiteCtx *types.SuiteContext, timeout time.Duration, namespace string, deploymentName string, expectedReplicas int) {
 	if expectedReplicas == 0 {
 		expectedReplicas = 1
 	}
-	// timeout := 120 * time.Second
-	log.Info("Waiting for deployment "+deploymentName+" to be ready ", "timeout", timeout)
-	err := wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		od, err := clientset.AppsV1().Deployments(namespace).Get(context.TODO(), deploymentName, metav1.GetOptions{})
-		if err != nil && !errors.IsNotFound(err) {
-			return false, err
-		}
-		if od != nil {
-			if od.Status.AvailableReplicas == int32(expectedReplicas) {
-				return true, nil
-			}
-		}
-		return false, nil
-	})
+	record, err := suiteCtx.Waiter.Timeout(timeout).Describe("deployment "+deploymentName+" to be ready").
+		ForObject(client.ObjectKey{Namespace: namespace, Name: deploymentName}, &appsv1.Deployment{}, DeploymentAvailable(expectedReplicas))
+	waiter.LogOnError(record, err)
 	kubernetescli.GetPods(namespace)
 	Expect(err).ToNot(HaveOccurred())
 }
 
-func WaitForObjectDeleted(name string, apiCall func() (interface{}, error)) {
-	timeout := 30 * time.Second
-	log.Info("Waiting for "+name+" to be removed ", "timeout", timeout)
-	err := wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		_, err := apiCall()
-		if err != nil {
-			if errors.IsNotFound(err) {
-				return true, nil
-			}
-			return false, err
+//DeploymentAvailable holds once the deployment has exactly the expected available replicas, or any available replica if expectedReplicas is 0
+func DeploymentAvailable(expectedReplicas int) waiter.ObjectPredicate {
+	return func(obj client.Object, exists bool) (bool, error) {
+		if !exists {
+			return false, nil
 		}
-		return false, nil
-	})
-	Expect(err).ToNot(HaveOccurred())
+		available := obj.(*appsv1.Deployment).Status.AvailableReplicas
+		if expectedReplicas == 0 {
+			return available > 0, nil
+		}
+		return available == int32(expectedReplicas), nil
+	}
 }
 
-func SetPullSecret(clientset *kubernetes.Clientset, serviceAccount string, namespace string) {
-	timeout := 10 * time.Second
-	err := wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		_, err := clientset.CoreV1().ServiceAccounts(namespace).Get(context.TODO(), serviceAccount, metav1.GetOptions{})
-		if err != nil {
-			if errors.IsNotFound(err) {
-				return false, nil
-			}
-			return false, err
+//IsPodReady true if the pod is running, not being deleted, and its readiness probes pass
+func IsPodReady(pod *v1.Pod) bool {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase != v1.PodRunning {
+		return false
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == v1.PodReady {
+			return c.Status == v1.ConditionTrue
 		}
-		return true, nil
-	})
+	}
+	return false
+}
+
+//WaitForObjectDeleted waits until the object with the given key is not found
+func WaitForObjectDeleted(suiteCtx *types.SuiteContext, key client.ObjectKey, obj client.Object) {
+	record, err := suiteCtx.Waiter.Timeout(timeouts.Get(timeouts.ObjectRemoval)).ForObject(key, obj, waiter.Deleted)
+	waiter.LogOnError(record, err)
+	Expect(err).ToNot(HaveOccurred())
+}
+
+func SetPullSecret(suiteCtx *types.SuiteContext, serviceAccount string, namespace string) {
+	record, err := suiteCtx.Waiter.Timeout(timeouts.Get(timeouts.ServiceAccount)).
+		ForObject(client.ObjectKey{Namespace: namespace, Name: serviceAccount}, &v1.ServiceAccount{}, waiter.Exists)
+	waiter.LogOnError(record, err)
 	Expect(err).ToNot(HaveOccurred())
 	log.Info("Binding pull secret to service account", "name", serviceAccount)
-	sa, err := clientset.CoreV1().ServiceAccounts(namespace).Get(context.TODO(), serviceAccount, metav1.GetOptions{})
+	sa, err := suiteCtx.Clientset.CoreV1().ServiceAccounts(namespace).Get(context.TODO(), serviceAccount, metav1.GetOptions{})
 	Expect(err).ToNot(HaveOccurred())
 	sa.ImagePullSecrets = append(sa.ImagePullSecrets, v1.LocalObjectReference{Name: utils.ImagePullSecretName})
-	_, err = clientset.CoreV1().ServiceAccounts(namespace).Update(context.TODO(), sa, metav1.UpdateOptions{})
+	_, err = suiteCtx.Clientset.CoreV1().ServiceAccounts(namespace).Update(context.TODO(), sa, metav1.UpdateOptions{})
 	Expect(err).ToNot(HaveOccurred())
 }
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	policy "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+)
+
+//podDisruptionBudgetVersions policy/v1 replaced policy/v1beta1, clusters from kubernetes 1.25 only serve policy/v1 and older ones only policy/v1beta1.
+//The api module the testsuite is built with has no policy/v1 types, so budgets are read as unstructured content of the version the cluster serves
+var podDisruptionBudgetVersions []string = []string{"v1", "v1beta1"}
+
+//PodDisruptionBudgetGVK newest version of the PodDisruptionBudget kind the cluster serves, resolved through discovery
+func PodDisruptionBudgetGVK(suiteCtx *types.SuiteContext) (schema.GroupVersionKind, error) {
+	mapping, err := suiteCtx.K8sManager.GetRESTMapper().RESTMapping(schema.GroupKind{Group: policy.GroupName, Kind: "PodDisruptionBudget"}, podDisruptionBudgetVersions...)
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+	return mapping.GroupVersionKind, nil
+}
+
+//UnstructuredObject empty object of the kind, for kinds the testsuite has no types for
+func UnstructuredObject(gvk schema.GroupVersionKind) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	return obj
+}
+
+//UnstructuredList empty list of objects of the kind
+func UnstructuredList(gvk schema.GroupVersionKind) *unstructured.UnstructuredList {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	return list
+}
+
+//ToPodDisruptionBudget converts a budget of any served version to the policy/v1beta1 type, both versions have the same fields but the ones
+//added to policy/v1 later, which are dropped. The apiVersion of the budget is kept
+func ToPodDisruptionBudget(obj *unstructured.Unstructured) (*policy.PodDisruptionBudget, error) {
+	pdb := &policy.PodDisruptionBudget{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, pdb); err != nil {
+		return nil, err
+	}
+	return pdb, nil
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestToPodDisruptionBudgetConvertsPolicyV1Budgets(t *testing.T) {
+	g := NewWithT(t)
+	gvk := schema.GroupVersionKind{Group: "policy", Version: "v1", Kind: "PodDisruptionBudget"}
+	obj := UnstructuredObject(gvk)
+	obj.SetName("registry-pdb")
+	obj.Object["spec"] = map[string]interface{}{
+		"maxUnavailable":             int64(1),
+		"selector":                   map[string]interface{}{"matchLabels": map[string]interface{}{"app": "registry"}},
+		"unhealthyPodEvictionPolicy": "AlwaysAllow",
+	}
+
+	pdb, err := ToPodDisruptionBudget(obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(pdb.APIVersion).To(Equal("policy/v1"))
+	g.Expect(pdb.Name).To(Equal("registry-pdb"))
+	g.Expect(*pdb.Spec.MaxUnavailable).To(Equal(intstr.FromInt(1)))
+	g.Expect(pdb.Spec.Selector.MatchLabels).To(Equal(map[string]string{"app": "registry"}))
+
+	g.Expect(UnstructuredList(gvk).GetKind()).To(Equal("PodDisruptionBudgetList"))
+}
@@ -80,6 +80,16 @@ func PrintSeparator() {
 }
 
 func SaveLogs(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
+	SaveTestPodsLogs(suiteCtx.Clientset, suiteCtx.SuiteID, ctx.RegistryNamespace, testName(ctx))
+}
+
+//TestLogsDir directory the logs of the running test are saved in
+func TestLogsDir(suiteCtx *types.SuiteContext, ctx *types.TestContext) string {
+	return utils.SuiteProjectDir + "/tests-logs/" + suiteCtx.SuiteID + "/" + testName(ctx) + "/"
+}
+
+//testName name of the running test, the ginkgo containers hierarchy followed by the test context id
+func testName(ctx *types.TestContext) string {
 	testDescription := CurrentSpecReport()
 
 	testName := ""
@@ -97,8 +107,7 @@ func SaveLogs(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
 			testName += ("-" + ctx.ID)
 		}
 	}
-
-	SaveTestPodsLogs(suiteCtx.Clientset, suiteCtx.SuiteID, ctx.RegistryNamespace, testName)
+	return testName
 }
 
 //SaveTestPodsLogs stores logs of all pods in OperatorNamespace
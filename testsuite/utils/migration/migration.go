@@ -1,12 +1,26 @@
 package migration
 
 import (
+	"strings"
+
+	. "github.com/onsi/gomega"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	apicurioclient "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/client"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/deploy"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/snapshot"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/functional"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
 )
 
-//DataMigrationTestcase runs migration profile in Functional testsuite, this profile tests the import/export functionalities of the Apicurio Registry API
+var log = logf.Log.WithName("migration")
+
+var migrationGroups []string = []string{"migration-a", "migration-b"}
+
+var avroSchemaV2 string = "{\"type\":\"record\",\"name\":\"price\",\"namespace\":\"com.example\",\"fields\":[{\"name\":\"symbol\",\"type\":\"string\"},{\"name\":\"price\",\"type\":\"string\"},{\"name\":\"currency\",\"type\":\"string\",\"default\":\"EUR\"}]}"
+
+//DataMigrationTestcase exports the data of one registry and imports it into another one, using the admin API, then verifies both registries hold the same data
 func DataMigrationTestcase(suiteCtx *types.SuiteContext, testContext *types.TestContext) {
 
 	sourceCtx := &types.TestContext{
@@ -37,17 +51,138 @@ func DataMigrationTestcase(suiteCtx *types.SuiteContext, testContext *types.Test
 	})
 	deploy.DeployRegistryStorage(suiteCtx, destCtx)
 
-	migrationTestsCtx := &types.TestContext{
-		ID:                     testContext.ID,
-		Storage:                testContext.Storage,
-		FunctionalTestsProfile: "migration",
-		FunctionalTestsExtraEnv: []string{
-			"SOURCE_REGISTRY_HOST=" + sourceCtx.RegistryHost,
-			"SOURCE_REGISTRY_PORT=" + sourceCtx.RegistryPort,
-			"DEST_REGISTRY_HOST=" + destCtx.RegistryHost,
-			"DEST_REGISTRY_PORT=" + destCtx.RegistryPort,
-		},
+	functional.BasicRegistryAPITest(sourceCtx)
+	functional.BasicRegistryAPITest(destCtx)
+
+	sourceClient := functional.RegistryClient(sourceCtx, nil)
+	destClient := functional.RegistryClient(destCtx, nil)
+
+	seedMigrationData(sourceClient)
+
+	log.Info("Exporting source registry data")
+	data, err := sourceClient.ExportData()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(data).ToNot(BeEmpty())
+
+	log.Info("Importing data into destination registry", "bytes", len(data))
+	err = destClient.ImportData(data)
+	Expect(err).ToNot(HaveOccurred())
+
+	Expect(snapshot.Diff(takeSnapshot(sourceClient), takeSnapshot(destClient))).To(BeEmpty())
+	log.Info("Successful data migration verification")
+}
+
+//BackupAndRestoreTestcase exports the data of a registry, removes the registry and its storage, and imports the data into a brand new registry
+func BackupAndRestoreTestcase(suiteCtx *types.SuiteContext, testContext *types.TestContext) {
+
+	backupCtx := &types.TestContext{
+		ID:                "backup-" + testContext.ID,
+		Storage:           testContext.Storage,
+		RegistryName:      "backup-registry",
+		RegistryNamespace: testContext.RegistryNamespace,
+		Size:              types.SmallSize,
+		//so strimzi operator is removed by the restore registry cleanup
+		SkipInfraRemoval: true,
 	}
 
-	functional.ExecuteRegistryFunctionalTests(suiteCtx, migrationTestsCtx)
+	backupRemoved := false
+	testContext.RegisterCleanup(func() {
+		if !backupRemoved {
+			deploy.RemoveRegistryDeployment(suiteCtx, backupCtx)
+		}
+	})
+	deploy.DeployRegistryStorage(suiteCtx, backupCtx)
+	functional.BasicRegistryAPITest(backupCtx)
+
+	backupClient := functional.RegistryClient(backupCtx, nil)
+	seedMigrationData(backupClient)
+	expected := takeSnapshot(backupClient)
+
+	log.Info("Creating registry backup")
+	backup, err := backupClient.ExportData()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(backup).ToNot(BeEmpty())
+
+	log.Info("Removing backed up registry and its storage")
+	deploy.RemoveRegistryDeployment(suiteCtx, backupCtx)
+	backupRemoved = true
+
+	restoreCtx := &types.TestContext{
+		ID:                "restore-" + testContext.ID,
+		Storage:           testContext.Storage,
+		RegistryName:      "restore-registry",
+		RegistryNamespace: testContext.RegistryNamespace,
+		Size:              types.SmallSize,
+	}
+	testContext.RegisterCleanup(func() {
+		deploy.RemoveRegistryDeployment(suiteCtx, restoreCtx)
+	})
+	deploy.DeployRegistryStorage(suiteCtx, restoreCtx)
+	functional.BasicRegistryAPITest(restoreCtx)
+
+	restoreClient := functional.RegistryClient(restoreCtx, nil)
+	artifacts, err := restoreClient.ListArtifacts()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(artifacts).To(BeEmpty())
+
+	log.Info("Restoring registry backup", "bytes", len(backup))
+	err = restoreClient.ImportData(backup)
+	Expect(err).ToNot(HaveOccurred())
+
+	Expect(snapshot.Diff(expected, takeSnapshot(restoreClient))).To(BeEmpty())
+	log.Info("Successful backup and restore verification")
+}
+
+//seedMigrationData creates one artifact of every type spread across groups, with metadata, multiple versions, version states and rules
+func seedMigrationData(client apicurioclient.ApicurioRegistryApiClient) {
+	log.Info("Creating migration test data")
+
+	err := client.CreateGlobalRule(&apicurioclient.Rule{Type: apicurioclient.ValidityRule, Config: "SYNTAX_ONLY"})
+	Expect(err).ToNot(HaveOccurred())
+
+	for i, artifactType := range apicurioclient.ArtifactTypes {
+		content, err := apicurioclient.SampleContent(artifactType)
+		Expect(err).ToNot(HaveOccurred())
+
+		groupId := migrationGroups[i%len(migrationGroups)]
+		artifactId := "migration-" + strings.ToLower(string(artifactType))
+		_, err = client.CreateArtifactInGroup(&apicurioclient.CreateArtifactRequest{
+			GroupId:      groupId,
+			ArtifactId:   artifactId,
+			ArtifactType: artifactType,
+			Version:      "1.0.0",
+			Content:      content,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		err = client.UpdateArtifactMetaData(groupId, artifactId, &apicurioclient.EditableMetaData{
+			Name:        artifactId,
+			Description: "migration test artifact of type " + string(artifactType),
+			Labels:      []string{"migration", strings.ToLower(string(artifactType))},
+			Properties:  map[string]string{"origin": "source"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	avroGroup := migrationGroups[0]
+	avroId := "migration-" + strings.ToLower(string(apicurioclient.Avro))
+	err = client.CreateArtifactRule(avroGroup, avroId, &apicurioclient.Rule{Type: apicurioclient.CompatibilityRule, Config: "BACKWARD"})
+	Expect(err).ToNot(HaveOccurred())
+	_, err = client.CreateArtifactVersion(&apicurioclient.CreateVersionRequest{
+		GroupId:      avroGroup,
+		ArtifactId:   avroId,
+		ArtifactType: apicurioclient.Avro,
+		Version:      "2.0.0",
+		Content:      avroSchemaV2,
+	})
+	Expect(err).ToNot(HaveOccurred())
+	err = client.UpdateArtifactVersionState(avroGroup, avroId, "1.0.0", apicurioclient.Deprecated)
+	Expect(err).ToNot(HaveOccurred())
+}
+
+//takeSnapshot snapshot of every group, artifact, version, content and rule stored in the registry
+func takeSnapshot(client apicurioclient.ApicurioRegistryApiClient) *snapshot.Snapshot {
+	s, err := snapshot.Take(client)
+	Expect(err).ToNot(HaveOccurred())
+	return s
 }
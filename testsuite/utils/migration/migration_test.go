@@ -0,0 +1,62 @@
+package migration
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	apicurioclient "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/client"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/snapshot"
+)
+
+//newFakeRegistries starts a source and a destination fake registry, closed when the test ends
+func newFakeRegistries(t *testing.T) (*apicurioclient.FakeRegistry, *apicurioclient.FakeRegistry) {
+	source := apicurioclient.NewFakeRegistry()
+	t.Cleanup(source.Close)
+	dest := apicurioclient.NewFakeRegistry()
+	t.Cleanup(dest.Close)
+	return source, dest
+}
+
+func TestSeedsEveryArtifactTypeWithVersionsStatesAndRules(t *testing.T) {
+	RegisterTestingT(t)
+	source, _ := newFakeRegistries(t)
+	seedMigrationData(source.Client())
+
+	contents := takeSnapshot(source.Client())
+	Expect(contents.GlobalRules).To(HaveKeyWithValue(apicurioclient.ValidityRule, "SYNTAX_ONLY"))
+	Expect(contents.Groups).To(Equal(migrationGroups))
+	Expect(contents.Artifacts).To(HaveLen(len(apicurioclient.ArtifactTypes)))
+
+	avro := contents.Artifact("migration-a", "migration-avro")
+	Expect(avro.Rules).To(HaveKeyWithValue(apicurioclient.CompatibilityRule, "BACKWARD"))
+	Expect(avro.Versions).To(HaveLen(2))
+	Expect(avro.Versions[0].Version).To(Equal("1.0.0"))
+	Expect(avro.Versions[0].State).To(Equal(apicurioclient.Deprecated))
+	Expect(avro.Versions[1].ContentHash).To(Equal(snapshot.ContentHash(avroSchemaV2)))
+	Expect(avro.Labels).To(ConsistOf("migration", "avro"))
+}
+
+func TestDetectsIdenticalRegistriesAfterExportAndImport(t *testing.T) {
+	RegisterTestingT(t)
+	source, dest := newFakeRegistries(t)
+	seedMigrationData(source.Client())
+	data, err := source.Client().ExportData()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(dest.Client().ImportData(data)).To(Succeed())
+
+	Expect(snapshot.Diff(takeSnapshot(source.Client()), takeSnapshot(dest.Client()))).To(BeEmpty())
+}
+
+func TestDetectsDifferencesBetweenRegistries(t *testing.T) {
+	RegisterTestingT(t)
+	source, dest := newFakeRegistries(t)
+	seedMigrationData(source.Client())
+	seedMigrationData(dest.Client())
+	err := dest.Client().UpdateArtifactVersionState("migration-a", "migration-avro", "2.0.0", apicurioclient.Disabled)
+	Expect(err).ToNot(HaveOccurred())
+
+	Expect(snapshot.Diff(takeSnapshot(source.Client()), takeSnapshot(dest.Client()))).To(Equal([]string{
+		"artifact migration-a/migration-avro version 2.0.0: state expected ENABLED but found DISABLED",
+	}))
+}
@@ -4,8 +4,8 @@ import (
 	"context"
 	"errors"
 	"strings"
-	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 
@@ -14,13 +14,15 @@ import (
 	. "github.com/onsi/gomega"
 	v1 "github.com/operator-framework/operator-lifecycle-manager/pkg/package-server/apis/operators/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	utils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils"
 	kubernetesutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetes"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetescli"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/logs"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/timeouts"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/waiter"
 
 	operatorsv1 "github.com/operator-framework/api/pkg/operators/v1"
 	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
@@ -60,40 +62,28 @@ func CreateCatalogSource(suiteCtx *types.SuiteContext, catalogSourceNamespace st
 		Expect(err).ToNot(HaveOccurred())
 	}
 
-	timeout := 200 * time.Second
+	key := client.ObjectKey{Namespace: catalogSourceNamespace, Name: catalogSourceName}
+	podsSelector := client.MatchingLabels{"olm.catalogSource": catalogSourceName}
+
+	timeout := timeouts.Get(timeouts.CatalogSource)
 	log.Info("Waiting for catalog source", "timeout", timeout)
-	err = wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		_, err := suiteCtx.OLMClient.OperatorsV1alpha1().CatalogSources(catalogSourceNamespace).Get(context.TODO(), catalogSourceName, metav1.GetOptions{})
-		if err != nil {
-			if kubeerrors.IsNotFound(err) {
-				return false, nil
-			}
-			return false, err
-		}
-		return true, nil
-	})
+	record, err := suiteCtx.Waiter.Timeout(timeout).Describe("catalog source "+catalogSourceName).
+		ForObject(key, &operatorsv1alpha1.CatalogSource{}, waiter.Exists)
+	waiter.LogOnError(record, err)
 	kubernetescli.GetPods(catalogSourceNamespace)
 	if err != nil {
 		kubernetescli.Execute("get", "catalogsource", catalogSourceName, "-n", catalogSourceNamespace, "-o", "yaml")
 	}
 	Expect(err).ToNot(HaveOccurred())
 
-	timeout = 90 * time.Second
-	log.Info("Waiting for catalog source pod", "timeout", timeout)
-	wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		labelsSet := labels.Set(map[string]string{"olm.catalogSource": catalogSourceName})
-
-		pods, err := suiteCtx.Clientset.CoreV1().Pods(catalogSourceNamespace).List(context.TODO(), metav1.ListOptions{LabelSelector: labelsSet.AsSelector().String()})
-		if err != nil {
-			return false, err
-		}
-		if len(pods.Items) == 0 {
-			return false, nil
-		}
-		return true, nil
-	})
+	timeout = timeouts.Get(timeouts.CatalogSourcePod)
+	log.Info("Waiting for catalog source pod to be running", "timeout", timeout)
+	record, err = suiteCtx.Waiter.Timeout(timeout).Describe("catalog source "+catalogSourceName+" pods running").
+		ForList(&corev1.PodList{}, allPods(func(p *corev1.Pod) bool {
+			return p.Status.Phase == corev1.PodRunning
+		}), client.InNamespace(catalogSourceNamespace), podsSelector)
+	waiter.LogOnError(record, err)
 	kubernetescli.GetPods(catalogSourceNamespace)
-	time.Sleep(10 * time.Second)
 	if suiteCtx.IsOpenshift {
 		labelsSet := labels.Set(map[string]string{"olm.catalogSource": catalogSourceName})
 		err := suiteCtx.Clientset.CoreV1().Pods(catalogSourceNamespace).DeleteCollection(context.TODO(),
@@ -104,43 +94,24 @@ func CreateCatalogSource(suiteCtx *types.SuiteContext, catalogSourceNamespace st
 	}
 	kubernetescli.GetPods(catalogSourceNamespace)
 
-	timeout = 120 * time.Second
+	timeout = timeouts.Get(timeouts.CatalogSourcePodReady)
 	log.Info("Waiting for catalog source pod ready", "timeout", timeout)
-	wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		labelsSet := labels.Set(map[string]string{"olm.catalogSource": catalogSourceName})
-
-		pods, err := suiteCtx.Clientset.CoreV1().Pods(catalogSourceNamespace).List(context.TODO(), metav1.ListOptions{LabelSelector: labelsSet.AsSelector().String()})
-		if err != nil {
-			return false, err
-		}
-		if len(pods.Items) == 0 {
-			return false, nil
-		}
-		allPodsReady := true
-		for _, p := range pods.Items {
-			podReady := false
-			if p.Status.ContainerStatuses != nil && len(p.Status.ContainerStatuses) > 0 {
-				podReady = p.Status.ContainerStatuses[0].Ready
-			}
-			allPodsReady = allPodsReady && podReady
-		}
-		return allPodsReady, nil
-	})
+	record, err = suiteCtx.Waiter.Timeout(timeout).Describe("catalog source "+catalogSourceName+" pods ready").
+		ForList(&corev1.PodList{}, allPods(func(p *corev1.Pod) bool {
+			return len(p.Status.ContainerStatuses) > 0 && p.Status.ContainerStatuses[0].Ready
+		}), client.InNamespace(catalogSourceNamespace), podsSelector)
+	waiter.LogOnError(record, err)
 
 	kubernetescli.GetPods(catalogSourceNamespace)
 
-	timeout = 300 * time.Second
+	timeout = timeouts.Get(timeouts.CatalogSourceReady)
 	log.Info("Waiting for catalog source ready", "timeout", timeout)
-	err = wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		c, err := suiteCtx.OLMClient.OperatorsV1alpha1().CatalogSources(catalogSourceNamespace).Get(context.TODO(), catalogSourceName, metav1.GetOptions{})
-		if err != nil {
-			if kubeerrors.IsNotFound(err) {
-				return false, nil
-			}
-			return false, err
-		}
-		return c.Status.GRPCConnectionState.LastObservedState == "READY", nil
-	})
+	record, err = suiteCtx.Waiter.Timeout(timeout).Describe("catalog source "+catalogSourceName+" ready").
+		ForObject(key, &operatorsv1alpha1.CatalogSource{}, func(obj client.Object, exists bool) (bool, error) {
+			c := obj.(*operatorsv1alpha1.CatalogSource)
+			return exists && c.Status.GRPCConnectionState != nil && c.Status.GRPCConnectionState.LastObservedState == "READY", nil
+		})
+	waiter.LogOnError(record, err)
 	kubernetescli.GetPods(catalogSourceNamespace)
 	if err != nil {
 		kubernetescli.Execute("get", "catalogsource", catalogSourceName, "-n", catalogSourceNamespace, "-o", "yaml")
@@ -150,6 +121,22 @@ func CreateCatalogSource(suiteCtx *types.SuiteContext, catalogSourceNamespace st
 	return catalog
 }
 
+//allPods holds once there are pods and the condition holds for all of them
+func allPods(condition func(p *corev1.Pod) bool) waiter.ListPredicate {
+	return func(list client.ObjectList) (bool, error) {
+		pods := list.(*corev1.PodList)
+		if len(pods.Items) == 0 {
+			return false, nil
+		}
+		for i := range pods.Items {
+			if !condition(&pods.Items[i]) {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+}
+
 func DeleteCatalogSource(suiteCtx *types.SuiteContext, catalogSourceNamespace string, catalogSourceName string) {
 	log.Info("Removing catalog source " + catalogSourceName)
 	err := suiteCtx.OLMClient.OperatorsV1alpha1().CatalogSources(catalogSourceNamespace).Delete(context.TODO(), catalogSourceName, metav1.DeleteOptions{})
@@ -242,7 +229,7 @@ func DeleteSubscription(suiteCtx *types.SuiteContext, sub *operatorsv1alpha1.Sub
 			Expect(err).ToNot(HaveOccurred())
 		}
 		if defaultWait {
-			kubernetesutils.WaitForOperatorDeploymentRemoved(suiteCtx.Clientset, sub.Namespace, utils.OperatorDeploymentNameOlm)
+			kubernetesutils.WaitForOperatorDeploymentRemoved(suiteCtx, sub.Namespace, utils.OperatorDeploymentNameOlm)
 		}
 	}
 }
@@ -263,11 +250,11 @@ func InstallOperatorOLM(suiteCtx *types.SuiteContext, operatorNamespace string,
 	const operatorGroupName string = "apicurio-registry-operator-group"
 
 	if !clusterwide {
-		kubernetesutils.CreateTestNamespace(suiteCtx.Clientset, operatorNamespace)
+		kubernetesutils.CreateTestNamespace(suiteCtx, operatorNamespace)
 	}
 
 	var catalogSourceNamespace string = utils.OLMCatalogSourceNamespace
-	err := kubernetesutils.CreateNamespace(suiteCtx.Clientset, catalogSourceNamespace)
+	err := kubernetesutils.CreateNamespace(suiteCtx, catalogSourceNamespace)
 	if !kubeerrors.IsAlreadyExists(err) {
 		Expect(err).ToNot(HaveOccurred())
 	}
@@ -298,38 +285,12 @@ func InstallOperatorOLM(suiteCtx *types.SuiteContext, operatorNamespace string,
 	var channelCSV string = ""
 	if utils.OLMApicurioChannelName == "" || utils.OLMApicurioCSV == "" {
 
-		//TODO make this timeout configurable
-		timeout := 540 * time.Second
+		timeout := timeouts.Get(timeouts.PackageManifest)
 		log.Info("Waiting for package manifest to be available", "timeout", timeout)
-		var packageManifest *v1.PackageManifest = nil
-		err = wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-
-			packageManifest, err = suiteCtx.PackageClient.OperatorsV1().PackageManifests(catalogSourceNamespace).Get(context.TODO(), utils.OLMApicurioPackageManifestName, metav1.GetOptions{})
-
-			if err != nil && !kubeerrors.IsNotFound(err) {
-				return false, err
-			}
-			if packageManifest == nil {
-				return false, nil
-			}
-			return true, nil
-
-			// labelsSet := labels.Set(map[string]string{"catalog": catalogSourceName})
-			// pkgsList, err := suiteCtx.PackageClient.OperatorsV1().PackageManifests(catalogSourceNamespace).List(context.TODO(), metav1.ListOptions{LabelSelector: labelsSet.AsSelector().String()})
-
-			// if err != nil && !kubeerrors.IsNotFound(err) {
-			// 	return false, err
-			// }
-			// if pkgsList != nil {
-			// 	pkg := findApicurioPackageManifest(pkgsList)
-			// 	if pkg != nil {
-			// 		packageManifest = pkg
-			// 		return true, nil
-			// 	}
-			// }
-			// return false, nil
-
-		})
+		packageManifest := &v1.PackageManifest{}
+		record, err := suiteCtx.Waiter.Timeout(timeout).Describe("package manifest "+utils.OLMApicurioPackageManifestName).
+			ForObject(client.ObjectKey{Namespace: catalogSourceNamespace, Name: utils.OLMApicurioPackageManifestName}, packageManifest, waiter.Exists)
+		waiter.LogOnError(record, err)
 		if err != nil {
 			logPodsAll(operatorNamespace)
 			kubernetescli.Execute("get", "packagemanifest", "-n", catalogSourceNamespace)
@@ -378,7 +339,7 @@ func InstallOperatorOLM(suiteCtx *types.SuiteContext, operatorNamespace string,
 		ChannelCSV:             channelCSV,
 		ChannelName:            channelName,
 	})
-	kubernetesutils.WaitForOperatorDeploymentReady(suiteCtx.Clientset, sub.Namespace, utils.OperatorDeploymentNameOlm)
+	kubernetesutils.WaitForOperatorDeploymentReady(suiteCtx, sub.Namespace, utils.OperatorDeploymentNameOlm)
 	kubernetescli.GetPods("olm") // tmp
 
 	return &OLMInstallationInfo{
@@ -411,7 +372,7 @@ func UninstallOperatorOLM(suiteCtx *types.SuiteContext, operatorNamespace string
 	}
 
 	if !clusterwide {
-		kubernetesutils.DeleteTestNamespace(suiteCtx.Clientset, operatorNamespace)
+		kubernetesutils.DeleteTestNamespace(suiteCtx, operatorNamespace)
 	}
 
 }
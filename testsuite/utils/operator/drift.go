@@ -0,0 +1,353 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	routev1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils"
+	apicurioutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/functional"
+	kubernetesutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetes"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/timeouts"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+	apicurio "github.com/Apicurio/apicurio-registry-operator/api/v1"
+)
+
+//driftValue value tampered fields are set to, so they are easy to spot in the logs
+const driftValue string = "drift"
+
+//DriftResult outcome of drifting one object managed by the operator
+type DriftResult struct {
+	Kind   string
+	Name   string
+	Action string
+	//Skipped the operator doesn't manage an object of the kind, older operators don't create every kind
+	Skipped   bool
+	Converged bool
+	//Elapsed time from the drift until the operator restored the object
+	Elapsed time.Duration
+	Error   string
+}
+
+//DriftReport results of drifting every object the operator manages for one registry
+type DriftReport struct {
+	Registry string
+	Results  []DriftResult
+}
+
+//Failed results of the objects the operator didn't restore
+func (r *DriftReport) Failed() []DriftResult {
+	failed := []DriftResult{}
+	for _, result := range r.Results {
+		if !result.Skipped && !result.Converged {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+//String one row per object, with how long the operator took to restore it
+func (r *DriftReport) String() string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "Drift report of registry %v\n", r.Registry)
+	w := tabwriter.NewWriter(b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tNAME\tACTION\tRESULT\tELAPSED")
+	for _, result := range r.Results {
+		outcome := "converged"
+		elapsed := result.Elapsed.Round(time.Millisecond).String()
+		if result.Skipped {
+			outcome = "skipped, not managed"
+			elapsed = "-"
+		} else if !result.Converged {
+			outcome = "NOT CONVERGED: " + result.Error
+		}
+		name := result.Name
+		if name == "" {
+			name = "-"
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\n", result.Kind, name, result.Action, outcome, elapsed)
+	}
+	w.Flush()
+	return b.String()
+}
+
+//driftTarget one kind of object managed by the operator and how the test drifts it
+type driftTarget struct {
+	kind   string
+	obj    func() client.Object
+	list   func() client.ObjectList
+	action string
+	//tamper changes the object in place before it's updated, nil deletes the object
+	tamper func(obj client.Object) error
+	//restored checks the operator converged the current object back to the original one
+	restored func(original client.Object, current client.Object) bool
+}
+
+//driftTargets objects every registry deployment has, ingresses are replaced by routes on openshift.
+//Pod disruption budgets are drifted in the version the cluster serves
+func driftTargets(isOpenshift bool, pdbGVK schema.GroupVersionKind) []driftTarget {
+	targets := []driftTarget{
+		{
+			kind:     "Deployment",
+			obj:      func() client.Object { return &appsv1.Deployment{} },
+			list:     func() client.ObjectList { return &appsv1.DeploymentList{} },
+			action:   "tamper env",
+			tamper:   tamperDeploymentEnv,
+			restored: deploymentEnvRestored,
+		},
+		{
+			kind:     "Service",
+			obj:      func() client.Object { return &corev1.Service{} },
+			list:     func() client.ObjectList { return &corev1.ServiceList{} },
+			action:   "tamper port",
+			tamper:   tamperServicePort,
+			restored: servicePortsRestored,
+		},
+	}
+	if isOpenshift {
+		targets = append(targets, driftTarget{
+			kind:     "Route",
+			obj:      func() client.Object { return &routev1.Route{} },
+			list:     func() client.ObjectList { return &routev1.RouteList{} },
+			action:   "tamper host",
+			tamper:   tamperRouteHost,
+			restored: routeHostRestored,
+		})
+	} else {
+		targets = append(targets, driftTarget{
+			kind:     "Ingress",
+			obj:      func() client.Object { return &networking.Ingress{} },
+			list:     func() client.ObjectList { return &networking.IngressList{} },
+			action:   "tamper host",
+			tamper:   tamperIngressHost,
+			restored: ingressHostsRestored,
+		})
+	}
+	return append(targets,
+		driftTarget{
+			kind:     "PodDisruptionBudget",
+			obj:      func() client.Object { return kubernetesutils.UnstructuredObject(pdbGVK) },
+			list:     func() client.ObjectList { return kubernetesutils.UnstructuredList(pdbGVK) },
+			action:   "delete",
+			restored: pdbRestored,
+		},
+		driftTarget{
+			kind:     "NetworkPolicy",
+			obj:      func() client.Object { return &networking.NetworkPolicy{} },
+			list:     func() client.ObjectList { return &networking.NetworkPolicyList{} },
+			action:   "delete",
+			restored: networkPolicyRestored,
+		},
+	)
+}
+
+//DriftTestCase deletes or tampers with every object the operator manages for the registry, one at a time,
+//verifying the operator converges each of them back and the registry keeps serving afterwards
+func DriftTestCase(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
+	log.Info("Testing operator reconciliation drift")
+	pdbGVK, err := kubernetesutils.PodDisruptionBudgetGVK(suiteCtx)
+	Expect(err).ToNot(HaveOccurred())
+	report := &DriftReport{Registry: ctx.RegistryNamespace + "/" + ctx.RegistryName}
+	for _, target := range driftTargets(suiteCtx.IsOpenshift, pdbGVK) {
+		result := drift(suiteCtx, ctx, target)
+		log.Info("Drift result", "kind", result.Kind, "name", result.Name, "skipped", result.Skipped, "converged", result.Converged, "elapsed", result.Elapsed)
+		report.Results = append(report.Results, result)
+	}
+	log.Info(report.String())
+	saveDriftReport(suiteCtx, ctx, report)
+
+	Expect(report.Failed()).To(BeEmpty(), report.String())
+	apicurioutils.WaitForRegistryReady(suiteCtx, ctx.RegistryNamespace, ctx.RegistryName, ctx.RegistryReplicas())
+	functional.BasicRegistryAPITest(ctx)
+}
+
+//drift applies the drift to the managed object of the target kind and waits for the operator to restore it
+func drift(suiteCtx *types.SuiteContext, ctx *types.TestContext, target driftTarget) DriftResult {
+	result := DriftResult{Kind: target.kind, Action: target.action}
+
+	registry := &apicurio.ApicurioRegistry{}
+	err := suiteCtx.K8sClient.Get(context.TODO(), client.ObjectKey{Namespace: ctx.RegistryNamespace, Name: ctx.RegistryName}, registry)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	var original client.Object
+	var drifted client.Object
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj := target.obj()
+		found, err := apicurioutils.GetManagedObject(suiteCtx, registry, obj, target.list())
+		if err != nil || !found {
+			original = nil
+			return err
+		}
+		original = obj.DeepCopyObject().(client.Object)
+		if target.tamper == nil {
+			drifted = obj
+			return suiteCtx.K8sClient.Delete(context.TODO(), obj)
+		}
+		if err := target.tamper(obj); err != nil {
+			return err
+		}
+		drifted = obj
+		return suiteCtx.K8sClient.Update(context.TODO(), obj)
+	})
+	if err == nil && original == nil {
+		result.Skipped = true
+		return result
+	}
+	if original != nil {
+		result.Name = original.GetName()
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	start := time.Now()
+
+	//the cache may still hold the object as it was before or right after the drift, only later versions count
+	record, err := suiteCtx.Waiter.Timeout(timeouts.Get(timeouts.OperatorConvergence)).
+		Describe(fmt.Sprintf("operator to restore %v %v after %v", target.kind, original.GetName(), target.action)).
+		ForObject(client.ObjectKeyFromObject(original), target.obj(), func(obj client.Object, exists bool) (bool, error) {
+			if !exists || obj.GetResourceVersion() == original.GetResourceVersion() || obj.GetResourceVersion() == drifted.GetResourceVersion() {
+				return false, nil
+			}
+			if target.tamper == nil && obj.GetUID() == original.GetUID() {
+				return false, nil
+			}
+			return target.restored(original, obj), nil
+		})
+	result.Elapsed = time.Since(start)
+	if err != nil {
+		log.Info(record.String())
+		result.Error = err.Error()
+		return result
+	}
+	result.Converged = true
+	return result
+}
+
+//saveDriftReport stores the report along with the logs of the testsuite
+func saveDriftReport(suiteCtx *types.SuiteContext, ctx *types.TestContext, report *DriftReport) {
+	reportsDir := utils.SuiteProjectDir + "/tests-logs/" + suiteCtx.SuiteID + "/drift/"
+	os.MkdirAll(reportsDir, os.ModePerm)
+	err := ioutil.WriteFile(reportsDir+ctx.RegistryNamespace+"-"+ctx.RegistryName+".log", []byte(report.String()), 0644)
+	if err != nil {
+		log.Error(err, "Error saving drift report")
+	}
+}
+
+//tamperDeploymentEnv changes the value of the first plain env var of the registry container
+func tamperDeploymentEnv(obj client.Object) error {
+	deployment := obj.(*appsv1.Deployment)
+	for c := range deployment.Spec.Template.Spec.Containers {
+		env := deployment.Spec.Template.Spec.Containers[c].Env
+		for i := range env {
+			if env[i].ValueFrom == nil && env[i].Value != driftValue {
+				env[i].Value = driftValue
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("deployment %v has no env var to tamper with", deployment.Name)
+}
+
+func deploymentEnvRestored(original client.Object, current client.Object) bool {
+	originalContainers := original.(*appsv1.Deployment).Spec.Template.Spec.Containers
+	currentContainers := current.(*appsv1.Deployment).Spec.Template.Spec.Containers
+	if len(originalContainers) != len(currentContainers) {
+		return false
+	}
+	for i := range originalContainers {
+		if !equality.Semantic.DeepEqual(envValues(originalContainers[i].Env), envValues(currentContainers[i].Env)) {
+			return false
+		}
+	}
+	return true
+}
+
+//envValues env vars by name, the operator doesn't have to keep their order
+func envValues(env []corev1.EnvVar) map[string]corev1.EnvVar {
+	values := map[string]corev1.EnvVar{}
+	for _, e := range env {
+		values[e.Name] = e
+	}
+	return values
+}
+
+func tamperServicePort(obj client.Object) error {
+	service := obj.(*corev1.Service)
+	if len(service.Spec.Ports) == 0 {
+		return fmt.Errorf("service %v has no ports to tamper with", service.Name)
+	}
+	service.Spec.Ports[0].Port += 1000
+	return nil
+}
+
+func servicePortsRestored(original client.Object, current client.Object) bool {
+	return equality.Semantic.DeepEqual(servicePorts(original.(*corev1.Service)), servicePorts(current.(*corev1.Service)))
+}
+
+func servicePorts(service *corev1.Service) []string {
+	ports := []string{}
+	for _, p := range service.Spec.Ports {
+		ports = append(ports, fmt.Sprintf("%v:%v->%v", p.Name, p.Port, p.TargetPort.String()))
+	}
+	return ports
+}
+
+func tamperIngressHost(obj client.Object) error {
+	ingress := obj.(*networking.Ingress)
+	if len(ingress.Spec.Rules) == 0 {
+		return fmt.Errorf("ingress %v has no rules to tamper with", ingress.Name)
+	}
+	ingress.Spec.Rules[0].Host = driftValue + "." + ingress.Spec.Rules[0].Host
+	return nil
+}
+
+func ingressHostsRestored(original client.Object, current client.Object) bool {
+	return equality.Semantic.DeepEqual(ingressHosts(original.(*networking.Ingress)), ingressHosts(current.(*networking.Ingress)))
+}
+
+func ingressHosts(ingress *networking.Ingress) []string {
+	hosts := []string{}
+	for _, rule := range ingress.Spec.Rules {
+		hosts = append(hosts, rule.Host)
+	}
+	return hosts
+}
+
+func tamperRouteHost(obj client.Object) error {
+	route := obj.(*routev1.Route)
+	route.Spec.Host = driftValue + "." + route.Spec.Host
+	return nil
+}
+
+func routeHostRestored(original client.Object, current client.Object) bool {
+	return original.(*routev1.Route).Spec.Host == current.(*routev1.Route).Spec.Host
+}
+
+func pdbRestored(original client.Object, current client.Object) bool {
+	return equality.Semantic.DeepEqual(original.(*unstructured.Unstructured).Object["spec"], current.(*unstructured.Unstructured).Object["spec"])
+}
+
+func networkPolicyRestored(original client.Object, current client.Object) bool {
+	return equality.Semantic.DeepEqual(original.(*networking.NetworkPolicy).Spec, current.(*networking.NetworkPolicy).Spec)
+}
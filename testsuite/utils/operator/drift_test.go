@@ -0,0 +1,107 @@
+package operator
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestDriftTampersWithTheFirstPlainEnvVarAndDetectsWhenItsRestored(t *testing.T) {
+	g := NewWithT(t)
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "registry-deployment"}}
+	deployment.Spec.Template.Spec.Containers = []corev1.Container{{
+		Name: "registry",
+		Env: []corev1.EnvVar{
+			{Name: "REGISTRY_DATASOURCE_PASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{Key: "password"}}},
+			{Name: "LOG_LEVEL", Value: "INFO"},
+			{Name: "QUARKUS_PROFILE", Value: "prod"},
+		},
+	}}
+	original := deployment.DeepCopy()
+
+	g.Expect(tamperDeploymentEnv(deployment)).To(Succeed())
+	g.Expect(deployment.Spec.Template.Spec.Containers[0].Env[1].Value).To(Equal(driftValue))
+	g.Expect(deploymentEnvRestored(original, deployment)).To(BeFalse())
+
+	restored := original.DeepCopy()
+	env := restored.Spec.Template.Spec.Containers[0].Env
+	env[1], env[2] = env[2], env[1]
+	g.Expect(deploymentEnvRestored(original, restored)).To(BeTrue())
+}
+
+func TestDriftFailsToTamperWithADeploymentWithoutEnvVars(t *testing.T) {
+	g := NewWithT(t)
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "registry-deployment"}}
+	deployment.Spec.Template.Spec.Containers = []corev1.Container{{Name: "registry"}}
+	g.Expect(tamperDeploymentEnv(deployment)).To(MatchError(ContainSubstring("no env var")))
+}
+
+func TestDriftDetectsRestoredServicePortsAndIngressHosts(t *testing.T) {
+	g := NewWithT(t)
+	service := &corev1.Service{Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 8080, TargetPort: intstr.FromInt(8080)}}}}
+	originalService := service.DeepCopy()
+	g.Expect(tamperServicePort(service)).To(Succeed())
+	g.Expect(service.Spec.Ports[0].Port).To(Equal(int32(9080)))
+	g.Expect(servicePortsRestored(originalService, service)).To(BeFalse())
+	g.Expect(servicePortsRestored(originalService, originalService.DeepCopy())).To(BeTrue())
+
+	ingress := &networking.Ingress{Spec: networking.IngressSpec{Rules: []networking.IngressRule{{Host: "registry.127.0.0.1.nip.io"}}}}
+	originalIngress := ingress.DeepCopy()
+	g.Expect(tamperIngressHost(ingress)).To(Succeed())
+	g.Expect(ingress.Spec.Rules[0].Host).To(Equal("drift.registry.127.0.0.1.nip.io"))
+	g.Expect(ingressHostsRestored(originalIngress, ingress)).To(BeFalse())
+	g.Expect(ingressHostsRestored(originalIngress, originalIngress.DeepCopy())).To(BeTrue())
+}
+
+func TestDriftDetectsRestoredPodDisruptionBudgetsOfTheServedPolicyVersion(t *testing.T) {
+	g := NewWithT(t)
+	pdbGVK := schema.GroupVersionKind{Group: "policy", Version: "v1", Kind: "PodDisruptionBudget"}
+	original := driftTargets(false, pdbGVK)[3].obj().(*unstructured.Unstructured)
+	g.Expect(original.GetAPIVersion()).To(Equal("policy/v1"))
+	original.Object["spec"] = map[string]interface{}{"maxUnavailable": int64(1)}
+
+	recreated := original.DeepCopy()
+	g.Expect(pdbRestored(original, recreated)).To(BeTrue())
+	recreated.Object["spec"] = map[string]interface{}{"minAvailable": int64(1)}
+	g.Expect(pdbRestored(original, recreated)).To(BeFalse())
+}
+
+func TestDriftTargetsRoutesInsteadOfIngressesOnOpenshift(t *testing.T) {
+	g := NewWithT(t)
+	kinds := func(targets []driftTarget) []string {
+		names := []string{}
+		for _, target := range targets {
+			names = append(names, target.kind)
+		}
+		return names
+	}
+	pdbGVK := schema.GroupVersionKind{Group: "policy", Version: "v1", Kind: "PodDisruptionBudget"}
+	g.Expect(kinds(driftTargets(false, pdbGVK))).To(Equal([]string{"Deployment", "Service", "Ingress", "PodDisruptionBudget", "NetworkPolicy"}))
+	g.Expect(kinds(driftTargets(true, pdbGVK))).To(Equal([]string{"Deployment", "Service", "Route", "PodDisruptionBudget", "NetworkPolicy"}))
+}
+
+func TestDriftReportsFailuresButNotSkippedObjects(t *testing.T) {
+	g := NewWithT(t)
+	report := &DriftReport{Registry: "testsuite/registry", Results: []DriftResult{
+		{Kind: "Deployment", Name: "registry-deployment", Action: "tamper env", Converged: true, Elapsed: 1500 * time.Millisecond},
+		{Kind: "PodDisruptionBudget", Action: "delete", Skipped: true},
+		{Kind: "NetworkPolicy", Name: "registry-networkpolicy", Action: "delete", Error: "timeout"},
+	}}
+	g.Expect(report.Failed()).To(HaveLen(1))
+	g.Expect(report.Failed()[0].Kind).To(Equal("NetworkPolicy"))
+
+	lines := report.String()
+	g.Expect(lines).To(HavePrefix("Drift report of registry testsuite/registry\n"))
+	g.Expect(lines).To(MatchRegexp(`Deployment\s+registry-deployment\s+tamper env\s+converged\s+1.5s`))
+	g.Expect(lines).To(MatchRegexp(`PodDisruptionBudget\s+-\s+delete\s+skipped, not managed\s+-`))
+	g.Expect(lines).To(MatchRegexp(`NetworkPolicy\s+registry-networkpolicy\s+delete\s+NOT CONVERGED: timeout`))
+}
@@ -0,0 +1,7 @@
+package operator
+
+import (
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var log = logf.Log.WithName("operator")
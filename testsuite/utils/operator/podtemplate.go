@@ -0,0 +1,197 @@
+package operator
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apicurioutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/functional"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/timeouts"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+)
+
+//PodTemplatePrunedFields the fields of the pod template customizations of the test context the api server prunes from the registry,
+//because the installed operator doesn't support them. It runs before deploying the storage, skipping the test right away
+//if none of the customizations is supported
+func PodTemplatePrunedFields(suiteCtx *types.SuiteContext, ctx *types.TestContext) []string {
+	Expect(ctx.PodTemplate).ToNot(BeNil(), "test context without pod template customizations")
+
+	pruned, err := apicurioutils.PrunedFields(suiteCtx, ctx, apicurioutils.EnvField, apicurioutils.PodTemplateField)
+	Expect(err).ToNot(HaveOccurred())
+	if len(pruned) != 0 && supportedPodTemplate(ctx.PodTemplate, pruned).IsEmpty() {
+		Skip(unsupportedMessage(pruned))
+	}
+	return pruned
+}
+
+//PodTemplateTestCase verifies the pod template customizations of the test context reached every running pod of the registry.
+//The customizations in pruned fields are not verified, the supported ones are and then the test is skipped, reporting the pruned fields
+func PodTemplateTestCase(suiteCtx *types.SuiteContext, ctx *types.TestContext, pruned []string) {
+	expected := supportedPodTemplate(ctx.PodTemplate, pruned)
+
+	//pods of the first rollout can still be running when the operator applies the customizations in a second one
+	var problems []string
+	record, err := suiteCtx.Waiter.Timeout(timeouts.Get(timeouts.RegistryRollout)).Describe("registry pods with the pod template customizations").
+		ForList(&corev1.PodList{}, func(list client.ObjectList) (bool, error) {
+			problems = []string{}
+			running := 0
+			for _, pod := range list.(*corev1.PodList).Items {
+				if pod.DeletionTimestamp != nil {
+					continue
+				}
+				running++
+				problems = append(problems, podTemplateProblems(expected, ctx.RegistryName, &pod)...)
+			}
+			if running == 0 {
+				problems = append(problems, "no registry pods")
+			}
+			return len(problems) == 0, nil
+		}, client.InNamespace(ctx.RegistryNamespace), client.MatchingLabels{"app": ctx.RegistryName})
+	if err != nil {
+		log.Info(record.String())
+		Expect(fmt.Errorf("pod template customizations not applied: %v", strings.Join(problems, "; "))).ToNot(HaveOccurred())
+	}
+	log.Info("Successful pod template customizations verification")
+
+	functional.BasicRegistryAPITest(ctx)
+
+	if len(pruned) != 0 {
+		Skip(unsupportedMessage(pruned))
+	}
+}
+
+func unsupportedMessage(pruned []string) string {
+	return fmt.Sprintf("operator doesn't support the pod template customizations in %v, the api server pruned them from the registry",
+		strings.Join(pruned, ", "))
+}
+
+//supportedPodTemplate the customizations the operator supports, without the ones requested through pruned fields
+func supportedPodTemplate(pt *types.PodTemplate, pruned []string) *types.PodTemplate {
+	supported := *pt
+	for _, field := range pruned {
+		switch field {
+		case strings.Join(apicurioutils.EnvField, "."):
+			supported.Env = nil
+		case strings.Join(apicurioutils.PodTemplateField, "."):
+			supported.Resources = nil
+			supported.Annotations = nil
+			supported.Labels = nil
+			supported.Volumes = nil
+			supported.VolumeMounts = nil
+		}
+	}
+	return &supported
+}
+
+//podTemplateProblems differences between the requested customizations and the pod.
+//Kubernetes adds tolerations, labels and volumes of its own, so the requested ones only have to be part of the pod
+func podTemplateProblems(pt *types.PodTemplate, registryName string, pod *corev1.Pod) []string {
+	problems := []string{}
+	problem := func(format string, args ...interface{}) {
+		problems = append(problems, "pod "+pod.Name+" "+fmt.Sprintf(format, args...))
+	}
+
+	container := apicurioutils.RegistryContainer(&pod.Spec, registryName)
+	if container == nil {
+		problem("has no registry container")
+		return problems
+	}
+
+	for _, e := range pt.Env {
+		found := false
+		for _, c := range container.Env {
+			if c.Name == e.Name {
+				found = true
+				if !equality.Semantic.DeepEqual(c, e) {
+					problem("env var %v is %v, expected %v", e.Name, toString(c), toString(e))
+				}
+			}
+		}
+		if !found {
+			problem("has no env var %v", e.Name)
+		}
+	}
+
+	if pt.Resources != nil {
+		for name, quantity := range pt.Resources.Requests {
+			if actual, exists := container.Resources.Requests[name]; !exists || actual.Cmp(quantity) != 0 {
+				problem("requests %v %v, expected %v", name, actual.String(), quantity.String())
+			}
+		}
+		for name, quantity := range pt.Resources.Limits {
+			if actual, exists := container.Resources.Limits[name]; !exists || actual.Cmp(quantity) != 0 {
+				problem("limits %v %v, expected %v", name, actual.String(), quantity.String())
+			}
+		}
+	}
+
+	if pt.Affinity != nil && !equality.Semantic.DeepEqual(pod.Spec.Affinity, pt.Affinity) {
+		problem("affinity is %v, expected %v", toString(pod.Spec.Affinity), toString(pt.Affinity))
+	}
+
+	for _, t := range pt.Tolerations {
+		found := false
+		for _, actual := range pod.Spec.Tolerations {
+			if equality.Semantic.DeepEqual(actual, t) {
+				found = true
+			}
+		}
+		if !found {
+			problem("has no toleration %v", toString(t))
+		}
+	}
+
+	for k, v := range pt.Annotations {
+		if actual, exists := pod.Annotations[k]; !exists || actual != v {
+			problem("annotation %v is %q, expected %q", k, actual, v)
+		}
+	}
+	for k, v := range pt.Labels {
+		if actual, exists := pod.Labels[k]; !exists || actual != v {
+			problem("label %v is %q, expected %q", k, actual, v)
+		}
+	}
+
+	for _, v := range pt.Volumes {
+		found := false
+		for _, actual := range pod.Spec.Volumes {
+			if actual.Name == v.Name {
+				found = true
+				if !equality.Semantic.DeepEqual(actual.VolumeSource, v.VolumeSource) {
+					problem("volume %v is %v, expected %v", v.Name, toString(actual.VolumeSource), toString(v.VolumeSource))
+				}
+			}
+		}
+		if !found {
+			problem("has no volume %v", v.Name)
+		}
+	}
+	for _, m := range pt.VolumeMounts {
+		found := false
+		for _, actual := range container.VolumeMounts {
+			if actual.Name == m.Name && actual.MountPath == m.MountPath {
+				found = true
+			}
+		}
+		if !found {
+			problem("container %v doesn't mount volume %v at %v", container.Name, m.Name, m.MountPath)
+		}
+	}
+	return problems
+}
+
+func toString(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
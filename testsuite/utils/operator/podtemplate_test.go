@@ -0,0 +1,115 @@
+package operator
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+)
+
+//customizedPodTemplate customizations of every kind
+func customizedPodTemplate() *types.PodTemplate {
+	return &types.PodTemplate{
+		Env: []corev1.EnvVar{{Name: "E2E_CUSTOM_ENV", Value: "custom-value"}},
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1200Mi")},
+		},
+		Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "kubernetes.io/os", Operator: corev1.NodeSelectorOpIn, Values: []string{"linux"}}},
+			}}},
+		}},
+		Tolerations:  []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+		Annotations:  map[string]string{"e2e": "annotation"},
+		Labels:       map[string]string{"e2e": "label"},
+		Volumes:      []corev1.Volume{{Name: "extra", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+		VolumeMounts: []corev1.VolumeMount{{Name: "extra", MountPath: "/extra"}},
+	}
+}
+
+//customizedPod a pod with the customizations plus what the operator and kubernetes add on their own
+func customizedPod(podTemplate *types.PodTemplate) *corev1.Pod {
+	notReady := corev1.Toleration{Key: "node.kubernetes.io/not-ready", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "registry-deployment-abc",
+			Labels:      map[string]string{"app": "registry", "e2e": "label"},
+			Annotations: map[string]string{"e2e": "annotation"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name: "registry",
+				Env:  []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "INFO"}, {Name: "E2E_CUSTOM_ENV", Value: "custom-value"}},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("0.25"), corev1.ResourceMemory: resource.MustParse("512Mi")},
+					Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1200Mi")},
+				},
+				VolumeMounts: []corev1.VolumeMount{{Name: "tmp", MountPath: "/tmp"}, {Name: "extra", MountPath: "/extra"}},
+			}},
+			Affinity:    podTemplate.Affinity.DeepCopy(),
+			Tolerations: []corev1.Toleration{notReady, podTemplate.Tolerations[0]},
+			Volumes: []corev1.Volume{
+				{Name: "tmp", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+				{Name: "extra", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			},
+		},
+	}
+}
+
+func TestPodTemplateAcceptsPodsWithEveryCustomization(t *testing.T) {
+	g := NewWithT(t)
+	podTemplate := customizedPodTemplate()
+	g.Expect(podTemplateProblems(podTemplate, "registry", customizedPod(podTemplate))).To(BeEmpty())
+}
+
+func TestPodTemplateReportsEveryCustomizationMissingInThePod(t *testing.T) {
+	g := NewWithT(t)
+	podTemplate := customizedPodTemplate()
+	pod := customizedPod(podTemplate)
+	container := &pod.Spec.Containers[0]
+	container.Env = container.Env[:1]
+	container.Resources.Limits[corev1.ResourceMemory] = resource.MustParse("1300Mi")
+	container.VolumeMounts = container.VolumeMounts[:1]
+	pod.Spec.Affinity = nil
+	pod.Spec.Tolerations = pod.Spec.Tolerations[:1]
+	pod.Spec.Volumes = pod.Spec.Volumes[:1]
+	delete(pod.Labels, "e2e")
+	pod.Annotations["e2e"] = "other"
+
+	g.Expect(podTemplateProblems(podTemplate, "registry", pod)).To(ConsistOf(
+		"pod registry-deployment-abc has no env var E2E_CUSTOM_ENV",
+		"pod registry-deployment-abc limits memory 1300Mi, expected 1200Mi",
+		`pod registry-deployment-abc affinity is null, expected {"nodeAffinity":{"requiredDuringSchedulingIgnoredDuringExecution":{"nodeSelectorTerms":[{"matchExpressions":[{"key":"kubernetes.io/os","operator":"In","values":["linux"]}]}]}}}`,
+		`pod registry-deployment-abc has no toleration {"key":"dedicated","operator":"Exists","effect":"NoSchedule"}`,
+		`pod registry-deployment-abc annotation e2e is "other", expected "annotation"`,
+		`pod registry-deployment-abc label e2e is "", expected "label"`,
+		"pod registry-deployment-abc has no volume extra",
+		"pod registry-deployment-abc container registry doesn't mount volume extra at /extra",
+	))
+}
+
+func TestPodTemplateSkipsTheCustomizationsOfPrunedFields(t *testing.T) {
+	g := NewWithT(t)
+	podTemplate := customizedPodTemplate()
+	supported := supportedPodTemplate(podTemplate, []string{"spec.configuration.env", "spec.deployment.podTemplateSpecPreview"})
+	g.Expect(supported.Env).To(BeNil())
+	g.Expect(supported.Resources).To(BeNil())
+	g.Expect(supported.Labels).To(BeNil())
+	g.Expect(supported.Volumes).To(BeNil())
+	g.Expect(supported.Affinity).ToNot(BeNil())
+	g.Expect(supported.Tolerations).To(HaveLen(1))
+	g.Expect(podTemplate.Env).To(HaveLen(1), "the requested pod template is not modified")
+	g.Expect(supported.IsEmpty()).To(BeFalse())
+	g.Expect(supportedPodTemplate(&types.PodTemplate{Env: podTemplate.Env}, []string{"spec.configuration.env"}).IsEmpty()).To(BeTrue())
+
+	pod := customizedPod(podTemplate)
+	pod.Spec.Containers[0].Env = nil
+	pod.Spec.Containers[0].Name = "apicurio-registry-sql"
+	g.Expect(podTemplateProblems(supported, "apicurio-registry-sql", pod)).To(BeEmpty())
+}
@@ -0,0 +1,119 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/gomega"
+
+	networking "k8s.io/api/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apicurioutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/functional"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+	apicurio "github.com/Apicurio/apicurio-registry-operator/api/v1"
+)
+
+//UpdateTestCases changes a running registry in every supported way, verifying the operator applies each change and the registry keeps serving
+func UpdateTestCases(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
+	LogLevelUpdateTestCase(suiteCtx, ctx)
+	EnvUpdateTestCase(suiteCtx, ctx)
+	ReplicasUpdateTestCase(suiteCtx, ctx)
+	HostUpdateTestCase(suiteCtx, ctx)
+}
+
+//LogLevelUpdateTestCase changes the log level of the registry, the operator has to roll it out as an env var of the deployment.
+//Registries are deployed with DEBUG log level, it's restored at the end
+func LogLevelUpdateTestCase(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
+	log.Info("Testing log level update")
+	diff := apicurioutils.UpdateRegistryAndWait(suiteCtx, ctx, true, func(spec *apicurio.ApicurioRegistrySpec) {
+		spec.Configuration.LogLevel = "INFO"
+	})
+	before := expectEnvChange(diff, "LOG_LEVEL", "INFO")
+	Expect(before).To(Equal("DEBUG"))
+	functional.BasicRegistryAPITest(ctx)
+
+	diff = apicurioutils.UpdateRegistryAndWait(suiteCtx, ctx, true, func(spec *apicurio.ApicurioRegistrySpec) {
+		spec.Configuration.LogLevel = "DEBUG"
+	})
+	expectEnvChange(diff, "LOG_LEVEL", "DEBUG")
+}
+
+//EnvUpdateTestCase changes a configuration the operator passes to the registry through env vars, the spec has no field to set env vars directly
+func EnvUpdateTestCase(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
+	log.Info("Testing env vars update")
+	diff := apicurioutils.UpdateRegistryAndWait(suiteCtx, ctx, true, func(spec *apicurio.ApicurioRegistrySpec) {
+		spec.Configuration.UI.ReadOnly = true
+	})
+	expectEnvChange(diff, "READONLY", "true")
+	functional.BasicRegistryAPITest(ctx)
+
+	diff = apicurioutils.UpdateRegistryAndWait(suiteCtx, ctx, true, func(spec *apicurio.ApicurioRegistrySpec) {
+		spec.Configuration.UI.ReadOnly = false
+	})
+	Expect(diff.Changes).ToNot(BeEmpty())
+}
+
+//ReplicasUpdateTestCase scales the registry up and back down, every replica has to be ready and serving
+func ReplicasUpdateTestCase(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
+	log.Info("Testing replicas update")
+	var replicas int32 = 2
+	diff := apicurioutils.UpdateRegistryAndWait(suiteCtx, ctx, true, func(spec *apicurio.ApicurioRegistrySpec) {
+		spec.Deployment.Replicas = replicas
+	})
+	Expect(diff.Changes).To(ContainElement("replicas: 1 -> 2"))
+	Expect(functional.ReplicaClients(suiteCtx, ctx)).To(HaveLen(int(replicas)))
+	functional.BasicRegistryAPITest(ctx)
+
+	diff = apicurioutils.UpdateRegistryAndWait(suiteCtx, ctx, true, func(spec *apicurio.ApicurioRegistrySpec) {
+		spec.Deployment.Replicas = 1
+	})
+	Expect(diff.Changes).To(ContainElement("replicas: 2 -> 1"))
+	functional.BasicRegistryAPITest(ctx)
+}
+
+//HostUpdateTestCase changes the host of the registry, the operator has to update the ingress without rolling out the deployment
+func HostUpdateTestCase(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
+	if suiteCtx.IsOpenshift {
+		//TODO verify the route, the testsuite relies on the host openshift generates for it
+		log.Info("Skipping host update test on openshift")
+		return
+	}
+	log.Info("Testing host update")
+	host := ctx.RegistryName + "-updated.127.0.0.1.nip.io"
+	diff := apicurioutils.UpdateRegistryAndWait(suiteCtx, ctx, false, func(spec *apicurio.ApicurioRegistrySpec) {
+		spec.Deployment.Host = host
+	})
+	Expect(ctx.RegistryResource.Status.Info.Host).To(Or(Equal(host), BeEmpty()))
+	for _, c := range diff.Changes {
+		Expect(c).ToNot(HavePrefix("replicas"), "host update changed the deployment replicas")
+	}
+
+	ingresses := &networking.IngressList{}
+	err := suiteCtx.K8sClient.List(context.TODO(), ingresses, client.InNamespace(ctx.RegistryNamespace), client.MatchingLabels{"app": ctx.RegistryName})
+	Expect(err).ToNot(HaveOccurred())
+	Expect(ingresses.Items).To(HaveLen(1))
+	Expect(ingresses.Items[0].Spec.Rules).ToNot(BeEmpty())
+	Expect(ingresses.Items[0].Spec.Rules[0].Host).To(Equal(host))
+
+	ctx.RegistryHost = host
+	functional.BasicRegistryAPITest(ctx)
+}
+
+//expectEnvChange verifies the update changed an env var whose name contains nameFragment to value, returning the value before the update.
+//Env var names differ between operator versions
+func expectEnvChange(diff *apicurioutils.DeploymentDiff, nameFragment string, value string) string {
+	for _, container := range diff.After.Spec.Template.Spec.Containers {
+		for _, e := range container.Env {
+			if strings.Contains(e.Name, nameFragment) && e.Value == value {
+				before, _ := apicurioutils.EnvValue(diff.Before, e.Name)
+				Expect(before).ToNot(Equal(value), "env var %v already was %v before the update", e.Name, value)
+				return before
+			}
+		}
+	}
+	Expect(fmt.Errorf("no env var like %v set to %v, deployment changes %v", nameFragment, value, diff.Changes)).ToNot(HaveOccurred())
+	return ""
+}
@@ -4,6 +4,7 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils"
+	apicurioutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/deploy"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/functional"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/keycloak"
@@ -13,9 +14,12 @@ import (
 
 var log = logf.Log.WithName("security")
 
+//roleBasedAuthorizationEnv env var enabling the sr-admin, sr-developer and sr-readonly roles in the registry
+const roleBasedAuthorizationEnv string = "ROLE_BASED_AUTHZ_ENABLED"
+
 func Testcase(suiteCtx *types.SuiteContext, namespace string) {
 
-	ctx := &types.TestContext{RegistryNamespace: namespace}
+	ctx := &types.TestContext{RegistryNamespace: namespace, Size: types.SmallSize}
 	keycloakURL := keycloak.DeployKeycloak(suiteCtx, ctx)
 	defer keycloak.RemoveKeycloak(suiteCtx, ctx)
 
@@ -37,16 +41,16 @@ func runStorageSecurityTest(suiteCtx *types.SuiteContext, s *types.TestContext)
 	defer func() {
 		logs.PrintSeparator()
 		logs.SaveLogs(suiteCtx, s)
+		deploy.SaveStorageDiagnostics(suiteCtx, s)
 		deploy.RemoveRegistryDeployment(suiteCtx, s)
 	}()
 
 	deploy.DeployRegistryStorage(suiteCtx, s)
 
-	// if suiteCtx.OnlyTestOperator {
+	//role based authorization has no field of its own in the registry spec, the registry reads the roles from the keycloak tokens
+	apicurioutils.SetRegistryEnvAndWait(suiteCtx, s, roleBasedAuthorizationEnv, "true")
+
 	runSecurityTest(suiteCtx, s)
-	// } else {
-	// 	runFunctionalSecurityTests(suiteCtx, s)
-	// }
 
 }
 
@@ -65,40 +69,15 @@ func runSecurityTest(suiteCtx *types.SuiteContext, testContext *types.TestContex
 	roPwd := "changeme"
 	functional.BasicRegistryAPITestWithAuthentication(testContext, roUser, roPwd)
 
-}
-
-//TODO fix me , java tests do client credentials flow, k8s testsuite is prepared for user/password flow
-// func runFunctionalSecurityTests(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
-
-// 	// info hardcoded in kubefiles/keycloak/*.yaml
-// 	adminUser := "registry-admin"
-// 	adminPwd := "changeme"
-
-// 	devUser := "registry-developer"
-// 	devPwd := "changeme"
+	//the read-only role can't write, every other role has full access
+	functional.AuthenticatedCRUDTest(testContext, adminUser, functional.KeycloakUserAuthenticator(testContext, adminUser, adminPwd))
+	functional.AuthenticatedCRUDTest(testContext, devUser, functional.KeycloakUserAuthenticator(testContext, devUser, devPwd))
+	functional.ReadOnlyAccessTest(testContext, roUser, functional.KeycloakUserAuthenticator(testContext, roUser, roPwd),
+		functional.KeycloakUserAuthenticator(testContext, adminUser, adminPwd))
 
-// 	roUser := "registry-user"
-// 	roPwd := "changeme"
+	//the service account gets the developer role from a hardcoded role mapper of its client, see kubefiles/keycloak/keycloak-realm.yaml
+	saClient := "registry-client-sa"
+	saSecret := "changeme"
+	functional.AuthenticatedCRUDTest(testContext, saClient, functional.KeycloakClientAuthenticator(testContext, saClient, saSecret))
 
-// 	authServerUrl := ctx.RegistryResource.Spec.Configuration.Security.Keycloak.Url
-// 	realm := ctx.RegistryResource.Spec.Configuration.Security.Keycloak.Realm
-
-// 	authTestsCtx := &types.TestContext{
-// 		Storage:                ctx.Storage,
-// 		FunctionalTestsProfile: "auth",
-// 		FunctionalTestsExtraEnv: []string{
-// 			"AUTH_SERVER_URL", authServerUrl,
-// 			"AUTH_REALM", realm,
-
-// 			"AUTH_ADMIN_CLIENT_ID", adminUser,
-
-// 			//TODO create java tests and set env vars
-// 			// "SOURCE_REGISTRY_HOST=" + sourceCtx.RegistryHost,
-// 			// "SOURCE_REGISTRY_PORT=" + sourceCtx.RegistryPort,
-// 			// "DEST_REGISTRY_HOST=" + destCtx.RegistryHost,
-// 			// "DEST_REGISTRY_PORT=" + destCtx.RegistryPort,
-// 		},
-// 	}
-// 	functional.ExecuteRegistryFunctionalTests(suiteCtx, authTestsCtx)
-
-// }
+}
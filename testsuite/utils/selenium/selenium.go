@@ -2,7 +2,6 @@ package selenium
 
 import (
 	"context"
-	"time"
 
 	. "github.com/onsi/gomega"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -18,6 +17,7 @@ import (
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils"
 	kubernetesutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetes"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/logs"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/timeouts"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
 )
 
@@ -53,12 +53,16 @@ func deploySeleniumChrome(suiteCtx *types.SuiteContext) {
 	log.Info("Deploying selenium")
 
 	if !suiteCtx.IsOpenshift {
-		kubernetesutils.WaitForDeploymentReady(suiteCtx.Clientset, 180*time.Second, "ingress-nginx", "ingress-nginx-controller", 1)
+		kubernetesutils.WaitForDeploymentReady(suiteCtx, timeouts.Get(timeouts.IngressControllerReady), "ingress-nginx", "ingress-nginx-controller", 1)
 	}
 
-	kubernetesutils.CreateTestNamespace(suiteCtx.Clientset, seleniumNamespace)
+	profile := seleniumSize(suiteCtx).Profile()
+	kubernetesutils.ValidateClusterCapacity(suiteCtx, "selenium",
+		kubernetesutils.ResourceDemand{Name: "selenium", Pods: 1, Requests: profile.Selenium.Requests})
 
-	err := suiteCtx.K8sClient.Create(context.TODO(), seleniumDeployment(seleniumNamespace))
+	kubernetesutils.CreateTestNamespace(suiteCtx, seleniumNamespace)
+
+	err := suiteCtx.K8sClient.Create(context.TODO(), seleniumDeployment(seleniumNamespace, profile.Selenium))
 	Expect(err).ToNot(HaveOccurred())
 
 	err = suiteCtx.K8sClient.Create(context.TODO(), seleniumService(seleniumNamespace))
@@ -72,7 +76,7 @@ func deploySeleniumChrome(suiteCtx *types.SuiteContext) {
 		Expect(err).ToNot(HaveOccurred())
 	}
 
-	kubernetesutils.WaitForDeploymentReady(suiteCtx.Clientset, 240*time.Second, seleniumNamespace, seleniumName, 1)
+	kubernetesutils.WaitForDeploymentReady(suiteCtx, timeouts.Get(timeouts.SeleniumReady), seleniumNamespace, seleniumName, 1)
 
 	if suiteCtx.IsOpenshift {
 		seleniumRoute, err := suiteCtx.OcpRouteClient.Routes(seleniumNamespace).Get(context.TODO(), seleniumName, metav1.GetOptions{})
@@ -87,7 +91,7 @@ func deploySeleniumChrome(suiteCtx *types.SuiteContext) {
 
 func removeSeleniumChrome(suiteCtx *types.SuiteContext) {
 	log.Info("Removing selenium")
-	err := suiteCtx.K8sClient.Delete(context.TODO(), seleniumDeployment(seleniumNamespace))
+	err := suiteCtx.K8sClient.Delete(context.TODO(), seleniumDeployment(seleniumNamespace, seleniumSize(suiteCtx).Profile().Selenium))
 	Expect(err).ToNot(HaveOccurred())
 	err = suiteCtx.K8sClient.Delete(context.TODO(), seleniumService(seleniumNamespace))
 	Expect(err).ToNot(HaveOccurred())
@@ -98,10 +102,18 @@ func removeSeleniumChrome(suiteCtx *types.SuiteContext) {
 		err = suiteCtx.K8sClient.Delete(context.TODO(), seleniumIngress(seleniumNamespace))
 		Expect(err).ToNot(HaveOccurred())
 	}
-	kubernetesutils.DeleteTestNamespace(suiteCtx.Clientset, seleniumNamespace)
+	kubernetesutils.DeleteTestNamespace(suiteCtx, seleniumNamespace)
+}
+
+//seleniumSize selenium is shared by the whole testsuite, it gets the size the registry deployment tests use on the platform
+func seleniumSize(suiteCtx *types.SuiteContext) types.DeploymentSize {
+	if suiteCtx.IsOpenshift {
+		return types.NormalSize
+	}
+	return types.SmallSize
 }
 
-func seleniumDeployment(namespace string) *v1.Deployment {
+func seleniumDeployment(namespace string, resources corev1.ResourceRequirements) *v1.Deployment {
 	var replicas int32 = 1
 	return &v1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -141,13 +153,7 @@ func seleniumDeployment(namespace string) *v1.Deployment {
 								InitialDelaySeconds: 10,
 								PeriodSeconds:       2,
 							},
-							// Resources: corev1.ResourceRequirements{
-							// 	Requests: corev1.ResourceList{
-							// 		"requests": resource.Quantity{
-
-							// 		}
-							// 	},
-							// },
+							Resources: resources,
 						},
 					},
 				},
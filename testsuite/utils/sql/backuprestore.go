@@ -2,35 +2,37 @@ package sql
 
 import (
 	"context"
-	"net/http"
-	"strconv"
-	"time"
 
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils"
 	apicurioutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio"
-	apicurioclient "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/client"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/seeder"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/snapshot"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/functional"
 	kubernetesutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetes"
 	kubernetescli "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetescli"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/timeouts"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/waiter"
 	apicurio "github.com/Apicurio/apicurio-registry-operator/api/v1"
 )
 
-var artifactData string = "{\"type\":\"record\",\"name\":\"price\",\"namespace\":\"com.example\",\"fields\":[{\"name\":\"symbol\",\"type\":\"string\"},{\"name\":\"price\",\"type\":\"string\"}]}"
+//seedValue seed of the dataset backed up, fixed so failures can be reproduced
+const seedValue int64 = 20210602
+
 var dbplaygroundlabels map[string]string = map[string]string{"apicurio": "dbplayground"}
 
 func ExecuteBackupAndRestoreTestCase(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
 
 	//deploy db and registry
-	backupDBData := DeployPostgresqlDatabase(suiteCtx, ctx.RegistryNamespace, "backupdb", "backupdb", "test", "test")
+	backupDBData := DeployPostgresqlDatabase(suiteCtx, ctx.RegistryNamespace, "backupdb", "backupdb", "test", "test", ctx.Size)
 	ctx.RegisterCleanup(func() {
-		RemovePostgresqlDatabase(suiteCtx.K8sClient, suiteCtx.Clientset, ctx.RegistryNamespace, backupDBData.Name)
+		RemovePostgresqlDatabase(suiteCtx, ctx.RegistryNamespace, backupDBData.Name)
 	})
 
 	backupregistry := apicurio.ApicurioRegistry{
@@ -60,17 +62,15 @@ func ExecuteBackupAndRestoreTestCase(suiteCtx *types.SuiteContext, ctx *types.Te
 	functional.BasicRegistryAPITest(ctx)
 
 	//create artifacts on the registry
-	backupclient := apicurioclient.NewApicurioRegistryApiClient(ctx.RegistryHost, ctx.RegistryPort, http.DefaultClient)
+	backupclient := functional.RegistryClient(ctx, nil)
 
-	for i := 1; i <= 50; i++ {
-		err := backupclient.CreateArtifact("bandr-"+strconv.Itoa(i), apicurioclient.Avro, artifactData)
-		Expect(err).ToNot(HaveOccurred())
-		time.Sleep(1 * time.Second)
-	}
+	manifest, err := seeder.Seed(backupclient, seeder.Options{Seed: seedValue, Prefix: "bandr", Groups: 5, Artifacts: 50})
+	Expect(err).ToNot(HaveOccurred())
 
-	artifacts, err := backupclient.ListArtifacts()
+	log.Info("Test artifacts created", "artifacts", len(manifest.Artifacts), "versions", manifest.VersionsCount())
+
+	beforeBackup, err := snapshot.Take(backupclient)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(len(artifacts)).To(BeIdenticalTo(50))
 
 	// deploy a dummypod to create the backup and store it, and then restore the backup from that pod
 	log.Info("Deploying dbplayground")
@@ -82,12 +82,21 @@ func ExecuteBackupAndRestoreTestCase(suiteCtx *types.SuiteContext, ctx *types.Te
 	}
 	err = suiteCtx.K8sClient.Create(context.TODO(), dbplaygroundDeployment(ctx.RegistryNamespace, dbplaygroundImage))
 	Expect(err).ToNot(HaveOccurred())
-	kubernetesutils.WaitForDeploymentReady(suiteCtx.Clientset, 120*time.Second, ctx.RegistryNamespace, "dbplayground", 1)
-	time.Sleep(2 * time.Second)
-	labelsSet := labels.Set(dbplaygroundlabels)
-	podList, err := suiteCtx.Clientset.CoreV1().Pods(ctx.RegistryNamespace).List(context.TODO(), metav1.ListOptions{LabelSelector: labelsSet.AsSelector().String()})
+	kubernetesutils.WaitForDeploymentReady(suiteCtx, timeouts.Get(timeouts.DbPlaygroundReady), ctx.RegistryNamespace, "dbplayground", 1)
+	//the deployment can be available while the pod is still being replaced, commands are executed in a ready pod
+	dbplaygroundPodName := ""
+	record, err := suiteCtx.Waiter.Timeout(timeouts.Get(timeouts.DbPlaygroundReady)).Describe("dbplayground pod to be ready").
+		ForList(&corev1.PodList{}, func(list client.ObjectList) (bool, error) {
+			for _, pod := range list.(*corev1.PodList).Items {
+				if kubernetesutils.IsPodReady(&pod) {
+					dbplaygroundPodName = pod.Name
+					return true, nil
+				}
+			}
+			return false, nil
+		}, client.InNamespace(ctx.RegistryNamespace), client.MatchingLabels(dbplaygroundlabels))
+	waiter.LogOnError(record, err)
 	Expect(err).ToNot(HaveOccurred())
-	dbplaygroundPodName := podList.Items[0].Name
 	ctx.RegisterCleanup(func() {
 		suiteCtx.Clientset.AppsV1().Deployments(ctx.RegistryNamespace).Delete(context.TODO(), "dbplayground", metav1.DeleteOptions{})
 	})
@@ -98,12 +107,12 @@ func ExecuteBackupAndRestoreTestCase(suiteCtx *types.SuiteContext, ctx *types.Te
 
 	// shut down the registry and the first db
 	apicurioutils.DeleteRegistryAndWait(suiteCtx, ctx.RegistryNamespace, backupregistry.Name)
-	RemovePostgresqlDatabase(suiteCtx.K8sClient, suiteCtx.Clientset, ctx.RegistryNamespace, backupDBData.Name)
+	RemovePostgresqlDatabase(suiteCtx, ctx.RegistryNamespace, backupDBData.Name)
 
 	// deploy the new db, this deployment already creates the database
-	restoreDBData := DeployPostgresqlDatabase(suiteCtx, ctx.RegistryNamespace, "restoredb", "restoredb", "test", "test")
+	restoreDBData := DeployPostgresqlDatabase(suiteCtx, ctx.RegistryNamespace, "restoredb", "restoredb", "test", "test", ctx.Size)
 	ctx.RegisterCleanup(func() {
-		RemovePostgresqlDatabase(suiteCtx.K8sClient, suiteCtx.Clientset, ctx.RegistryNamespace, restoreDBData.Name)
+		RemovePostgresqlDatabase(suiteCtx, ctx.RegistryNamespace, restoreDBData.Name)
 	})
 
 	// restore the backup
@@ -139,10 +148,12 @@ func ExecuteBackupAndRestoreTestCase(suiteCtx *types.SuiteContext, ctx *types.Te
 	functional.BasicRegistryAPITest(ctx)
 
 	// verify new registry have old data
-	restoreclient := apicurioclient.NewApicurioRegistryApiClient(ctx.RegistryHost, ctx.RegistryPort, http.DefaultClient)
-	artifacts, err = restoreclient.ListArtifacts()
+	restoreclient := functional.RegistryClient(ctx, nil)
+	Expect(manifest.Verify(restoreclient)).To(Succeed())
+
+	afterRestore, err := snapshot.Take(restoreclient)
 	Expect(err).ToNot(HaveOccurred())
-	Expect(len(artifacts)).To(BeIdenticalTo(50))
+	Expect(snapshot.Diff(beforeBackup, afterRestore)).To(BeEmpty())
 
 }
 
@@ -2,15 +2,13 @@ package sql
 
 import (
 	"context"
-	"time"
 
 	. "github.com/onsi/gomega"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
-	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils"
-	apicurioutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio"
 	kubernetesutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetes"
 	kubernetescli "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetescli"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/timeouts"
 	types "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
 
 	v1 "k8s.io/api/apps/v1"
@@ -20,68 +18,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-
-	apicurio "github.com/Apicurio/apicurio-registry-operator/api/v1"
 )
 
 var log = logf.Log.WithName("postgresql")
 
-func SqlDeployResource(suiteCtx *types.SuiteContext, ctx *types.TestContext) *apicurio.ApicurioRegistry {
-
-	name := ctx.RegistryName
-	if name == "" {
-		name = "apicurio-registry-" + ctx.Storage
-		ctx.RegistryName = name
-	}
-
-	user := "apicuriouser"
-	password := "password"
-	dataSourceURL := DeployPostgresqlDatabase(suiteCtx, ctx.RegistryNamespace, "db-"+name, "apicurioregistry", user, password).DataSourceURL
-
-	log.Info("Deploying apicurio registry")
-
-	replicas := 1
-	if ctx.Replicas > 0 {
-		replicas = ctx.Replicas
-	}
-
-	registry := apicurio.ApicurioRegistry{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: name,
-		},
-		Spec: apicurio.ApicurioRegistrySpec{
-			Configuration: apicurio.ApicurioRegistrySpecConfiguration{
-				LogLevel:    "DEBUG",
-				Persistence: utils.StorageSql,
-				Sql: apicurio.ApicurioRegistrySpecConfigurationSql{
-					DataSource: apicurio.ApicurioRegistrySpecConfigurationDataSource{
-						Url:      string(dataSourceURL),
-						UserName: user,
-						Password: password,
-					},
-				},
-			},
-			Deployment: apicurio.ApicurioRegistrySpecDeployment{
-				Replicas: int32(replicas),
-			},
-		},
-	}
-
-	return &registry
-}
-
-//RemoveJpaRegistry uninstalls registry CR and postgresql database
-func RemoveJpaRegistry(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
-
-	apicurioutils.DeleteRegistryAndWait(suiteCtx, ctx.RegistryNamespace, ctx.RegistryName)
-
-	RemovePostgresqlDatabase(suiteCtx.K8sClient, suiteCtx.Clientset, ctx.RegistryNamespace, "db-"+ctx.RegistryName)
-
-}
-
 type DbData struct {
 	Name          string
 	Host          string
@@ -106,9 +48,9 @@ func DeployDebeziumPostgresqlDatabase(suiteCtx *types.SuiteContext, namespace st
 	return deployPostgresqlDatabase(suiteCtx, namespace, name, database, user, password, d)
 }
 
-//DeployPostgresqlDatabase deploys a postgresql database
-func DeployPostgresqlDatabase(suiteCtx *types.SuiteContext, namespace string, name string, database string, user string, password string) *DbData {
-	var d *v1.Deployment = deployment(namespace, name, database, user, password)
+//DeployPostgresqlDatabase deploys a postgresql database with the resources of the deployment size
+func DeployPostgresqlDatabase(suiteCtx *types.SuiteContext, namespace string, name string, database string, user string, password string, size types.DeploymentSize) *DbData {
+	var d *v1.Deployment = deployment(namespace, name, database, user, password, size.Profile().Postgresql)
 	return deployPostgresqlDatabase(suiteCtx, namespace, name, database, user, password, d)
 }
 
@@ -124,28 +66,13 @@ func deployPostgresqlDatabase(suiteCtx *types.SuiteContext, namespace string, na
 	err = suiteCtx.K8sClient.Create(context.TODO(), postgresqlService(namespace, name))
 	Expect(err).ToNot(HaveOccurred())
 
-	timeout := 180 * time.Second
-	log.Info("Waiting for postgresql database to be ready ", "timeout", timeout)
-	err = wait.Poll(utils.APIPollInterval, timeout, func() (bool, error) {
-		od, err := suiteCtx.Clientset.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
-		if err != nil && !errors.IsNotFound(err) {
-			return false, err
-		}
-		if od != nil {
-			if od.Status.AvailableReplicas > int32(0) {
-				return true, nil
-			}
-		}
-		return false, nil
-	})
-	kubernetescli.GetPods(namespace)
-	Expect(err).ToNot(HaveOccurred())
+	kubernetesutils.WaitForDeploymentReady(suiteCtx, timeouts.Get(timeouts.PostgresqlReady), namespace, name, 1)
 
 	svc, err := suiteCtx.Clientset.CoreV1().Services(namespace).Get(context.TODO(), name, metav1.GetOptions{})
 	Expect(err).ToNot(HaveOccurred())
 	dbdata := &DbData{
 		Name:          name,
-		DataSourceURL: "jdbc:postgresql://" + svc.Spec.ClusterIP + ":5432/" + database,
+		DataSourceURL: dataSourceURL(svc.Spec.ClusterIP, database),
 		Host:          svc.Spec.ClusterIP,
 		Port:          "5432",
 		Database:      database,
@@ -155,6 +82,10 @@ func deployPostgresqlDatabase(suiteCtx *types.SuiteContext, namespace string, na
 	return dbdata
 }
 
+func dataSourceURL(host string, database string) string {
+	return "jdbc:postgresql://" + host + ":5432/" + database
+}
+
 //GetPostgresqlDatabasePod gets the database pod from the name given when created
 func GetPostgresqlDatabasePod(clientset *kubernetes.Clientset, namespace string, name string) *corev1.Pod {
 	labelsSet := labels.Set(map[string]string{"app": name})
@@ -164,46 +95,25 @@ func GetPostgresqlDatabasePod(clientset *kubernetes.Clientset, namespace string,
 }
 
 //RemovePostgresqlDatabase removes a postgresql database
-func RemovePostgresqlDatabase(k8sclient client.Client, clientset *kubernetes.Clientset, namespace string, name string) {
+func RemovePostgresqlDatabase(suiteCtx *types.SuiteContext, namespace string, name string) {
 	log.Info("Removing postgresql database " + name)
 
-	dep, err := clientset.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
-	if err == nil {
-		err = k8sclient.Delete(context.TODO(), dep)
-		Expect(err).ToNot(HaveOccurred())
-		kubernetesutils.WaitForObjectDeleted("Deployment "+name, func() (interface{}, error) {
-			return clientset.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
-		})
-	} else if !errors.IsNotFound(err) {
-		Expect(err).ToNot(HaveOccurred())
-	}
-
-	pvc, err := clientset.CoreV1().PersistentVolumeClaims(namespace).Get(context.TODO(), name, metav1.GetOptions{})
-	if err == nil {
-		err = k8sclient.Delete(context.TODO(), pvc)
-		Expect(err).ToNot(HaveOccurred())
-		kubernetesutils.WaitForObjectDeleted("PVC "+name, func() (interface{}, error) {
-			return clientset.CoreV1().PersistentVolumeClaims(namespace).Get(context.TODO(), name, metav1.GetOptions{})
-		})
-	} else if !errors.IsNotFound(err) {
-		Expect(err).ToNot(HaveOccurred())
-	}
-
-	svc, err := clientset.CoreV1().Services(namespace).Get(context.TODO(), name, metav1.GetOptions{})
-	if err == nil {
-		err = k8sclient.Delete(context.TODO(), svc)
+	key := client.ObjectKey{Namespace: namespace, Name: name}
+	for _, obj := range []client.Object{&v1.Deployment{}, &corev1.PersistentVolumeClaim{}, &corev1.Service{}} {
+		err := suiteCtx.K8sClient.Get(context.TODO(), key, obj)
+		if errors.IsNotFound(err) {
+			continue
+		}
 		Expect(err).ToNot(HaveOccurred())
-		kubernetesutils.WaitForObjectDeleted("Service "+name, func() (interface{}, error) {
-			return clientset.CoreV1().Services(namespace).Get(context.TODO(), name, metav1.GetOptions{})
-		})
-	} else if !errors.IsNotFound(err) {
+		err = suiteCtx.K8sClient.Delete(context.TODO(), obj)
 		Expect(err).ToNot(HaveOccurred())
+		kubernetesutils.WaitForObjectDeleted(suiteCtx, key, obj)
 	}
 
 	kubernetescli.GetPods(namespace)
 }
 
-func deployment(namespace string, name string, database string, user string, password string) *v1.Deployment {
+func deployment(namespace string, name string, database string, user string, password string, resources corev1.ResourceRequirements) *v1.Deployment {
 	labels := map[string]string{"app": name}
 	var replicas int32 = 1
 	return &v1.Deployment{
@@ -224,8 +134,9 @@ func deployment(namespace string, name string, database string, user string, pas
 				Spec: corev1.PodSpec{
 					Containers: []corev1.Container{
 						{
-							Name:  name,
-							Image: "quay.io/centos7/postgresql-12-centos7:1",
+							Name:      name,
+							Image:     "quay.io/centos7/postgresql-12-centos7:1",
+							Resources: resources,
 							Env: []corev1.EnvVar{
 								{
 									Name:  "POSTGRESQL_ADMIN_PASSWORD",
@@ -0,0 +1,84 @@
+package sql
+
+import (
+	"context"
+	"os"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils"
+	kubernetesutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetes"
+	kubernetescli "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetescli"
+	types "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+
+	apicurio "github.com/Apicurio/apicurio-registry-operator/api/v1"
+)
+
+//credentials and database of the postgresql database deployed as registry storage
+const (
+	registryDatabase string = "apicurioregistry"
+	registryUser     string = "apicuriouser"
+	registryPassword string = "password"
+)
+
+//StorageProvider sql storage, the registry is deployed with a postgresql database deployed in its namespace
+type StorageProvider struct{}
+
+func (p *StorageProvider) Name() string {
+	return utils.StorageSql
+}
+
+func (p *StorageProvider) ResourceDemands(ctx *types.TestContext) []kubernetesutils.ResourceDemand {
+	return []kubernetesutils.ResourceDemand{
+		{Name: "postgresql", Pods: 1, Requests: ctx.Size.Profile().Postgresql.Requests},
+	}
+}
+
+func (p *StorageProvider) Deploy(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
+	DeployPostgresqlDatabase(suiteCtx, ctx.RegistryNamespace, databaseName(ctx), registryDatabase, registryUser, registryPassword, ctx.Size)
+}
+
+func (p *StorageProvider) RegistryResource(suiteCtx *types.SuiteContext, ctx *types.TestContext) *apicurio.ApicurioRegistry {
+	svc, err := suiteCtx.Clientset.CoreV1().Services(ctx.RegistryNamespace).Get(context.TODO(), databaseName(ctx), metav1.GetOptions{})
+	Expect(err).ToNot(HaveOccurred())
+
+	return &apicurio.ApicurioRegistry{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ctx.RegistryName,
+		},
+		Spec: apicurio.ApicurioRegistrySpec{
+			Configuration: apicurio.ApicurioRegistrySpecConfiguration{
+				LogLevel:    "DEBUG",
+				Persistence: utils.StorageSql,
+				Sql: apicurio.ApicurioRegistrySpecConfigurationSql{
+					DataSource: apicurio.ApicurioRegistrySpecConfigurationDataSource{
+						//the service name instead of its cluster ip, so the manifests generated for the registry are the same on every run
+						Url:      dataSourceURL(svc.Name+"."+svc.Namespace, registryDatabase),
+						UserName: registryUser,
+						Password: registryPassword,
+					},
+				},
+			},
+			Deployment: apicurio.ApicurioRegistrySpecDeployment{
+				Replicas: ctx.RegistryReplicas(),
+			},
+		},
+	}
+}
+
+func (p *StorageProvider) Remove(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
+	RemovePostgresqlDatabase(suiteCtx, ctx.RegistryNamespace, databaseName(ctx))
+}
+
+//Diagnostics describes the deployment, pods, volume claim and service of the database
+func (p *StorageProvider) Diagnostics(suiteCtx *types.SuiteContext, ctx *types.TestContext, dir string) {
+	file, err := os.Create(dir + "postgresql.log")
+	Expect(err).ToNot(HaveOccurred())
+	defer file.Close()
+	kubernetescli.RedirectOutput(file, os.Stderr, "describe", "deployment,pods,pvc,service", "-l", "app="+databaseName(ctx), "-n", ctx.RegistryNamespace)
+}
+
+func databaseName(ctx *types.TestContext) string {
+	return "db-" + ctx.RegistryName
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	kubernetesutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetes"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+	apicurio "github.com/Apicurio/apicurio-registry-operator/api/v1"
+)
+
+//Provider deploys and removes one of the storage variants the registry can be deployed with, like a postgresql database or a kafka cluster.
+//Failures are reported with gomega assertions, like the rest of the testsuite utils
+type Provider interface {
+	//Name storage of the test contexts the provider is used for, i.e: utils.StorageSql
+	Name() string
+	//ResourceDemands pods the provider deploys for the test context, checked against the cluster capacity before deploying
+	ResourceDemands(ctx *types.TestContext) []kubernetesutils.ResourceDemand
+	//Deploy deploys the storage for the registry of the test context and waits for it to be ready
+	Deploy(suiteCtx *types.SuiteContext, ctx *types.TestContext)
+	//RegistryResource the registry configured to use the deployed storage, not created yet
+	RegistryResource(suiteCtx *types.SuiteContext, ctx *types.TestContext) *apicurio.ApicurioRegistry
+	//Remove removes the storage, called once the registry is deleted
+	Remove(suiteCtx *types.SuiteContext, ctx *types.TestContext)
+	//Diagnostics saves information about the state of the storage in dir, called before removing it
+	Diagnostics(suiteCtx *types.SuiteContext, ctx *types.TestContext, dir string)
+}
+
+//Registry storage providers by name
+type Registry struct {
+	mutex     sync.RWMutex
+	providers map[string]Provider
+}
+
+//NewRegistry creates a registry with the given providers, panics if two of them have the same name
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{providers: map[string]Provider{}}
+	for _, p := range providers {
+		if err := r.Register(p); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+//Register adds a provider, fails if there is already one with the same name
+func (r *Registry) Register(provider Provider) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, exists := r.providers[provider.Name()]; exists {
+		return fmt.Errorf("storage %v already registered", provider.Name())
+	}
+	r.providers[provider.Name()] = provider
+	return nil
+}
+
+//Get the provider of the storage
+func (r *Registry) Get(name string) (Provider, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	provider, exists := r.providers[name]
+	if !exists {
+		return nil, fmt.Errorf("storage %q not implemented, available storages: %v", name, strings.Join(r.names(), ", "))
+	}
+	return provider, nil
+}
+
+//Names of the registered storages, sorted
+func (r *Registry) Names() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.names()
+}
+
+func (r *Registry) names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
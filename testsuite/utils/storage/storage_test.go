@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	kubernetesutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetes"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+	apicurio "github.com/Apicurio/apicurio-registry-operator/api/v1"
+)
+
+//fakeProvider provider that deploys nothing
+type fakeProvider struct {
+	name string
+}
+
+func (p *fakeProvider) Name() string {
+	return p.name
+}
+
+func (p *fakeProvider) ResourceDemands(ctx *types.TestContext) []kubernetesutils.ResourceDemand {
+	return nil
+}
+
+func (p *fakeProvider) Deploy(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
+}
+
+func (p *fakeProvider) RegistryResource(suiteCtx *types.SuiteContext, ctx *types.TestContext) *apicurio.ApicurioRegistry {
+	return &apicurio.ApicurioRegistry{}
+}
+
+func (p *fakeProvider) Remove(suiteCtx *types.SuiteContext, ctx *types.TestContext) {
+}
+
+func (p *fakeProvider) Diagnostics(suiteCtx *types.SuiteContext, ctx *types.TestContext, dir string) {
+}
+
+func TestGetsTheRegisteredProvidersByName(t *testing.T) {
+	g := NewWithT(t)
+	sql := &fakeProvider{name: "sql"}
+	r := NewRegistry(sql, &fakeProvider{name: "kafkasql"})
+
+	provider, err := r.Get("sql")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(provider).To(BeIdenticalTo(sql))
+
+	external := &fakeProvider{name: "sql-external"}
+	g.Expect(r.Register(external)).To(Succeed())
+	provider, err = r.Get("sql-external")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(provider).To(BeIdenticalTo(external))
+
+	g.Expect(r.Names()).To(Equal([]string{"kafkasql", "sql", "sql-external"}))
+}
+
+func TestFailsForStoragesNotRegisteredListingTheAvailableOnes(t *testing.T) {
+	g := NewWithT(t)
+	r := NewRegistry(&fakeProvider{name: "sql"}, &fakeProvider{name: "kafkasql"})
+	_, err := r.Get("mem")
+	g.Expect(err).To(MatchError(`storage "mem" not implemented, available storages: kafkasql, sql`))
+}
+
+func TestRejectsTwoProvidersWithTheSameName(t *testing.T) {
+	g := NewWithT(t)
+	r := NewRegistry(&fakeProvider{name: "sql"})
+	g.Expect(r.Register(&fakeProvider{name: "sql"})).To(MatchError("storage sql already registered"))
+	g.Expect(func() { NewRegistry(&fakeProvider{name: "sql"}, &fakeProvider{name: "sql"}) }).To(Panic())
+}
@@ -12,19 +12,24 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 
+	routev1 "github.com/openshift/api/route/v1"
 	ocp_route_client "github.com/openshift/client-go/route/clientset/versioned/typed/route/v1"
 
 	utils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils"
 	kubernetesutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetes"
 	kubernetescli "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetescli"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/selenium"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/timeouts"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/waiter"
 
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	apicurioScheme "github.com/Apicurio/apicurio-registry-operator/api/v1"
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	olmapiversioned "github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned"
+	packagev1 "github.com/operator-framework/operator-lifecycle-manager/pkg/package-server/apis/operators/v1"
 	pmversioned "github.com/operator-framework/operator-lifecycle-manager/pkg/package-server/client/clientset/versioned"
 )
 
@@ -36,6 +41,11 @@ var disableClusteredTests bool
 var disableConvertersTests bool
 var disableAuthTests bool
 var olmRunAdvancedTestcases bool
+var disableCapacityCheck bool
+var goldenManifests bool
+var updateGoldenManifests bool
+var timeoutScale string
+var timeoutOverrides string
 
 //SetFlags call this function on init function on test suite package
 func SetFlags() {
@@ -45,6 +55,11 @@ func SetFlags() {
 	flag.BoolVar(&disableConvertersTests, "disable-converters-tests", false, "to disable tests for kafka connect converters")
 	flag.BoolVar(&disableAuthTests, "disable-auth-tests", false, "to disable tests for keycloak authentication")
 	flag.BoolVar(&olmRunAdvancedTestcases, "enable-olm-advanced-tests", false, "to enable advanced tests for OLM testsuite")
+	flag.BoolVar(&disableCapacityCheck, "disable-capacity-check", false, "to deploy without checking the cluster has enough allocatable cpu and memory first, i.e: for clusters with autoscaling")
+	flag.BoolVar(&goldenManifests, "golden-manifests", false, "to compare the manifests the operator generates against the golden files in testsuite/golden")
+	flag.BoolVar(&updateGoldenManifests, "update-golden-manifests", false, "to regenerate the golden files in testsuite/golden with the manifests the operator generates")
+	flag.StringVar(&timeoutScale, "timeout-scale", "", "factor every timeout is multiplied by, i.e: 2 for slow clusters, overrides env var E2E_TIMEOUT_SCALE")
+	flag.StringVar(&timeoutOverrides, "timeouts", "", "comma separated name=duration timeouts, i.e: registry-ready=10m,keycloak-ready=20m, applied after the ones in env var E2E_TIMEOUTS")
 }
 
 //NewSuiteContext creates the SuiteContext instance and loads some data like flags into the context
@@ -77,8 +92,27 @@ func NewSuiteContext(suiteID string) *types.SuiteContext {
 		log.Info("Running Advanced Testcases with OLM deployment")
 	}
 
+	suiteCtx.DisableCapacityCheck = disableCapacityCheck
+	if suiteCtx.DisableCapacityCheck {
+		log.Info("Cluster capacity check disabled")
+	}
+
 	suiteCtx.SetupSelenium = setupSelenium
 
+	suiteCtx.GoldenManifests = goldenManifests
+	suiteCtx.UpdateGoldenManifests = updateGoldenManifests
+	if suiteCtx.UpdateGoldenManifests {
+		log.Info("Updating golden manifests")
+	} else if suiteCtx.GoldenManifests {
+		log.Info("Comparing generated manifests against golden manifests")
+	}
+
+	err := timeouts.Configure(timeoutScale, timeoutOverrides)
+	if err != nil {
+		panic(err)
+	}
+	log.Info("Using timeouts", "profile", timeouts.Current().String())
+
 	return &suiteCtx
 }
 
@@ -104,6 +138,17 @@ func InitSuite(suiteCtx *types.SuiteContext) {
 	err = apicurioScheme.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 
+	//routes are read through the manager client and the waiter, on kubernetes they are never requested
+	err = routev1.Install(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
+
+	//olm resources are awaited through the waiter, so their kinds have to be known by the manager cache
+	err = operatorsv1alpha1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = packagev1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
+
 	suiteCtx.PackageClient = pmversioned.NewForConfigOrDie(suiteCtx.Cfg)
 
 	suiteCtx.OLMClient = olmapiversioned.NewForConfigOrDie(suiteCtx.Cfg)
@@ -121,6 +166,8 @@ func InitSuite(suiteCtx *types.SuiteContext) {
 	suiteCtx.K8sClient = suiteCtx.K8sManager.GetClient()
 	Expect(suiteCtx.K8sClient).ToNot(BeNil())
 
+	suiteCtx.Waiter = waiter.NewWaiter(suiteCtx.K8sManager.GetCache(), scheme.Scheme)
+
 	suiteCtx.Clientset = kubernetes.NewForConfigOrDie(suiteCtx.Cfg)
 	Expect(suiteCtx.Clientset).ToNot(BeNil())
 
@@ -3,6 +3,8 @@ package testcase
 import (
 	"strconv"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	. "github.com/onsi/ginkgo"
@@ -11,9 +13,12 @@ import (
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/apicurio/deploy"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/converters"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/functional"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/golden"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kafkasql"
 	kubernetesutils "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetes"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/logs"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/migration"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/operator"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/security"
 	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/types"
 )
@@ -28,7 +33,10 @@ func CommonTestCases(suiteCtx *types.SuiteContext, namespace string) {
 	}
 	var _ = DescribeTable("registry deployment",
 		func(testContext *types.TestContext) {
-			executeTestCase(suiteCtx, testContext)
+			executeTestOnStorage(suiteCtx, testContext, func() {
+				golden.ManifestsTestCase(suiteCtx, testContext)
+				executeRegistryTests(suiteCtx, testContext)
+			})
 		},
 
 		Entry("storage-sql", &types.TestContext{Storage: utils.StorageSql, RegistryNamespace: namespace, Size: size}),
@@ -63,7 +71,10 @@ func AdvancedTestCases(suiteCtx *types.SuiteContext, namespace string) {
 					//TODO investigate some more
 					return
 				}
-				executeTestCase(suiteCtx, testContext)
+				executeTestOnStorage(suiteCtx, testContext, func() {
+					executeRegistryTests(suiteCtx, testContext)
+					functional.SearchReplicasTestCase(suiteCtx, testContext)
+				})
 			},
 
 			Entry("sql", &types.TestContext{Storage: utils.StorageSql, Replicas: 3}),
@@ -71,6 +82,104 @@ func AdvancedTestCases(suiteCtx *types.SuiteContext, namespace string) {
 		)
 	}
 
+	var _ = DescribeTable("registry api",
+		func(testContext *types.TestContext) {
+			executeTestOnStorage(suiteCtx, testContext, func() {
+				functional.BasicRegistryAPITest(testContext)
+				functional.ArtifactTypesTestCase(testContext)
+				functional.RulesTestCase(testContext)
+				functional.AdminTestCase(testContext)
+				functional.ProbesTestCase(suiteCtx, testContext)
+			})
+		},
+
+		Entry("sql", &types.TestContext{Storage: utils.StorageSql, RegistryNamespace: namespace, Size: types.SmallSize}),
+		Entry("kafkasql", &types.TestContext{Storage: utils.StorageKafkaSql, RegistryNamespace: namespace, Size: types.SmallSize}),
+		Entry("sql-tls", &types.TestContext{Storage: utils.StorageSql, RegistryNamespace: namespace, Size: types.SmallSize, RegistryTLS: &types.TLSConfig{}}),
+	)
+
+	var _ = DescribeTable("ccompat api",
+		func(testContext *types.TestContext) {
+			executeTestOnStorage(suiteCtx, testContext, func() {
+				functional.BasicRegistryAPITest(testContext)
+				functional.CCompatTestCase(testContext)
+			})
+		},
+
+		Entry("sql", &types.TestContext{Storage: utils.StorageSql, RegistryNamespace: namespace, Size: types.SmallSize}),
+		Entry("kafkasql", &types.TestContext{Storage: utils.StorageKafkaSql, RegistryNamespace: namespace, Size: types.SmallSize}),
+	)
+
+	var _ = DescribeTable("artifact references",
+		func(testContext *types.TestContext) {
+			executeTestOnStorage(suiteCtx, testContext, func() {
+				functional.BasicRegistryAPITest(testContext)
+				functional.ReferencesTestCase(suiteCtx, testContext)
+			})
+		},
+
+		Entry("sql", &types.TestContext{Storage: utils.StorageSql, RegistryNamespace: namespace, Size: types.SmallSize}),
+		Entry("kafkasql", &types.TestContext{Storage: utils.StorageKafkaSql, RegistryNamespace: namespace, Size: types.SmallSize}),
+	)
+
+	var _ = DescribeTable("registry updates",
+		func(testContext *types.TestContext) {
+			executeTestOnStorage(suiteCtx, testContext, func() {
+				functional.BasicRegistryAPITest(testContext)
+				operator.UpdateTestCases(suiteCtx, testContext)
+			})
+		},
+
+		Entry("sql", &types.TestContext{Storage: utils.StorageSql, RegistryNamespace: namespace, Size: types.SmallSize}),
+	)
+
+	var _ = DescribeTable("reconciliation drift",
+		func(testContext *types.TestContext) {
+			executeTestOnStorage(suiteCtx, testContext, func() {
+				functional.BasicRegistryAPITest(testContext)
+				operator.DriftTestCase(suiteCtx, testContext)
+			})
+		},
+
+		Entry("sql", &types.TestContext{Storage: utils.StorageSql, RegistryNamespace: namespace, Size: types.SmallSize}),
+	)
+
+	var _ = DescribeTable("pod template customization",
+		func(testContext *types.TestContext) {
+			pruned := operator.PodTemplatePrunedFields(suiteCtx, testContext)
+			executeTestOnStorage(suiteCtx, testContext, func() {
+				operator.PodTemplateTestCase(suiteCtx, testContext, pruned)
+			})
+		},
+
+		Entry("env", &types.TestContext{Storage: utils.StorageSql, RegistryNamespace: namespace, Size: types.SmallSize, PodTemplate: &types.PodTemplate{
+			Env: []corev1.EnvVar{{Name: "E2E_CUSTOM_ENV", Value: "custom-value"}},
+		}}),
+		Entry("resources", &types.TestContext{Storage: utils.StorageSql, RegistryNamespace: namespace, Size: types.SmallSize, PodTemplate: &types.PodTemplate{
+			Resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m"), corev1.ResourceMemory: resource.MustParse("600Mi")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("1200Mi")},
+			},
+		}}),
+		//the deployment spec of every operator has affinity and tolerations, so the pods are verified with the operator in go.mod too
+		Entry("affinity and tolerations", &types.TestContext{Storage: utils.StorageSql, RegistryNamespace: namespace, Size: types.SmallSize, PodTemplate: &types.PodTemplate{
+			Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "kubernetes.io/os", Operator: corev1.NodeSelectorOpIn, Values: []string{"linux"}}},
+				}}},
+			}},
+			Tolerations: []corev1.Toleration{{Key: "e2e.apicur.io/dedicated", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+		}}),
+		Entry("annotations and labels", &types.TestContext{Storage: utils.StorageSql, RegistryNamespace: namespace, Size: types.SmallSize, PodTemplate: &types.PodTemplate{
+			Annotations: map[string]string{"e2e.apicur.io/annotation": "custom-annotation"},
+			Labels:      map[string]string{"e2e.apicur.io/label": "custom-label"},
+		}}),
+		Entry("volumes", &types.TestContext{Storage: utils.StorageSql, RegistryNamespace: namespace, Size: types.SmallSize, PodTemplate: &types.PodTemplate{
+			Volumes:      []corev1.Volume{{Name: "e2e-extra", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+			VolumeMounts: []corev1.VolumeMount{{Name: "e2e-extra", MountPath: "/e2e-extra"}},
+		}}),
+	)
+
 	if suiteCtx.OnlyTestOperator {
 		var _ = DescribeTable("security",
 			func(testContext *types.TestContext) {
@@ -95,23 +204,25 @@ func AdvancedTestCases(suiteCtx *types.SuiteContext, namespace string) {
 			)
 		}
 
-		//migration tests are executed and managed by integration tests testsuite, not longer supported to run from k8s testsuite
-		// var _ = DescribeTable("data migration",
-		// 	func(testContext *types.TestContext) {
-		// 		defer SaveLogsAndExecuteTestCleanups(suiteCtx, testContext)
-		// 		migration.DataMigrationTestcase(suiteCtx, testContext)
-		// 	},
-
-		// 	Entry("sql", &types.TestContext{Storage: utils.StorageSql, ID: utils.StorageSql, RegistryNamespace: utils.OperatorNamespace}),
-		// 	Entry("kafkasql", &types.TestContext{Storage: utils.StorageKafkaSql, ID: utils.StorageKafkaSql, RegistryNamespace: utils.OperatorNamespace}),
-		// )
-
-		// var _ = It("backup and restore", func() {
-		// 	ctx := &types.TestContext{}
-		// 	ctx.RegistryNamespace = utils.OperatorNamespace
-		// 	defer SaveLogsAndExecuteTestCleanups(suiteCtx, ctx)
-		// 	sql.ExecuteBackupAndRestoreTestCase(suiteCtx, ctx)
-		// })
+		var _ = DescribeTable("data migration",
+			func(testContext *types.TestContext) {
+				defer SaveLogsAndExecuteTestCleanups(suiteCtx, testContext)
+				migration.DataMigrationTestcase(suiteCtx, testContext)
+			},
+
+			Entry("sql", &types.TestContext{Storage: utils.StorageSql, ID: utils.StorageSql, RegistryNamespace: namespace}),
+			Entry("kafkasql", &types.TestContext{Storage: utils.StorageKafkaSql, ID: utils.StorageKafkaSql, RegistryNamespace: namespace}),
+		)
+
+		var _ = DescribeTable("backup and restore",
+			func(testContext *types.TestContext) {
+				defer SaveLogsAndExecuteTestCleanups(suiteCtx, testContext)
+				migration.BackupAndRestoreTestcase(suiteCtx, testContext)
+			},
+
+			Entry("sql", &types.TestContext{Storage: utils.StorageSql, ID: utils.StorageSql, RegistryNamespace: namespace}),
+			Entry("kafkasql", &types.TestContext{Storage: utils.StorageKafkaSql, ID: utils.StorageKafkaSql, RegistryNamespace: namespace}),
+		)
 	}
 
 }
@@ -130,12 +241,12 @@ func MultinamespacedTestCase(suiteCtx *types.SuiteContext) {
 			}
 			contexts = append(contexts, ctx)
 
-			kubernetesutils.CreateTestNamespace(suiteCtx.Clientset, ctx.RegistryNamespace)
+			kubernetesutils.CreateTestNamespace(suiteCtx, ctx.RegistryNamespace)
 		}
 
 		cleanup := func() {
 			for i := range contexts {
-				defer kubernetesutils.DeleteTestNamespace(suiteCtx.Clientset, contexts[i].RegistryNamespace)
+				defer kubernetesutils.DeleteTestNamespace(suiteCtx, contexts[i].RegistryNamespace)
 				contexts[i].RegisterCleanup(func() {
 					deploy.RemoveRegistryDeployment(suiteCtx, contexts[i])
 				})
@@ -158,20 +269,25 @@ func MultinamespacedTestCase(suiteCtx *types.SuiteContext) {
 //ExecuteTestCase common logic to test operator deploying an instance of ApicurioRegistry with one of it's storage variants
 func executeTestCase(suiteCtx *types.SuiteContext, testContext *types.TestContext) {
 	executeTestOnStorage(suiteCtx, testContext, func() {
-		if !suiteCtx.OnlyTestOperator {
-			//shared kafka deployment in k8s
-			sharedKafkaCluster := kafkasql.DeploySharedKafkaIfNeeded(suiteCtx, testContext)
-			if sharedKafkaCluster != nil {
-				defer kafkasql.RemoveSharedKafkaIfNeeded(suiteCtx, testContext, sharedKafkaCluster)
-				testContext.FunctionalTestsSharedKafkaCluster = sharedKafkaCluster
-			}
-			functional.ExecuteRegistryFunctionalTests(suiteCtx, testContext)
-		} else {
-			functional.BasicRegistryAPITest(testContext)
-		}
+		executeRegistryTests(suiteCtx, testContext)
 	})
 }
 
+//executeRegistryTests runs the registry functional tests, or only the basic api test when testing just the operator
+func executeRegistryTests(suiteCtx *types.SuiteContext, testContext *types.TestContext) {
+	if !suiteCtx.OnlyTestOperator {
+		//shared kafka deployment in k8s
+		sharedKafkaCluster := kafkasql.DeploySharedKafkaIfNeeded(suiteCtx, testContext)
+		if sharedKafkaCluster != nil {
+			defer kafkasql.RemoveSharedKafkaIfNeeded(suiteCtx, testContext, sharedKafkaCluster)
+			testContext.FunctionalTestsSharedKafkaCluster = sharedKafkaCluster
+		}
+		functional.ExecuteRegistryFunctionalTests(suiteCtx, testContext)
+	} else {
+		functional.BasicRegistryAPITest(testContext)
+	}
+}
+
 //ExecuteTestOnStorage extensible logic to test apicurio registry functionality deployed with one of it's storage variants
 func executeTestOnStorage(suiteCtx *types.SuiteContext, testContext *types.TestContext, testFunction func()) {
 	//implement here support for multiple namespaces
@@ -194,6 +310,7 @@ func SaveLogsAndExecuteTestCleanups(suiteCtx *types.SuiteContext, ctx *types.Tes
 	logs.PrintSeparator()
 
 	logs.SaveLogs(suiteCtx, ctx)
+	deploy.SaveStorageDiagnostics(suiteCtx, ctx)
 
 	ctx.ExecuteCleanups()
 }
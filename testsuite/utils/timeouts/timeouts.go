@@ -0,0 +1,284 @@
+package timeouts
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	timeoutScaleEnvVar = "E2E_TIMEOUT_SCALE"
+	timeoutsEnvVar     = "E2E_TIMEOUTS"
+)
+
+//Name identifies one timeout or poll interval of the profile, it's the key used to override it
+type Name string
+
+//timeouts of the registry and the operator
+const (
+	RegistryCR                Name = "registry-cr"
+	RegistryReady             Name = "registry-ready"
+	ClusteredRegistryReady    Name = "clustered-registry-ready"
+	RegistryRoute             Name = "registry-route"
+	RegistryTLS               Name = "registry-tls"
+	RegistryRestart           Name = "registry-restart"
+	RegistryRollout           Name = "registry-rollout"
+	RegistryCRRemoval         Name = "registry-cr-removal"
+	RegistryDeploymentRemoval Name = "registry-deployment-removal"
+	RegistryAPI               Name = "registry-api"
+	RegistryUnauthorized      Name = "registry-unauthorized"
+	SearchConsistency         Name = "search-consistency"
+	OperatorReady             Name = "operator-ready"
+	OperatorRemoval           Name = "operator-removal"
+	OperatorConvergence       Name = "operator-convergence"
+)
+
+//timeouts of kubernetes and olm resources
+const (
+	NamespaceRemoval       Name = "namespace-removal"
+	ObjectRemoval          Name = "object-removal"
+	ServiceAccount         Name = "service-account"
+	IngressControllerReady Name = "ingress-controller-ready"
+	CatalogSource          Name = "catalog-source"
+	CatalogSourcePod       Name = "catalog-source-pod"
+	CatalogSourcePodReady  Name = "catalog-source-pod-ready"
+	CatalogSourceReady     Name = "catalog-source-ready"
+	PackageManifest        Name = "package-manifest"
+	SubscriptionUpdate     Name = "subscription-update"
+	CSVReady               Name = "csv-ready"
+)
+
+//timeouts of the services registries are tested with
+const (
+	PostgresqlReady         Name = "postgresql-ready"
+	DbPlaygroundReady       Name = "dbplayground-ready"
+	StrimziOperatorReady    Name = "strimzi-operator-ready"
+	StrimziOperatorRemoval  Name = "strimzi-operator-removal"
+	KafkaClusterReady       Name = "kafka-cluster-ready"
+	KafkaClusterRemoval     Name = "kafka-cluster-removal"
+	KafkaCASecret           Name = "kafka-ca-secret"
+	KafkaConnectReady       Name = "kafka-connect-ready"
+	KafkaConnectRemoval     Name = "kafka-connect-removal"
+	KafkaConsumer           Name = "kafka-consumer"
+	DebeziumReady           Name = "debezium-ready"
+	DebeziumConnector       Name = "debezium-connector"
+	DebeziumIngress         Name = "debezium-ingress"
+	KeycloakReady           Name = "keycloak-ready"
+	KeycloakRoute           Name = "keycloak-route"
+	KeycloakRemoval         Name = "keycloak-removal"
+	KeycloakOperatorReady   Name = "keycloak-operator-ready"
+	KeycloakOperatorRemoval Name = "keycloak-operator-removal"
+	SeleniumReady           Name = "selenium-ready"
+)
+
+//poll intervals, they are not scaled
+const (
+	APIPollInterval    Name = "api-poll-interval"
+	MediumPollInterval Name = "medium-poll-interval"
+	LongPollInterval   Name = "long-poll-interval"
+)
+
+var defaultTimeouts = map[Name]time.Duration{
+	RegistryCR:                15 * time.Second,
+	RegistryReady:             180 * time.Second,
+	ClusteredRegistryReady:    300 * time.Second,
+	RegistryRoute:             90 * time.Second,
+	RegistryTLS:               60 * time.Second,
+	RegistryRestart:           180 * time.Second,
+	RegistryRollout:           300 * time.Second,
+	RegistryCRRemoval:         15 * time.Second,
+	RegistryDeploymentRemoval: 30 * time.Second,
+	RegistryAPI:               60 * time.Second,
+	RegistryUnauthorized:      20 * time.Second,
+	SearchConsistency:         90 * time.Second,
+	OperatorReady:             500 * time.Second,
+	OperatorRemoval:           60 * time.Second,
+	OperatorConvergence:       120 * time.Second,
+
+	NamespaceRemoval:       60 * time.Second,
+	ObjectRemoval:          30 * time.Second,
+	ServiceAccount:         10 * time.Second,
+	IngressControllerReady: 180 * time.Second,
+	CatalogSource:          200 * time.Second,
+	CatalogSourcePod:       90 * time.Second,
+	CatalogSourcePodReady:  120 * time.Second,
+	CatalogSourceReady:     300 * time.Second,
+	PackageManifest:        540 * time.Second,
+	SubscriptionUpdate:     120 * time.Second,
+	CSVReady:               160 * time.Second,
+
+	PostgresqlReady:         180 * time.Second,
+	DbPlaygroundReady:       120 * time.Second,
+	StrimziOperatorReady:    180 * time.Second,
+	StrimziOperatorRemoval:  120 * time.Second,
+	KafkaClusterReady:       10 * time.Minute,
+	KafkaClusterRemoval:     120 * time.Second,
+	KafkaCASecret:           1 * time.Minute,
+	KafkaConnectReady:       10 * time.Minute,
+	KafkaConnectRemoval:     120 * time.Second,
+	KafkaConsumer:           60 * time.Second,
+	DebeziumReady:           120 * time.Second,
+	DebeziumConnector:       45 * time.Second,
+	DebeziumIngress:         60 * time.Second,
+	KeycloakReady:           13 * time.Minute,
+	KeycloakRoute:           90 * time.Second,
+	KeycloakRemoval:         3 * time.Minute,
+	KeycloakOperatorReady:   180 * time.Second,
+	KeycloakOperatorRemoval: 120 * time.Second,
+	SeleniumReady:           240 * time.Second,
+}
+
+var defaultIntervals = map[Name]time.Duration{
+	APIPollInterval:    2 * time.Second,
+	MediumPollInterval: 5 * time.Second,
+	LongPollInterval:   10 * time.Second,
+}
+
+//Profile timeouts and poll intervals of a testsuite run.
+//Timeouts are the default multiplied by Scale unless overridden, overrides are used as they are
+type Profile struct {
+	Scale     float64
+	Overrides map[Name]time.Duration
+}
+
+var mu sync.RWMutex
+
+//current profile loaded from E2E_TIMEOUT_SCALE and E2E_TIMEOUTS, flags can replace it with Configure
+var current *Profile = loadFromEnv()
+
+func loadFromEnv() *Profile {
+	profile, err := NewProfile(os.Getenv(timeoutScaleEnvVar), os.Getenv(timeoutsEnvVar))
+	if err != nil {
+		panic(fmt.Sprintf("invalid timeouts configuration, %v", err))
+	}
+	return profile
+}
+
+//NewProfile creates a profile from a scale, like "2" or "1.5", empty means 1,
+//and overrides like "registry-ready=10m,keycloak-ready=20m"
+func NewProfile(scale string, overrides string) (*Profile, error) {
+	profile := &Profile{Scale: 1, Overrides: map[Name]time.Duration{}}
+	if scale != "" {
+		value, err := strconv.ParseFloat(scale, 64)
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("scale %q is not a positive number", scale)
+		}
+		profile.Scale = value
+	}
+	for _, override := range strings.Split(overrides, ",") {
+		override = strings.TrimSpace(override)
+		if override == "" {
+			continue
+		}
+		parts := strings.SplitN(override, "=", 2)
+		name := Name(strings.TrimSpace(parts[0]))
+		if !isKnown(name) {
+			return nil, fmt.Errorf("unknown timeout %q, known timeouts are %v", name, strings.Join(Names(), ", "))
+		}
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("missing duration of timeout %v", name)
+		}
+		duration, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("invalid duration of timeout %v: %q", name, parts[1])
+		}
+		profile.Overrides[name] = duration
+	}
+	return profile, nil
+}
+
+//Configure replaces the current profile, empty values keep the ones from the environment
+func Configure(scale string, overrides string) error {
+	if scale == "" {
+		scale = os.Getenv(timeoutScaleEnvVar)
+	}
+	envOverrides := os.Getenv(timeoutsEnvVar)
+	if envOverrides != "" && overrides != "" {
+		//later overrides win, so the flag takes precedence over the env var
+		overrides = envOverrides + "," + overrides
+	} else if overrides == "" {
+		overrides = envOverrides
+	}
+	profile, err := NewProfile(scale, overrides)
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	current = profile
+	return nil
+}
+
+//Current the profile in use
+func Current() *Profile {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+//Get the timeout of the current profile
+func Get(name Name) time.Duration {
+	return Current().Timeout(name)
+}
+
+//Interval the poll interval of the current profile
+func Interval(name Name) time.Duration {
+	return Current().Interval(name)
+}
+
+//Timeout the override of the named timeout, or its default scaled. Panics for unknown timeouts
+func (p *Profile) Timeout(name Name) time.Duration {
+	if override, exists := p.Overrides[name]; exists {
+		return override
+	}
+	timeout, exists := defaultTimeouts[name]
+	if !exists {
+		panic("unknown timeout " + name)
+	}
+	return time.Duration(float64(timeout) * p.Scale)
+}
+
+//Interval the override of the named poll interval, or its default. Panics for unknown intervals
+func (p *Profile) Interval(name Name) time.Duration {
+	if override, exists := p.Overrides[name]; exists {
+		return override
+	}
+	interval, exists := defaultIntervals[name]
+	if !exists {
+		panic("unknown poll interval " + name)
+	}
+	return interval
+}
+
+//String the scale and the overrides of the profile
+func (p *Profile) String() string {
+	overrides := []string{}
+	for name, duration := range p.Overrides {
+		overrides = append(overrides, string(name)+"="+duration.String())
+	}
+	sort.Strings(overrides)
+	return fmt.Sprintf("scale=%v overrides=[%v]", p.Scale, strings.Join(overrides, ","))
+}
+
+//Names sorted names of every timeout and poll interval
+func Names() []string {
+	names := []string{}
+	for name := range defaultTimeouts {
+		names = append(names, string(name))
+	}
+	for name := range defaultIntervals {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func isKnown(name Name) bool {
+	_, isTimeout := defaultTimeouts[name]
+	_, isInterval := defaultIntervals[name]
+	return isTimeout || isInterval
+}
@@ -0,0 +1,91 @@
+package timeouts
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+//resetConfigurationOnCleanup configures the default profile again when the test ends, clearing the env vars it set
+func resetConfigurationOnCleanup(t *testing.T) {
+	t.Cleanup(func() {
+		os.Unsetenv(timeoutScaleEnvVar)
+		os.Unsetenv(timeoutsEnvVar)
+		if err := Configure("", ""); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestUsesTheDefaults(t *testing.T) {
+	g := NewWithT(t)
+	profile, err := NewProfile("", "")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(profile.Timeout(RegistryReady)).To(Equal(180 * time.Second))
+	g.Expect(profile.Timeout(KeycloakReady)).To(Equal(13 * time.Minute))
+	g.Expect(profile.Interval(APIPollInterval)).To(Equal(2 * time.Second))
+}
+
+func TestScalesTimeoutsButNotPollIntervalsNorOverrides(t *testing.T) {
+	g := NewWithT(t)
+	profile, err := NewProfile("2", "registry-ready=10m, api-poll-interval=1s")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(profile.Timeout(PackageManifest)).To(Equal(1080 * time.Second))
+	g.Expect(profile.Timeout(RegistryReady)).To(Equal(10 * time.Minute))
+	g.Expect(profile.Interval(APIPollInterval)).To(Equal(time.Second))
+	g.Expect(profile.Interval(MediumPollInterval)).To(Equal(5 * time.Second))
+	g.Expect(profile.String()).To(Equal("scale=2 overrides=[api-poll-interval=1s,registry-ready=10m0s]"))
+
+	profile, err = NewProfile("1.5", "")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(profile.Timeout(RegistryCR)).To(Equal(22500 * time.Millisecond))
+}
+
+func TestRejectsInvalidConfigurations(t *testing.T) {
+	g := NewWithT(t)
+	for _, invalid := range [][]string{
+		{"0", ""},
+		{"-1", ""},
+		{"fast", ""},
+		{"", "registry-ready"},
+		{"", "registry-ready=soon"},
+		{"", "registry-ready=-1m"},
+		{"", "unknown=1m"},
+	} {
+		_, err := NewProfile(invalid[0], invalid[1])
+		g.Expect(err).To(HaveOccurred(), "%v", invalid)
+	}
+}
+
+func TestHasADefaultForEveryTimeoutName(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(Names()).To(HaveLen(len(defaultTimeouts) + len(defaultIntervals)))
+	g.Expect(func() { Current().Timeout(APIPollInterval) }).To(Panic())
+	g.Expect(func() { Current().Interval(RegistryReady) }).To(Panic())
+}
+
+func TestConfigureAppliesFlagOverridesAfterTheEnvVarOnes(t *testing.T) {
+	g := NewWithT(t)
+	resetConfigurationOnCleanup(t)
+	os.Setenv(timeoutScaleEnvVar, "3")
+	os.Setenv(timeoutsEnvVar, "registry-ready=5m,keycloak-ready=20m")
+
+	g.Expect(Configure("", "registry-ready=7m")).To(Succeed())
+	g.Expect(Get(RegistryReady)).To(Equal(7 * time.Minute))
+	g.Expect(Get(KeycloakReady)).To(Equal(20 * time.Minute))
+	g.Expect(Get(RegistryCR)).To(Equal(45 * time.Second))
+
+	g.Expect(Configure("2", "")).To(Succeed())
+	g.Expect(Get(RegistryCR)).To(Equal(30 * time.Second))
+	g.Expect(Get(RegistryReady)).To(Equal(5 * time.Minute))
+}
+
+func TestConfigureKeepsTheCurrentProfileOnErrors(t *testing.T) {
+	g := NewWithT(t)
+	resetConfigurationOnCleanup(t)
+	g.Expect(Configure("2", "")).To(Succeed())
+	g.Expect(Configure("", "registry-ready=never")).ToNot(Succeed())
+	g.Expect(Current().Scale).To(Equal(2.0))
+}
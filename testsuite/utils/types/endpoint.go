@@ -0,0 +1,66 @@
+package types
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+)
+
+//TLSConfig trust settings used to connect to a registry exposed with https
+type TLSConfig struct {
+	//CABundle PEM encoded certificates trusted in addition to the system ones, i.e. the router CA of a re-encrypt Route
+	CABundle []byte
+	//InsecureSkipVerify disables server certificate verification, use only for self-signed setups without a CA bundle at hand
+	InsecureSkipVerify bool
+}
+
+//RegistryEndpoint address of a registry as seen from the testsuite
+type RegistryEndpoint struct {
+	Scheme string
+	Host   string
+	Port   string
+	TLS    *TLSConfig
+}
+
+//RegistryEndpoint returns the external endpoint of the registry under test, https is used when RegistryTLS is set
+func (ctx *TestContext) RegistryEndpoint() *RegistryEndpoint {
+	endpoint := &RegistryEndpoint{
+		Scheme: "http",
+		Host:   ctx.RegistryHost,
+		Port:   ctx.RegistryPort,
+	}
+	if ctx.RegistryTLS != nil {
+		endpoint.Scheme = "https"
+		endpoint.TLS = ctx.RegistryTLS
+	}
+	return endpoint
+}
+
+//URL base url of the endpoint, without trailing slash
+func (e *RegistryEndpoint) URL() string {
+	return e.Scheme + "://" + e.Host + ":" + e.Port
+}
+
+//HTTPClient returns an http client able to connect to the endpoint, trusting the configured CA bundle
+func (e *RegistryEndpoint) HTTPClient() (*http.Client, error) {
+	if e.TLS == nil {
+		return http.DefaultClient, nil
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: e.TLS.InsecureSkipVerify}
+	if len(e.TLS.CABundle) != 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(e.TLS.CABundle) {
+			return nil, errors.New("no valid certificates found in registry CA bundle")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: transport}, nil
+}
@@ -0,0 +1,25 @@
+package types
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+//PodTemplate customizations of the registry pods requested through the ApicurioRegistry spec, empty fields are not requested.
+//Affinity and tolerations are part of the deployment spec, env vars and the rest need an operator supporting
+//spec.configuration.env and spec.deployment.podTemplateSpecPreview
+type PodTemplate struct {
+	Env          []corev1.EnvVar
+	Resources    *corev1.ResourceRequirements
+	Affinity     *corev1.Affinity
+	Tolerations  []corev1.Toleration
+	Annotations  map[string]string
+	Labels       map[string]string
+	Volumes      []corev1.Volume
+	VolumeMounts []corev1.VolumeMount
+}
+
+//IsEmpty true if no customization is requested
+func (pt *PodTemplate) IsEmpty() bool {
+	return len(pt.Env) == 0 && pt.Resources == nil && pt.Affinity == nil && len(pt.Tolerations) == 0 &&
+		len(pt.Annotations) == 0 && len(pt.Labels) == 0 && len(pt.Volumes) == 0 && len(pt.VolumeMounts) == 0
+}
@@ -0,0 +1,56 @@
+package types
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+//ResourceProfile cpu and memory requests and limits of the deployments the testsuite creates for a deployment size
+type ResourceProfile struct {
+	Registry   corev1.ResourceRequirements
+	Postgresql corev1.ResourceRequirements
+	Kafka      corev1.ResourceRequirements
+	Zookeeper  corev1.ResourceRequirements
+	Keycloak   corev1.ResourceRequirements
+	Selenium   corev1.ResourceRequirements
+	//KafkaNodes number of kafka brokers of the kafka clusters used as registry storage
+	KafkaNodes int
+}
+
+//Profile the resource profile of the deployment size, sizes not set or unknown get the NormalSize profile.
+//NormalSize registry resources are the defaults of the operator
+func (size DeploymentSize) Profile() ResourceProfile {
+	if size == SmallSize {
+		return ResourceProfile{
+			Registry:   requirements("250m", "512Mi", "500m", "1Gi"),
+			Postgresql: requirements("100m", "128Mi", "250m", "256Mi"),
+			Kafka:      requirements("250m", "512Mi", "500m", "1Gi"),
+			Zookeeper:  requirements("100m", "256Mi", "250m", "512Mi"),
+			Keycloak:   requirements("250m", "512Mi", "500m", "1Gi"),
+			Selenium:   requirements("250m", "512Mi", "500m", "1Gi"),
+			KafkaNodes: 1,
+		}
+	}
+	return ResourceProfile{
+		Registry:   requirements("500m", "512Mi", "1", "1300Mi"),
+		Postgresql: requirements("250m", "256Mi", "500m", "512Mi"),
+		Kafka:      requirements("500m", "1Gi", "1", "2Gi"),
+		Zookeeper:  requirements("250m", "512Mi", "500m", "1Gi"),
+		Keycloak:   requirements("500m", "1Gi", "1", "2Gi"),
+		Selenium:   requirements("500m", "1Gi", "1", "2Gi"),
+		KafkaNodes: 3,
+	}
+}
+
+func requirements(cpuRequest string, memoryRequest string, cpuLimit string, memoryLimit string) corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpuRequest),
+			corev1.ResourceMemory: resource.MustParse(memoryRequest),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpuLimit),
+			corev1.ResourceMemory: resource.MustParse(memoryLimit),
+		},
+	}
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -11,6 +12,7 @@ import (
 	ocp_route_client "github.com/openshift/client-go/route/clientset/versioned/typed/route/v1"
 
 	kubernetescli "github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/kubernetescli"
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/waiter"
 
 	apicurio "github.com/Apicurio/apicurio-registry-operator/api/v1"
 	operatorsv1 "github.com/operator-framework/api/pkg/operators/v1"
@@ -29,12 +31,15 @@ type TestContext struct {
 	Auth          bool
 	Size          DeploymentSize
 	KafkaSecurity kafkaSecurity
+	//PodTemplate customizations of the registry pods, applied when the registry is created
+	PodTemplate *PodTemplate
 
 	RegistryNamespace string
 
 	RegistryName         string
 	RegistryHost         string
 	RegistryPort         string
+	RegistryTLS          *TLSConfig
 	RegistryInternalHost string
 	RegistryInternalPort string
 
@@ -54,6 +59,8 @@ type TestContext struct {
 }
 
 type kafkaSecurity string
+
+//DeploymentSize resources of the deployments created for a test, see Profile
 type DeploymentSize string
 
 var (
@@ -64,6 +71,14 @@ var (
 	Tls   kafkaSecurity = kafkaSecurity("tls")
 )
 
+//RegistryReplicas replicas the registry of the test context is deployed with, 1 if not set
+func (ctx *TestContext) RegistryReplicas() int32 {
+	if ctx.Replicas > 0 {
+		return int32(ctx.Replicas)
+	}
+	return 1
+}
+
 func (ctx *TestContext) RegisterCleanup(cleanup func()) {
 	ctx.cleanupFunctions = append(ctx.cleanupFunctions, cleanup)
 }
@@ -78,10 +93,12 @@ func (ctx *TestContext) ExecuteCleanups() {
 
 //SuiteContext holds common info used in a testsuite
 type SuiteContext struct {
-	SuiteID       string
-	Cfg           *rest.Config
-	K8sClient     client.Client
-	K8sManager    ctrl.Manager
+	SuiteID    string
+	Cfg        *rest.Config
+	K8sClient  client.Client
+	K8sManager ctrl.Manager
+	//Waiter waits for objects using the informers of K8sManager
+	Waiter        *waiter.Waiter
 	TestEnv       *envtest.Environment
 	PackageClient pmversioned.Interface
 	OLMClient     olmapiversioned.Interface
@@ -97,6 +114,19 @@ type SuiteContext struct {
 	DisableConvertersTests  bool
 	DisableAuthTests        bool
 	OLMRunAdvancedTestcases bool
+	//DisableCapacityCheck deploys without checking the cluster has enough allocatable resources for the deployment size
+	DisableCapacityCheck bool
+
+	//GoldenManifests compares the manifests generated by the operator against the golden files
+	GoldenManifests bool
+	//UpdateGoldenManifests regenerates the golden files with the manifests generated by the operator
+	UpdateGoldenManifests bool
+
+	//PodTemplateSupported whether the installed operator supports pod template customizations, probed once per suite, nil until then
+	PodTemplateSupported *bool
+	//OperatorDefaultResources resources of the registry pods of operators not supporting pod template customizations,
+	//read from the first registry deployed, nil until then
+	OperatorDefaultResources *corev1.ResourceRequirements
 
 	SetupSelenium bool
 	SeleniumHost  string
@@ -0,0 +1,78 @@
+package waiter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+//Record states observed during one wait, consecutive identical states are recorded once
+type Record struct {
+	Description string
+	States      []State
+	//Dropped count of the oldest states removed to keep the record bounded
+	Dropped int
+
+	started time.Time
+}
+
+//State one observed state and when it was first seen, relative to the start of the wait
+type State struct {
+	Elapsed time.Duration
+	Summary string
+}
+
+func (r *Record) add(summary string) {
+	if len(r.States) != 0 && r.States[len(r.States)-1].Summary == summary {
+		return
+	}
+	r.States = append(r.States, State{Elapsed: time.Since(r.started), Summary: summary})
+	if len(r.States) > maxStates {
+		r.States = r.States[1:]
+		r.Dropped++
+	}
+}
+
+//Last the latest state observed, empty if none
+func (r *Record) Last() string {
+	if len(r.States) == 0 {
+		return ""
+	}
+	return r.States[len(r.States)-1].Summary
+}
+
+//String one line per state, meant for diagnostics
+func (r *Record) String() string {
+	var b strings.Builder
+	b.WriteString(r.Description + ":")
+	if r.Dropped != 0 {
+		b.WriteString(fmt.Sprintf("\n  ... %v older states", r.Dropped))
+	}
+	for _, s := range r.States {
+		b.WriteString(fmt.Sprintf("\n  +%v %v", s.Elapsed.Round(time.Millisecond), s.Summary))
+	}
+	return b.String()
+}
+
+//LogOnError logs the states observed by a wait that failed, so the logs show how far the awaited objects got
+func LogOnError(record *Record, err error) {
+	if err != nil && record != nil {
+		log.Info("Wait failed", "error", err.Error(), "states", record.String())
+	}
+}
+
+//TimeoutError returned when the predicate doesn't hold before the timeout, it carries the states observed
+type TimeoutError struct {
+	Record  *Record
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("timed out after %v waiting for %v, last state: %v", e.Timeout, e.Record.Description, e.Record.Last())
+}
+
+//IsTimeout true if the error is a TimeoutError
+func IsTimeout(err error) bool {
+	_, isTimeout := err.(*TimeoutError)
+	return isTimeout
+}
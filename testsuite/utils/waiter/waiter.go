@@ -0,0 +1,315 @@
+package waiter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/timeouts"
+)
+
+var log = logf.Log.WithName("waiter")
+
+//maxStates intermediate states kept per wait, the oldest ones are dropped
+const maxStates int = 50
+
+//Source objects and informers waits are based on, the cache of the controller-runtime manager implements it
+type Source interface {
+	client.Reader
+	GetInformer(ctx context.Context, obj client.Object) (cache.Informer, error)
+}
+
+//ObjectPredicate decides if the object reached the awaited state, exists is false while the object is not found
+type ObjectPredicate func(obj client.Object, exists bool) (bool, error)
+
+//ListPredicate decides if the listed objects reached the awaited state
+type ListPredicate func(list client.ObjectList) (bool, error)
+
+//Exists holds once the object exists
+func Exists(obj client.Object, exists bool) (bool, error) {
+	return exists, nil
+}
+
+//Deleted holds once the object is not found
+func Deleted(obj client.Object, exists bool) (bool, error) {
+	return !exists, nil
+}
+
+//Empty holds once no object matches the list options
+func Empty(list client.ObjectList) (bool, error) {
+	return meta.LenList(list) == 0, nil
+}
+
+//Summarizer describes the state of an object recorded while waiting, by default its status
+type Summarizer func(obj runtime.Object) string
+
+//Waiter waits for predicates on objects of any kind, evaluating them as soon as the informers of the source notify a change
+type Waiter struct {
+	source Source
+	scheme *runtime.Scheme
+
+	mu          sync.Mutex
+	dispatchers map[schema.GroupVersionKind]*dispatcher
+}
+
+//NewWaiter creates a waiter on top of the given source, usually the cache of the suite manager
+func NewWaiter(source Source, scheme *runtime.Scheme) *Waiter {
+	return &Waiter{source: source, scheme: scheme, dispatchers: map[schema.GroupVersionKind]*dispatcher{}}
+}
+
+//Wait one wait being configured, see ForObject and ForList
+type Wait struct {
+	waiter    *Waiter
+	timeout   time.Duration
+	summarize Summarizer
+	desc      string
+	triggers  []client.Object
+}
+
+//Timeout how long to wait before failing
+func (w *Waiter) Timeout(timeout time.Duration) *Wait {
+	return &Wait{waiter: w, timeout: timeout, summarize: StatusSummary}
+}
+
+//Summarize replaces the summary recorded for every intermediate state
+func (w *Wait) Summarize(summarize Summarizer) *Wait {
+	w.summarize = summarize
+	return w
+}
+
+//Describe describes what is awaited, used in logs and errors
+func (w *Wait) Describe(desc string) *Wait {
+	w.desc = desc
+	return w
+}
+
+//TriggeredBy evaluates the predicate again on changes of objects of the given kinds in the namespace waited on,
+//for predicates reading other objects, like the deployment of a custom resource
+func (w *Wait) TriggeredBy(objs ...client.Object) *Wait {
+	w.triggers = append(w.triggers, objs...)
+	return w
+}
+
+//ForObject waits until the predicate holds for the object with the given key, obj is left with the last state seen
+func (w *Wait) ForObject(key client.ObjectKey, obj client.Object, predicate ObjectPredicate) (*Record, error) {
+	gvk, err := apiutil.GVKForObject(obj, w.waiter.scheme)
+	if err != nil {
+		return nil, err
+	}
+	desc := w.desc
+	if desc == "" {
+		desc = gvk.Kind + " " + key.String()
+	}
+	return w.run(gvk, obj, desc, key.Namespace, key.Name, func(record *Record) (bool, error) {
+		err := w.waiter.source.Get(context.TODO(), key, obj)
+		exists := err == nil
+		if err != nil && !errors.IsNotFound(err) {
+			return false, err
+		}
+		if exists {
+			record.add(w.summarize(obj))
+		} else {
+			record.add("not found")
+		}
+		return predicate(obj, exists)
+	})
+}
+
+//ForList waits until the predicate holds for the objects matching the list options, list is left with the last objects seen
+func (w *Wait) ForList(list client.ObjectList, predicate ListPredicate, opts ...client.ListOption) (*Record, error) {
+	gvk, err := apiutil.GVKForObject(list, w.waiter.scheme)
+	if err != nil {
+		return nil, err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	obj, err := w.waiter.scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	listOptions := &client.ListOptions{}
+	listOptions.ApplyOptions(opts)
+	desc := w.desc
+	if desc == "" {
+		desc = gvk.Kind + " list in namespace " + listOptions.Namespace
+		if listOptions.LabelSelector != nil {
+			desc += " matching " + listOptions.LabelSelector.String()
+		}
+	}
+	return w.run(gvk, obj.(client.Object), desc, listOptions.Namespace, "", func(record *Record) (bool, error) {
+		if err := w.waiter.source.List(context.TODO(), list, opts...); err != nil {
+			return false, err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return false, err
+		}
+		summaries := make([]string, 0, len(items))
+		for _, item := range items {
+			summaries = append(summaries, objectName(item)+" "+w.summarize(item))
+		}
+		record.add(fmt.Sprintf("%v items [%v]", len(items), strings.Join(summaries, ", ")))
+		return predicate(list)
+	})
+}
+
+//run evaluates the condition once and then on every event of the kind in the namespace, plus every resyncInterval
+func (w *Wait) run(gvk schema.GroupVersionKind, obj client.Object, desc string, namespace string, name string, condition func(record *Record) (bool, error)) (*Record, error) {
+	record := &Record{Description: desc, started: time.Now()}
+	log.Info("Waiting for "+desc, "timeout", w.timeout)
+
+	events := make(chan struct{}, 1)
+	unsubscribe, err := w.waiter.subscribe(gvk, obj, &subscriber{namespace: namespace, name: name, events: events})
+	if err != nil {
+		return record, err
+	}
+	defer unsubscribe()
+	for _, trigger := range w.triggers {
+		triggerGVK, err := apiutil.GVKForObject(trigger, w.waiter.scheme)
+		if err != nil {
+			return record, err
+		}
+		unsubscribe, err := w.waiter.subscribe(triggerGVK, trigger, &subscriber{namespace: namespace, events: events})
+		if err != nil {
+			return record, err
+		}
+		defer unsubscribe()
+	}
+
+	timeout := time.NewTimer(w.timeout)
+	defer timeout.Stop()
+	//predicates are evaluated again after some time without events, covering predicates depending on other objects
+	resync := time.NewTicker(timeouts.Interval(timeouts.LongPollInterval))
+	defer resync.Stop()
+	for {
+		done, err := condition(record)
+		if err != nil {
+			return record, err
+		}
+		if done {
+			return record, nil
+		}
+		select {
+		case <-events:
+		case <-resync.C:
+		case <-timeout.C:
+			return record, &TimeoutError{Record: record, Timeout: w.timeout}
+		}
+	}
+}
+
+//subscribe notifies the subscriber on every change of an object of the kind, returning the function to unsubscribe
+func (w *Waiter) subscribe(gvk schema.GroupVersionKind, obj client.Object, s *subscriber) (func(), error) {
+	d, err := w.dispatcher(gvk, obj)
+	if err != nil {
+		return nil, err
+	}
+	d.add(s)
+	return func() { d.remove(s) }, nil
+}
+
+//dispatcher informers can't remove event handlers, so a single handler per kind fans events out to the active waits
+func (w *Waiter) dispatcher(gvk schema.GroupVersionKind, obj client.Object) (*dispatcher, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if d, exists := w.dispatchers[gvk]; exists {
+		return d, nil
+	}
+	informer, err := w.source.GetInformer(context.TODO(), obj)
+	if err != nil {
+		return nil, err
+	}
+	d := &dispatcher{subscribers: map[*subscriber]bool{}}
+	informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    d.notify,
+		UpdateFunc: func(oldObj, newObj interface{}) { d.notify(newObj) },
+		DeleteFunc: d.notify,
+	})
+	w.dispatchers[gvk] = d
+	return d, nil
+}
+
+type dispatcher struct {
+	mu          sync.Mutex
+	subscribers map[*subscriber]bool
+}
+
+//subscriber receives the events of objects matching namespace and name, empty values match any
+type subscriber struct {
+	namespace string
+	name      string
+	events    chan struct{}
+}
+
+func (d *dispatcher) add(s *subscriber) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.subscribers[s] = true
+}
+
+func (d *dispatcher) remove(s *subscriber) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.subscribers, s)
+}
+
+func (d *dispatcher) notify(obj interface{}) {
+	if tombstone, isTombstone := obj.(toolscache.DeletedFinalStateUnknown); isTombstone {
+		obj = tombstone.Obj
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for s := range d.subscribers {
+		if (s.namespace != "" && s.namespace != accessor.GetNamespace()) || (s.name != "" && s.name != accessor.GetName()) {
+			continue
+		}
+		//a pending notification already triggers a new evaluation
+		select {
+		case s.events <- struct{}{}:
+		default:
+		}
+	}
+}
+
+//StatusSummary resource version and status of the object, as json
+func StatusSummary(obj runtime.Object) string {
+	summary := ""
+	if accessor, err := meta.Accessor(obj); err == nil {
+		summary = "resourceVersion=" + accessor.GetResourceVersion()
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return summary
+	}
+	if status, exists := content["status"]; exists {
+		data, err := json.Marshal(status)
+		if err == nil {
+			summary += " status=" + string(data)
+		}
+	}
+	return summary
+}
+
+func objectName(obj runtime.Object) string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return "?"
+	}
+	return accessor.GetName()
+}
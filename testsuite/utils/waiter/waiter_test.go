@@ -0,0 +1,230 @@
+package waiter
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
+
+	"github.com/Apicurio/apicurio-registry-k8s-tests-e2e/testsuite/utils/timeouts"
+)
+
+//fakeSource fake client backed source, tests notify changes through the informer of each kind
+type fakeSource struct {
+	client.Client
+	mu        sync.Mutex
+	informers map[reflect.Type]*controllertest.FakeInformer
+}
+
+func (s *fakeSource) GetInformer(ctx context.Context, obj client.Object) (cache.Informer, error) {
+	return s.informer(obj), nil
+}
+
+func (s *fakeSource) informer(obj client.Object) *controllertest.FakeInformer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := reflect.TypeOf(obj)
+	if _, exists := s.informers[t]; !exists {
+		s.informers[t] = &controllertest.FakeInformer{Synced: true}
+	}
+	return s.informers[t]
+}
+
+//apply creates or updates the object and notifies the change
+func (s *fakeSource) apply(obj client.Object) error {
+	err := s.Create(context.TODO(), obj)
+	if err != nil {
+		obj.SetResourceVersion("")
+		err = s.Update(context.TODO(), obj)
+	}
+	if err != nil {
+		return err
+	}
+	s.informer(obj).Update(obj, obj)
+	return nil
+}
+
+func (s *fakeSource) remove(obj client.Object) error {
+	if err := s.Delete(context.TODO(), obj); err != nil {
+		return err
+	}
+	s.informer(obj).Delete(obj)
+	return nil
+}
+
+func newFakeWaiter() (*fakeSource, *Waiter) {
+	source := &fakeSource{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(), informers: map[reflect.Type]*controllertest.FakeInformer{}}
+	return source, NewWaiter(source, scheme.Scheme)
+}
+
+var testKey = client.ObjectKey{Namespace: "test", Name: "config"}
+
+func testConfigMap(data string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: testKey.Namespace, Name: testKey.Name, Labels: map[string]string{"app": "test"}}, Data: map[string]string{"value": data}}
+}
+
+func TestReturnsImmediatelyIfThePredicateAlreadyHolds(t *testing.T) {
+	g := NewWithT(t)
+	source, waiter := newFakeWaiter()
+	g.Expect(source.apply(testConfigMap("a"))).To(Succeed())
+	obj := &corev1.ConfigMap{}
+	record, err := waiter.Timeout(time.Second).ForObject(testKey, obj, Exists)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(obj.Data["value"]).To(Equal("a"))
+	g.Expect(record.States).To(HaveLen(1))
+}
+
+func TestReactsToChangesWithoutWaitingForTheResync(t *testing.T) {
+	g := NewWithT(t)
+	source, waiter := newFakeWaiter()
+	go func() {
+		for _, value := range []string{"a", "b", "c"} {
+			time.Sleep(50 * time.Millisecond)
+			if err := source.apply(testConfigMap(value)); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+
+	obj := &corev1.ConfigMap{}
+	started := time.Now()
+	record, err := waiter.Timeout(5*time.Second).Describe("config c").Summarize(func(o runtime.Object) string {
+		return o.(*corev1.ConfigMap).Data["value"]
+	}).ForObject(testKey, obj, func(o client.Object, exists bool) (bool, error) {
+		return exists && o.(*corev1.ConfigMap).Data["value"] == "c", nil
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(time.Since(started)).To(BeNumerically("<", timeouts.Interval(timeouts.LongPollInterval)))
+
+	summaries := []string{}
+	for _, s := range record.States {
+		summaries = append(summaries, s.Summary)
+	}
+	g.Expect(summaries[0]).To(Equal("not found"))
+	g.Expect(summaries[len(summaries)-1]).To(Equal("c"))
+	g.Expect(record.Description).To(Equal("config c"))
+}
+
+func TestWaitsForDeletions(t *testing.T) {
+	g := NewWithT(t)
+	source, waiter := newFakeWaiter()
+	cm := testConfigMap("a")
+	g.Expect(source.apply(cm)).To(Succeed())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		if err := source.remove(cm); err != nil {
+			t.Error(err)
+		}
+	}()
+	_, err := waiter.Timeout(5*time.Second).ForObject(testKey, &corev1.ConfigMap{}, Deleted)
+	g.Expect(err).ToNot(HaveOccurred())
+}
+
+func TestWaitsOnLists(t *testing.T) {
+	g := NewWithT(t)
+	source, waiter := newFakeWaiter()
+	g.Expect(source.apply(testConfigMap("a"))).To(Succeed())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		if err := source.remove(testConfigMap("a")); err != nil {
+			t.Error(err)
+		}
+	}()
+	list := &corev1.ConfigMapList{}
+	record, err := waiter.Timeout(5*time.Second).ForList(list, Empty, client.InNamespace("test"), client.MatchingLabels{"app": "test"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(record.Description).To(Equal("ConfigMap list in namespace test matching app=test"))
+	g.Expect(record.States[0].Summary).To(HavePrefix("1 items [config "))
+	g.Expect(record.Last()).To(Equal("0 items []"))
+}
+
+func TestEvaluatesAgainOnChangesOfTheTriggerKinds(t *testing.T) {
+	g := NewWithT(t)
+	source, waiter := newFakeWaiter()
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		if err := source.apply(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "deployment"}}); err != nil {
+			t.Error(err)
+		}
+	}()
+	started := time.Now()
+	_, err := waiter.Timeout(5*time.Second).TriggeredBy(&appsv1.Deployment{}).ForObject(testKey, &corev1.ConfigMap{}, func(o client.Object, exists bool) (bool, error) {
+		err := source.Get(context.TODO(), client.ObjectKey{Namespace: "test", Name: "deployment"}, &appsv1.Deployment{})
+		return err == nil, nil
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(time.Since(started)).To(BeNumerically("<", timeouts.Interval(timeouts.LongPollInterval)))
+}
+
+func TestIgnoresChangesOfOtherObjects(t *testing.T) {
+	g := NewWithT(t)
+	source, waiter := newFakeWaiter()
+	other := testConfigMap("a")
+	other.Name = "other"
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		if err := source.apply(other); err != nil {
+			t.Error(err)
+		}
+	}()
+	_, err := waiter.Timeout(300*time.Millisecond).ForObject(testKey, &corev1.ConfigMap{}, Exists)
+	g.Expect(IsTimeout(err)).To(BeTrue())
+}
+
+func TestReportsTheStatesObservedOnTimeout(t *testing.T) {
+	g := NewWithT(t)
+	source, waiter := newFakeWaiter()
+	g.Expect(source.apply(testConfigMap("a"))).To(Succeed())
+	_, err := waiter.Timeout(100*time.Millisecond).ForObject(testKey, &corev1.ConfigMap{}, Deleted)
+	g.Expect(IsTimeout(err)).To(BeTrue())
+	timeoutErr := err.(*TimeoutError)
+	g.Expect(err.Error()).To(HavePrefix("timed out after 100ms waiting for ConfigMap test/config, last state: resourceVersion="))
+	g.Expect(timeoutErr.Record.String()).To(ContainSubstring("ConfigMap test/config:\n  +"))
+}
+
+func TestPropagatesPredicateErrors(t *testing.T) {
+	g := NewWithT(t)
+	_, waiter := newFakeWaiter()
+	_, err := waiter.Timeout(time.Second).ForObject(testKey, &corev1.ConfigMap{}, func(o client.Object, exists bool) (bool, error) {
+		return false, fmt.Errorf("broken")
+	})
+	g.Expect(err).To(MatchError("broken"))
+}
+
+func TestRecordsConsecutiveIdenticalStatesOnceAndKeepsABoundedHistory(t *testing.T) {
+	g := NewWithT(t)
+	record := &Record{Description: "test", started: time.Now()}
+	record.add("a")
+	record.add("a")
+	record.add("b")
+	g.Expect(record.States).To(HaveLen(2))
+
+	for i := 0; i < maxStates+5; i++ {
+		record.add(fmt.Sprint(i))
+	}
+	g.Expect(record.States).To(HaveLen(maxStates))
+	g.Expect(record.Dropped).To(Equal(7))
+	g.Expect(record.Last()).To(Equal(fmt.Sprint(maxStates + 4)))
+	g.Expect(record.String()).To(ContainSubstring("... 7 older states"))
+}
+
+func TestSummarizesTheStatusOfObjects(t *testing.T) {
+	g := NewWithT(t)
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "12"}}
+	deployment.Status.AvailableReplicas = 2
+	g.Expect(StatusSummary(deployment)).To(Equal(`resourceVersion=12 status={"availableReplicas":2}`))
+}